@@ -59,6 +59,42 @@ var updateCmd = &cobra.Command{
 	DisableFlagsInUseLine: true,
 }
 
+var cacheCmd = &cobra.Command{
+	Use:                "cache [options] stats|prune|purge|rebuild [paths...]",
+	Short:              "Show, prune, purge or rebuild the report cache",
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(cli.Cache(cmd.Root().Name(), args, cmd.OutOrStdout(), cmd.ErrOrStderr()))
+	},
+}
+
+var diffCmd = &cobra.Command{
+	Use:                "diff [options] <left> <right>",
+	Short:              "Compare two media files or saved JSON/XML reports",
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(cli.Diff(cmd.Root().Name(), args, cmd.OutOrStdout(), cmd.ErrOrStderr()))
+	},
+}
+
+var serveCmd = &cobra.Command{
+	Use:                "serve [options]",
+	Short:              "Serve media analysis over HTTP",
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(cli.Serve(cmd.Root().Name(), args, cmd.OutOrStdout(), cmd.ErrOrStderr()))
+	},
+}
+
+var watchCmd = &cobra.Command{
+	Use:                "watch [options] <dir>",
+	Short:              "Analyze files as they arrive in a directory",
+	DisableFlagParsing: true,
+	Run: func(cmd *cobra.Command, args []string) {
+		os.Exit(cli.Watch(cmd.Root().Name(), args, cmd.OutOrStdout(), cmd.ErrOrStderr()))
+	},
+}
+
 var versionCmd = &cobra.Command{
 	Use:   "version",
 	Short: "Print go-mediainfo version information",
@@ -76,8 +112,12 @@ func init() {
 	rootCmd.SetOut(os.Stdout)
 	rootCmd.SetErr(os.Stderr)
 	rootCmd.SetHelpTemplate(helpTemplate)
+	rootCmd.AddCommand(cacheCmd)
+	rootCmd.AddCommand(diffCmd)
+	rootCmd.AddCommand(serveCmd)
 	rootCmd.AddCommand(updateCmd)
 	rootCmd.AddCommand(versionCmd)
+	rootCmd.AddCommand(watchCmd)
 }
 
 func main() {
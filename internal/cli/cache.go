@@ -0,0 +1,120 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// openCache resolves a --Cache value: "1" (bare flag) uses the default directory, "0" disables
+// caching and anything else names the directory.
+func openCache(value string) (*mediainfo.ReportCache, error) {
+	value = strings.TrimSpace(value)
+	switch value {
+	case "0":
+		return nil, nil
+	case "", "1":
+		value = ""
+	}
+	return mediainfo.OpenReportCache(value)
+}
+
+// Cache manages the report cache: stats, prune, purge and rebuild.
+// args excludes the program and subcommand names; program is used in the help text.
+func Cache(program string, args []string, stdout, stderr io.Writer) int {
+	dir := ""
+	output := "TEXT"
+	opts := mediainfo.AnalyzeOptions{}
+	scan := mediainfo.ScanOptions{}
+	var positional []string
+	for _, arg := range args {
+		normalized := normalizeArg(arg)
+		value, hasValue := valueAfterEqual(arg)
+		switch {
+		case strings.HasPrefix(normalized, "--cachedir=") && hasValue:
+			dir = strings.TrimSpace(value)
+		case strings.HasPrefix(normalized, "--output=") && hasValue:
+			output = strings.ToUpper(strings.TrimSpace(value))
+		case normalized == "--recursive":
+			scan.Recursive = true
+		case strings.HasPrefix(normalized, "--parsespeed=") && hasValue:
+			speed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				fmt.Fprintf(stderr, "invalid %s: %v\n", arg, err)
+				return exitError
+			}
+			opts.ParseSpeed = speed
+			opts.HasParseSpeed = true
+		case normalized == "--help" || normalized == "-h":
+			HelpCache(program, stdout)
+			return exitOK
+		case strings.HasPrefix(normalized, "--") && normalized != "--":
+			fmt.Fprintf(stderr, "unknown cache option: %s\n", arg)
+			return exitError
+		case normalized == "--":
+		default:
+			positional = append(positional, arg)
+		}
+	}
+	if len(positional) == 0 || (output != "TEXT" && output != "JSON") {
+		HelpCache(program, stderr)
+		return exitError
+	}
+	action, paths := strings.ToLower(positional[0]), positional[1:]
+	if (action == "rebuild") != (len(paths) > 0) {
+		HelpCache(program, stderr)
+		return exitError
+	}
+
+	cache, err := mediainfo.OpenReportCache(dir)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	switch action {
+	case "stats":
+		stats, err := cache.Stats()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		if output == "JSON" {
+			data, _ := json.MarshalIndent(stats, "", "  ")
+			fmt.Fprintln(stdout, string(data))
+			return exitOK
+		}
+		fmt.Fprintf(stdout, "%-10s: %s\n", "Directory", stats.Dir)
+		fmt.Fprintf(stdout, "%-10s: %d\n", "Entries", stats.Entries)
+		fmt.Fprintf(stdout, "%-10s: %d bytes\n", "Size", stats.Bytes)
+		fmt.Fprintf(stdout, "%-10s: %d\n", "Stale", stats.Stale)
+	case "prune":
+		removed, err := cache.Prune()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		fmt.Fprintf(stdout, "Removed %d entries\n", removed)
+	case "purge":
+		if err := cache.Purge(); err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+		fmt.Fprintln(stdout, "Cache purged")
+	case "rebuild":
+		stored, err := cache.Rebuild(context.Background(), paths, scan, opts)
+		fmt.Fprintf(stdout, "Stored %d entries\n", stored)
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			return exitError
+		}
+	default:
+		HelpCache(program, stderr)
+		return exitError
+	}
+	return exitOK
+}
@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os"
@@ -39,6 +40,7 @@ func Run(args []string, stdout, stderr io.Writer) int {
 	program := programName(args[0])
 	var opts Options
 	var files []string
+	infoParameters := false
 
 	for i := 1; i < len(args); i++ {
 		original := args[i]
@@ -56,8 +58,7 @@ func Run(args []string, stdout, stderr io.Writer) int {
 		case strings.HasPrefix(normalized, "--help-"):
 			return helpTopic(normalized, program, stdout)
 		case normalized == "--info-parameters":
-			fmt.Fprintln(stdout, mediainfo.InfoParameters())
-			return exitOK
+			infoParameters = true
 		case strings.HasPrefix(normalized, "--language"):
 			if value, ok := valueAfterEqual(original); ok {
 				opts.Language = value
@@ -66,7 +67,7 @@ func Run(args []string, stdout, stderr io.Writer) int {
 			if value, ok := valueAfterEqual(original); ok {
 				opts.Language = value
 			}
-		case strings.HasPrefix(normalized, "--output="):
+		case strings.HasPrefix(normalized, "--output="), strings.HasPrefix(normalized, "--inform="):
 			if value, ok := valueAfterEqual(original); ok {
 				opts.Output = value
 			} else {
@@ -93,6 +94,15 @@ func Run(args []string, stdout, stderr io.Writer) int {
 		}
 	}
 
+	if infoParameters {
+		if strings.EqualFold(opts.Output, "JSON") {
+			fmt.Fprintln(stdout, mediainfo.InfoParametersJSON())
+		} else {
+			fmt.Fprintln(stdout, mediainfo.InfoParameters())
+		}
+		return exitOK
+	}
+
 	if len(files) == 0 {
 		return Usage(program, stdout)
 	}
@@ -101,7 +111,7 @@ func Run(args []string, stdout, stderr io.Writer) int {
 		writeBOM(stdout, stderr)
 	}
 
-	output, filesCount, err := runCore(opts, files)
+	output, filesCount, err := runCore(opts, files, stderr)
 	if err != nil {
 		fmt.Fprintln(stderr, err)
 		return exitError
@@ -199,21 +209,27 @@ func writeLogFile(path, output string, includeBOM bool) error {
 	return os.WriteFile(path, data, 0o644) //nolint:gosec // user-facing output file
 }
 
-func runCore(opts Options, files []string) (string, int, error) {
+func runCore(opts Options, files []string, stderr io.Writer) (string, int, error) {
+	var template *mediainfo.InformTemplate
 	if opts.Output != "" {
 		if strings.Contains(opts.Output, ";") || strings.HasPrefix(strings.ToLower(opts.Output), "file://") {
-			return "", 0, fmt.Errorf("output template not implemented: %s", opts.Output)
-		}
-
-		outputName := strings.ToUpper(strings.TrimSpace(opts.Output))
-		switch outputName {
-		case "TEXT", "JSON", "XML", "OLDXML", "HTML", "CSV", "EBUCORE", "EBUCORE_JSON", "PBCORE", "PBCORE2", "GRAPH_SVG", "GRAPH_DOT":
-		default:
+			parsed, err := loadInformTemplate(opts.Output)
+			if err != nil {
+				return "", 0, err
+			}
+			template = &parsed
+		} else if !mediainfo.IsOutputFormat(opts.Output) {
 			return "", 0, fmt.Errorf("output format not implemented: %s", opts.Output)
 		}
 	}
 
+	translation, err := loadTranslation(opts.Language, stderr)
+	if err != nil {
+		return "", 0, err
+	}
+
 	analyzeOpts := mediainfo.AnalyzeOptions{}
+	scanOpts := mediainfo.ScanOptions{OnError: func(_ string, err error) { fmt.Fprintln(stderr, err) }}
 	for _, opt := range opts.CoreOptions {
 		if strings.EqualFold(opt.Name, "parsespeed") {
 			if value, err := strconv.ParseFloat(strings.TrimSpace(opt.Value), 64); err == nil {
@@ -228,35 +244,141 @@ func runCore(opts Options, files []string) (string, int, error) {
 			analyzeOpts.HasTestContinuousFileNames = true
 			continue
 		}
+		switch strings.ToLower(opt.Name) {
+		case "recursive":
+			scanOpts.Recursive = strings.TrimSpace(opt.Value) != "0"
+		case "maxdepth":
+			value, err := strconv.Atoi(strings.TrimSpace(opt.Value))
+			if err != nil || value < 0 {
+				return "", 0, fmt.Errorf("invalid MaxDepth: %s", opt.Value)
+			}
+			scanOpts.MaxDepth = value
+		case "include":
+			scanOpts.Include = append(scanOpts.Include, splitPatterns(opt.Value)...)
+		case "exclude":
+			scanOpts.Exclude = append(scanOpts.Exclude, splitPatterns(opt.Value)...)
+		case "skipunknown":
+			scanOpts.SkipUnknown = strings.TrimSpace(opt.Value) != "0"
+		case "initsegment":
+			analyzeOpts.InitSegment = strings.TrimSpace(opt.Value)
+		case "cache":
+			cache, err := openCache(opt.Value)
+			if err != nil {
+				return "", 0, err
+			}
+			analyzeOpts.Cache = cache
+		}
 	}
-	reports, count, err := mediainfo.AnalyzeFilesWithOptions(files, analyzeOpts)
-	if err != nil {
-		return "", 0, err
+	// Analyze concurrently; report per-file failures and keep going with the rest.
+	reports := analyzeInputs(context.Background(), files, scanOpts, analyzeOpts, stderr)
+	count := len(reports)
+	if count == 0 {
+		return "", 0, nil
 	}
 
-	if strings.EqualFold(opts.Output, "JSON") {
-		return mediainfo.RenderJSON(reports), count, nil
+	if template != nil {
+		return mediainfo.RenderInform(reports, *template), count, nil
 	}
-	if strings.EqualFold(opts.Output, "XML") || strings.EqualFold(opts.Output, "OLDXML") {
-		return mediainfo.RenderXML(reports), count, nil
+	if opts.Full && (opts.Output == "" || strings.EqualFold(opts.Output, "TEXT")) {
+		return mediainfo.RenderTextFullTranslated(reports, translation), count, nil
 	}
-	if strings.EqualFold(opts.Output, "CSV") {
-		return mediainfo.RenderCSV(reports), count, nil
+	output, err := mediainfo.RenderOutput(opts.Output, reports, translation)
+	return output, count, err
+}
+
+// analyzeInputs analyzes local paths in batches, http(s) URLs through range requests and RAR
+// volume sets ("release.rar" or "release.rar/inner.mkv") in place, keeping the command-line order.
+func analyzeInputs(ctx context.Context, inputs []string, scan mediainfo.ScanOptions, opts mediainfo.AnalyzeOptions, stderr io.Writer) []mediainfo.Report {
+	var reports []mediainfo.Report
+	var local []string
+	flush := func() {
+		for _, result := range mediainfo.AnalyzeBatch(ctx, local, scan, opts, 0) {
+			if result.Err != nil {
+				fmt.Fprintln(stderr, result.Err)
+				continue
+			}
+			reports = append(reports, result.Report)
+		}
+		local = nil
 	}
-	if strings.EqualFold(opts.Output, "EBUCORE") || strings.EqualFold(opts.Output, "EBUCORE_JSON") {
-		return mediainfo.RenderEBUCore(reports), count, nil
+	for _, input := range inputs {
+		var analyze func() (mediainfo.Report, error)
+		if mediainfo.IsURL(input) {
+			analyze = func() (mediainfo.Report, error) {
+				return mediainfo.AnalyzeURL(ctx, input, opts, mediainfo.HTTPReaderOptions{})
+			}
+		} else if archive, inner, ok := rarInput(input); ok {
+			analyze = func() (mediainfo.Report, error) {
+				return mediainfo.AnalyzeRAR(ctx, archive, inner, opts)
+			}
+		} else {
+			local = append(local, input)
+			continue
+		}
+		flush()
+		report, err := analyze()
+		if err != nil {
+			fmt.Fprintln(stderr, err)
+			continue
+		}
+		reports = append(reports, report)
 	}
-	if strings.EqualFold(opts.Output, "PBCORE") || strings.EqualFold(opts.Output, "PBCORE2") {
-		return mediainfo.RenderPBCore(reports), count, nil
+	flush()
+	return reports
+}
+
+// rarInput recognizes a RAR archive argument, optionally followed by "/inner/path".
+func rarInput(input string) (string, string, bool) {
+	if mediainfo.IsRARPath(input) {
+		if info, err := os.Stat(input); err == nil && info.Mode().IsRegular() {
+			return input, "", true
+		}
+		return "", "", false
 	}
-	if strings.EqualFold(opts.Output, "GRAPH_SVG") {
-		return mediainfo.RenderGraphSVG(reports), count, nil
+	if _, err := os.Stat(input); err == nil {
+		return "", "", false
 	}
-	if strings.EqualFold(opts.Output, "GRAPH_DOT") {
-		return mediainfo.RenderGraphDOT(reports), count, nil
+	return mediainfo.SplitRARPath(input)
+}
+
+// loadTranslation resolves --Language: a bundled locale code, "raw", or a file:// translation table.
+// Like MediaInfo, an unknown code falls back to untranslated output; only a file:// table that
+// cannot be read or parsed is an error.
+func loadTranslation(value string, stderr io.Writer) (*mediainfo.Translation, error) {
+	if strings.HasPrefix(strings.ToLower(value), "file://") {
+		data, err := os.ReadFile(value[len("file://"):])
+		if err != nil {
+			return nil, fmt.Errorf("language: %w", err)
+		}
+		return mediainfo.ParseTranslation(data)
+	}
+	translation, err := mediainfo.LoadTranslation(value)
+	if err != nil {
+		fmt.Fprintf(stderr, "%v, using English\n", err)
+		return nil, nil
 	}
-	if strings.EqualFold(opts.Output, "HTML") {
-		return mediainfo.RenderHTML(reports), count, nil
+	return translation, nil
+}
+
+// loadInformTemplate parses an inline "Section;content" template or reads one from a file:// path.
+func loadInformTemplate(value string) (mediainfo.InformTemplate, error) {
+	text := value
+	if strings.HasPrefix(strings.ToLower(value), "file://") {
+		data, err := os.ReadFile(value[len("file://"):])
+		if err != nil {
+			return mediainfo.InformTemplate{}, fmt.Errorf("output template: %w", err)
+		}
+		text = string(data)
+	}
+	return mediainfo.ParseInformTemplate(text)
+}
+
+func splitPatterns(value string) []string {
+	var patterns []string
+	for part := range strings.SplitSeq(value, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			patterns = append(patterns, part)
+		}
 	}
-	return mediainfo.RenderText(reports), count, nil
+	return patterns
 }
@@ -0,0 +1,129 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// Diff exit codes follow diff(1): 0 when the reports match, 1 when they differ, 2 on errors.
+const (
+	exitDiffFound   = 1
+	exitDiffTrouble = 2
+)
+
+// Diff compares two media files or saved JSON/XML reports field by field.
+// args excludes the program and subcommand names; program is used in the help text.
+func Diff(program string, args []string, stdout, stderr io.Writer) int {
+	var paths []string
+	opts := mediainfo.DiffOptions{}
+	output := "TEXT"
+	for _, arg := range args {
+		normalized := normalizeArg(arg)
+		value, hasValue := valueAfterEqual(arg)
+		switch {
+		case strings.HasPrefix(normalized, "--output=") && hasValue:
+			output = strings.ToUpper(strings.TrimSpace(value))
+		case strings.HasPrefix(normalized, "--tolerance=") && hasValue:
+			if err := parseDiffTolerance(value, &opts); err != nil {
+				fmt.Fprintln(stderr, err)
+				return exitDiffTrouble
+			}
+		case strings.HasPrefix(normalized, "--ignore=") && hasValue:
+			opts.Ignore = append(opts.Ignore, splitPatterns(value)...)
+		case normalized == "--help" || normalized == "-h":
+			HelpDiff(program, stdout)
+			return exitOK
+		case strings.HasPrefix(normalized, "--") && normalized != "--":
+			fmt.Fprintf(stderr, "unknown diff option: %s\n", arg)
+			return exitDiffTrouble
+		case normalized == "--":
+		default:
+			paths = append(paths, arg)
+		}
+	}
+	if len(paths) != 2 || (output != "TEXT" && output != "JSON") {
+		HelpDiff(program, stderr)
+		return exitDiffTrouble
+	}
+
+	left, err := loadDiffReport(paths[0])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitDiffTrouble
+	}
+	right, err := loadDiffReport(paths[1])
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitDiffTrouble
+	}
+	result := mediainfo.DiffReports(left, right, opts)
+	result.Left, result.Right = paths[0], paths[1]
+	if output == "JSON" {
+		fmt.Fprint(stdout, mediainfo.RenderDiffJSON(result))
+	} else {
+		fmt.Fprint(stdout, mediainfo.RenderDiffText(result))
+	}
+	if result.Equal() {
+		return exitOK
+	}
+	return exitDiffFound
+}
+
+// parseDiffTolerance accepts "0.01" for every numeric field or "Duration=0.001" for one field.
+func parseDiffTolerance(value string, opts *mediainfo.DiffOptions) error {
+	name, number, perField := strings.Cut(value, "=")
+	if !perField {
+		number, name = name, ""
+	}
+	tolerance, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+	if err != nil || tolerance < 0 {
+		return fmt.Errorf("invalid Tolerance: %s", value)
+	}
+	if name == "" {
+		opts.Tolerance = tolerance
+		return nil
+	}
+	if opts.FieldTolerances == nil {
+		opts.FieldTolerances = map[string]float64{}
+	}
+	opts.FieldTolerances[strings.TrimSpace(name)] = tolerance
+	return nil
+}
+
+// loadDiffReport reads a saved .json/.xml report, or analyzes any other file.
+func loadDiffReport(path string) (mediainfo.Report, error) {
+	if mediainfo.IsURL(path) {
+		return mediainfo.AnalyzeURL(context.Background(), path, mediainfo.AnalyzeOptions{}, mediainfo.HTTPReaderOptions{})
+	}
+	if archive, inner, ok := rarInput(path); ok {
+		return mediainfo.AnalyzeRAR(context.Background(), archive, inner, mediainfo.AnalyzeOptions{})
+	}
+	var parse func([]byte) ([]mediainfo.Report, error)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		parse = mediainfo.ParseJSONReport
+	case ".xml":
+		parse = mediainfo.ParseXMLReport
+	default:
+		return mediainfo.AnalyzeFile(path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return mediainfo.Report{}, err
+	}
+	reports, err := parse(data)
+	if err != nil {
+		return mediainfo.Report{}, fmt.Errorf("%s: %w", path, err)
+	}
+	if len(reports) != 1 {
+		return mediainfo.Report{}, fmt.Errorf("%s: expected one media report, found %d", path, len(reports))
+	}
+	return reports[0], nil
+}
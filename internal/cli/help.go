@@ -15,16 +15,20 @@ func Help(program string, stdout io.Writer) {
 	fmt.Fprintln(stdout, "--Version")
 	fmt.Fprintln(stdout, "                    Display version information and exit")
 	fmt.Fprintln(stdout, "--Help-Output")
-	fmt.Fprintln(stdout, "                    Display help for Output= option (templates are not implemented)")
+	fmt.Fprintln(stdout, "                    Display help for Output= option")
 	fmt.Fprintln(stdout, "--Help-AnOption")
 	fmt.Fprintln(stdout, "                    Display help for \"AnOption\" (not implemented)")
 	fmt.Fprintln(stdout, "")
 	fmt.Fprintln(stdout, "--Full, -f")
-	fmt.Fprintln(stdout, "                    Reserved (currently no-op)")
+	fmt.Fprintln(stdout, "                    Full information Display (all internal tags)")
 	fmt.Fprintln(stdout, "--Output=TEXT|JSON|XML|OLDXML|HTML|CSV|EBUCore|EBUCore_JSON|PBCore|PBCore2|Graph_Svg|Graph_Dot")
-	fmt.Fprintln(stdout, "                    Select output format (templates are not implemented)")
+	fmt.Fprintln(stdout, "                    Select output format")
+	fmt.Fprintln(stdout, "--Output=\"Section;Template\", --Output=file://path")
+	fmt.Fprintln(stdout, "                    Render with a MediaInfo template (--Inform=... is an alias)")
 	fmt.Fprintln(stdout, "--Language=raw")
 	fmt.Fprintln(stdout, "                    Display non-translated unique identifiers (recommended for parity)")
+	fmt.Fprintln(stdout, "--Language=de|fr|es, --Language=file://path")
+	fmt.Fprintln(stdout, "                    Translate text and HTML output (bundled locale or \"English;Translation\" file)")
 	fmt.Fprintln(stdout, "-lang=raw")
 	fmt.Fprintln(stdout, "                    Alias for --Language=raw")
 	fmt.Fprintln(stdout, "--LogFile=...")
@@ -35,13 +39,31 @@ func Help(program string, stdout io.Writer) {
 	fmt.Fprintln(stdout, "                    Analysis speed/accuracy tradeoff (default 0.5; parity: 0.5)")
 	fmt.Fprintln(stdout, "--File_TestContinuousFileNames=0|1")
 	fmt.Fprintln(stdout, "                    Enable MediaInfo-style \"continuous filenames\" probing (default 0)")
+	fmt.Fprintln(stdout, "--Recursive")
+	fmt.Fprintln(stdout, "                    Also analyze files in subdirectories of directory arguments")
+	fmt.Fprintln(stdout, "--MaxDepth=N")
+	fmt.Fprintln(stdout, "                    Limit --Recursive to N directory levels (default 0 = unlimited)")
+	fmt.Fprintln(stdout, "--Include=*.mkv,*.mp4")
+	fmt.Fprintln(stdout, "                    Only analyze directory files matching these patterns")
+	fmt.Fprintln(stdout, "--Exclude=*.nfo,*.txt")
+	fmt.Fprintln(stdout, "                    Skip directory files matching these patterns")
+	fmt.Fprintln(stdout, "--SkipUnknown")
+	fmt.Fprintln(stdout, "                    Skip directory files with an unrecognized format")
+	fmt.Fprintln(stdout, "--Cache, --Cache=/path/to/dir")
+	fmt.Fprintln(stdout, "                    Reuse reports of unchanged files from an on-disk cache")
+	fmt.Fprintln(stdout, "--InitSegment=init.mp4")
+	fmt.Fprintln(stdout, "                    Initialization segment for fragmented MP4 media segments (.m4s)")
 	fmt.Fprintln(stdout, "--Info-Parameters")
-	fmt.Fprintln(stdout, "                    Display list of inform= parameters")
+	fmt.Fprintln(stdout, "                    Display list of inform= parameters (add --Output=JSON for JSON)")
 	fmt.Fprintln(stdout, "")
 	fmt.Fprintln(stdout, "Commands:")
+	fmt.Fprintln(stdout, "cache                Show, prune, purge or rebuild the report cache")
 	fmt.Fprintln(stdout, "completion           Generate the autocompletion script for the specified shell")
+	fmt.Fprintln(stdout, "diff                 Compare two media files or saved JSON/XML reports")
 	fmt.Fprintln(stdout, "help                 Help about any command")
+	fmt.Fprintln(stdout, "serve                Serve media analysis over HTTP")
 	fmt.Fprintln(stdout, "version              Print go-mediainfo version information")
+	fmt.Fprintln(stdout, "watch                Analyze files as they arrive in a directory")
 	fmt.Fprintln(stdout, "update               Update mediainfo to latest version (release builds only)")
 }
 
@@ -57,10 +79,100 @@ func HelpOutput(program string, stdout io.Writer) {
 	fmt.Fprintln(stdout, "Supported formats:")
 	fmt.Fprintln(stdout, "TEXT, JSON, XML, OLDXML, HTML, CSV, EBUCore, EBUCore_JSON, PBCore, PBCore2, Graph_Svg, Graph_Dot")
 	fmt.Fprintln(stdout, "")
-	fmt.Fprintln(stdout, "Note: MediaInfo output templates (e.g. \"Video;%AspectRatio%\" or \"file://...\") are not implemented.")
+	fmt.Fprintln(stdout, "Templates:")
+	fmt.Fprintf(stdout, "Usage: \"%s --Output=\"Video;%%Width%%x%%Height%%\\n\" FileName\"\n", program)
+	fmt.Fprintf(stdout, "Usage: \"%s --Output=file://template.txt FileName\"\n", program)
+	fmt.Fprintln(stdout, "One \"Section;content\" per line. Sections: General, Video, Audio, Text, Other, Image, Menu")
+	fmt.Fprintln(stdout, "(plus _Begin/_Middle/_End), File_Begin, File_End, Page_Begin, Page_Middle, Page_End.")
+	fmt.Fprintf(stdout, "%%Field%% is the raw value, %%Field/String%% the display value, $if(%%Field%%,then,else)\n")
+	fmt.Fprintln(stdout, "selects text and [...] is dropped when a field inside it is empty.")
 }
 
 func Usage(program string, stdout io.Writer) int {
 	HelpNothing(program, stdout)
 	return exitError
 }
+
+func HelpDiff(program string, stdout io.Writer) {
+	fmt.Fprintf(stdout, "Usage: \"%s diff [-Options...] Left Right\"\n", program)
+	fmt.Fprintln(stdout, "Compare two media files or saved MediaInfo .json/.xml reports field by field.")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "--Output=TEXT|JSON")
+	fmt.Fprintln(stdout, "                    Select output format")
+	fmt.Fprintln(stdout, "--Tolerance=0.01, --Tolerance=Field=0.001")
+	fmt.Fprintln(stdout, "                    Relative tolerance for numeric values (all fields or one field)")
+	fmt.Fprintln(stdout, "--Ignore=File_Modified_Date,File_Modified_Date_Local")
+	fmt.Fprintln(stdout, "                    Skip these fields")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "Exit status: 0 when equal, 1 when different, 2 on errors.")
+}
+
+func HelpServe(program string, stdout io.Writer) {
+	fmt.Fprintf(stdout, "Usage: \"%s serve [-Options...]\"\n", program)
+	fmt.Fprintln(stdout, "Serve media analysis over HTTP.")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "--Listen=127.0.0.1:8080")
+	fmt.Fprintln(stdout, "                    Address to listen on")
+	fmt.Fprintln(stdout, "--AllowedRoot=/data/downloads,/media")
+	fmt.Fprintln(stdout, "                    Directories path requests may read (path analysis is off without one)")
+	fmt.Fprintln(stdout, "--Concurrency=4")
+	fmt.Fprintln(stdout, "                    Maximum simultaneous analyses (default: number of CPUs)")
+	fmt.Fprintln(stdout, "--Timeout=60s")
+	fmt.Fprintln(stdout, "                    Per-request time limit, including the wait for a free slot")
+	fmt.Fprintln(stdout, "--MaxUpload=1073741824")
+	fmt.Fprintln(stdout, "                    Largest accepted upload, in bytes")
+	fmt.Fprintln(stdout, "--ParseSpeed=0.5")
+	fmt.Fprintln(stdout, "                    Parse speed passed to every analysis")
+	fmt.Fprintln(stdout, "--Cache, --Cache=/path/to/dir")
+	fmt.Fprintln(stdout, "                    Reuse reports of unchanged files from an on-disk cache")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "Endpoints:")
+	fmt.Fprintln(stdout, "POST /analyze       {\"path\": \"...\", \"output\": \"JSON\"} or an uploaded body (?output=&name=)")
+	fmt.Fprintln(stdout, "GET  /healthz       Liveness probe")
+	fmt.Fprintln(stdout, "GET  /metrics       Prometheus metrics")
+}
+
+func HelpWatch(program string, stdout io.Writer) {
+	fmt.Fprintf(stdout, "Usage: \"%s watch [-Options...] Directory\"\n", program)
+	fmt.Fprintln(stdout, "Analyze files once they stop changing, save their reports and notify a webhook.")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "--StableFor=5s")
+	fmt.Fprintln(stdout, "                    How long size and modification time must stay unchanged")
+	fmt.Fprintln(stdout, "--Interval=1s")
+	fmt.Fprintln(stdout, "                    Polling period")
+	fmt.Fprintln(stdout, "--Existing")
+	fmt.Fprintln(stdout, "                    Also process files present when watching starts")
+	fmt.Fprintln(stdout, "--Format=MPEG-4,Matroska")
+	fmt.Fprintln(stdout, "                    Only process these detected container formats")
+	fmt.Fprintln(stdout, "--Recursive, --MaxDepth=N, --Include=*.mkv, --Exclude=*sample*")
+	fmt.Fprintln(stdout, "                    Select files as for directory arguments")
+	fmt.Fprintln(stdout, "--Output=JSON")
+	fmt.Fprintln(stdout, "                    Report format (any --Output name)")
+	fmt.Fprintln(stdout, "--OutputDir=/path/to/reports")
+	fmt.Fprintln(stdout, "                    Save reports here instead of next to the media (<file>.mediainfo.<ext>)")
+	fmt.Fprintln(stdout, "--NoReports")
+	fmt.Fprintln(stdout, "                    Do not save reports")
+	fmt.Fprintln(stdout, "--Webhook=https://example.com/hook")
+	fmt.Fprintln(stdout, "                    POST each JSON report to this URL")
+	fmt.Fprintln(stdout, "--WebhookRetries=3")
+	fmt.Fprintln(stdout, "                    Retries for failed deliveries (429, 5xx, network errors)")
+	fmt.Fprintln(stdout, "--Cache, --Cache=/path/to/dir")
+	fmt.Fprintln(stdout, "                    Reuse reports of unchanged files from an on-disk cache")
+}
+
+func HelpCache(program string, stdout io.Writer) {
+	fmt.Fprintf(stdout, "Usage: \"%s cache [-Options...] stats|prune|purge|rebuild [Paths...]\"\n", program)
+	fmt.Fprintln(stdout, "Manage the report cache used by --Cache.")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "stats               Count entries, size and entries from other versions")
+	fmt.Fprintln(stdout, "prune               Remove entries from other versions or for changed/missing files")
+	fmt.Fprintln(stdout, "purge               Remove every entry")
+	fmt.Fprintln(stdout, "rebuild Paths...    Re-analyze files or directories and replace their entries")
+	fmt.Fprintln(stdout, "")
+	fmt.Fprintln(stdout, "--CacheDir=/path/to/dir")
+	fmt.Fprintln(stdout, "                    Cache directory (default: the user cache directory)")
+	fmt.Fprintln(stdout, "--Output=TEXT|JSON")
+	fmt.Fprintln(stdout, "                    Format for stats")
+	fmt.Fprintln(stdout, "--Recursive, --ParseSpeed=0.5")
+	fmt.Fprintln(stdout, "                    Analysis options for rebuild")
+}
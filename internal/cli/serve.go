@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/autobrr/go-mediainfo/internal/server"
+)
+
+const defaultListen = "127.0.0.1:8080"
+
+// Serve runs the HTTP analysis server until interrupted.
+// args excludes the program and subcommand names; program is used in the help text.
+func Serve(program string, args []string, stdout, stderr io.Writer) int {
+	listen := defaultListen
+	cfg := server.Config{}
+	for _, arg := range args {
+		normalized := normalizeArg(arg)
+		value, hasValue := valueAfterEqual(arg)
+		var err error
+		switch {
+		case strings.HasPrefix(normalized, "--listen=") && hasValue:
+			listen = strings.TrimSpace(value)
+		case strings.HasPrefix(normalized, "--allowedroot=") && hasValue:
+			cfg.AllowedRoots = append(cfg.AllowedRoots, splitPatterns(value)...)
+		case strings.HasPrefix(normalized, "--concurrency=") && hasValue:
+			cfg.MaxConcurrent, err = strconv.Atoi(strings.TrimSpace(value))
+			if err == nil && cfg.MaxConcurrent <= 0 {
+				err = errors.New("must be positive")
+			}
+		case strings.HasPrefix(normalized, "--timeout=") && hasValue:
+			cfg.Timeout, err = parsePositiveDuration(strings.TrimSpace(value))
+		case strings.HasPrefix(normalized, "--maxupload=") && hasValue:
+			cfg.MaxUploadBytes, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+			if err == nil && cfg.MaxUploadBytes <= 0 {
+				err = errors.New("must be positive")
+			}
+		case normalized == "--cache" || (strings.HasPrefix(normalized, "--cache=") && hasValue):
+			cfg.Analyze.Cache, err = openCache(value)
+		case strings.HasPrefix(normalized, "--parsespeed=") && hasValue:
+			cfg.Analyze.ParseSpeed, err = strconv.ParseFloat(strings.TrimSpace(value), 64)
+			cfg.Analyze.HasParseSpeed = err == nil
+		case normalized == "--help" || normalized == "-h":
+			HelpServe(program, stdout)
+			return exitOK
+		default:
+			fmt.Fprintf(stderr, "unknown serve option: %s\n", arg)
+			return exitError
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid %s: %v\n", arg, err)
+			return exitError
+		}
+	}
+
+	handler, err := server.New(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	httpServer := &http.Server{Addr: listen, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	errs := make(chan error, 1)
+	go func() { errs <- httpServer.ListenAndServe() }()
+	fmt.Fprintf(stderr, "listening on %s\n", listen)
+
+	select {
+	case err = <-errs:
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		err = httpServer.Shutdown(shutdownCtx)
+	}
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
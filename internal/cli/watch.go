@@ -0,0 +1,108 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/autobrr/go-mediainfo/internal/watch"
+)
+
+// Watch analyzes files as they finish arriving in a directory until interrupted.
+// args excludes the program and subcommand names; program is used in the help text.
+func Watch(program string, args []string, stdout, stderr io.Writer) int {
+	cfg := watch.Config{}
+	var dirs []string
+	for _, arg := range args {
+		normalized := normalizeArg(arg)
+		value, hasValue := valueAfterEqual(arg)
+		value = strings.TrimSpace(value)
+		var err error
+		switch {
+		case strings.HasPrefix(normalized, "--stablefor=") && hasValue:
+			cfg.StableFor, err = parsePositiveDuration(value)
+		case strings.HasPrefix(normalized, "--interval=") && hasValue:
+			cfg.Interval, err = parsePositiveDuration(value)
+		case strings.HasPrefix(normalized, "--output=") && hasValue:
+			cfg.Output = value
+		case strings.HasPrefix(normalized, "--outputdir=") && hasValue:
+			cfg.OutputDir = value
+		case normalized == "--noreports":
+			cfg.NoReports = true
+		case strings.HasPrefix(normalized, "--webhook=") && hasValue:
+			cfg.WebhookURL = value
+		case strings.HasPrefix(normalized, "--webhookretries=") && hasValue:
+			cfg.WebhookRetries, err = strconv.Atoi(value)
+			if err == nil && cfg.WebhookRetries == 0 {
+				cfg.WebhookRetries = -1
+			}
+		case strings.HasPrefix(normalized, "--format=") && hasValue:
+			cfg.Formats = append(cfg.Formats, splitPatterns(value)...)
+		case normalized == "--recursive":
+			cfg.Scan.Recursive = true
+		case strings.HasPrefix(normalized, "--maxdepth=") && hasValue:
+			cfg.Scan.MaxDepth, err = strconv.Atoi(value)
+		case strings.HasPrefix(normalized, "--include=") && hasValue:
+			cfg.Scan.Include = append(cfg.Scan.Include, splitPatterns(value)...)
+		case strings.HasPrefix(normalized, "--exclude=") && hasValue:
+			cfg.Scan.Exclude = append(cfg.Scan.Exclude, splitPatterns(value)...)
+		case normalized == "--existing":
+			cfg.Existing = true
+		case normalized == "--cache" || (strings.HasPrefix(normalized, "--cache=") && hasValue):
+			cfg.Analyze.Cache, err = openCache(value)
+		case strings.HasPrefix(normalized, "--parsespeed=") && hasValue:
+			cfg.Analyze.ParseSpeed, err = strconv.ParseFloat(value, 64)
+			cfg.Analyze.HasParseSpeed = err == nil
+		case normalized == "--help" || normalized == "-h":
+			HelpWatch(program, stdout)
+			return exitOK
+		case strings.HasPrefix(normalized, "--") && normalized != "--":
+			fmt.Fprintf(stderr, "unknown watch option: %s\n", arg)
+			return exitError
+		case normalized == "--":
+		default:
+			dirs = append(dirs, arg)
+		}
+		if err != nil {
+			fmt.Fprintf(stderr, "invalid %s: %v\n", arg, err)
+			return exitError
+		}
+	}
+	if len(dirs) != 1 {
+		HelpWatch(program, stderr)
+		return exitError
+	}
+	cfg.Dir = dirs[0]
+	cfg.Logf = func(format string, args ...any) {
+		fmt.Fprintf(stderr, time.Now().Format(time.DateTime)+" "+format+"\n", args...)
+	}
+
+	watcher, err := watch.New(cfg)
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	cfg.Logf("watching %s", cfg.Dir)
+	if err := watcher.Run(ctx); err != nil {
+		fmt.Fprintln(stderr, err)
+		return exitError
+	}
+	return exitOK
+}
+
+func parsePositiveDuration(value string) (time.Duration, error) {
+	d, err := time.ParseDuration(value)
+	if err == nil && d <= 0 {
+		err = errors.New("must be positive")
+	}
+	return d, err
+}
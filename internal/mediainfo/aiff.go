@@ -0,0 +1,296 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// aiffCodec describes how an AIFF-C compression type is reported.
+type aiffCodec struct {
+	format     string
+	profile    string
+	endianness string
+	sign       string
+	bitDepth   int // stored bits per sample when it differs from the COMM sample size
+}
+
+var aiffCodecs = map[string]aiffCodec{
+	"NONE": {format: "PCM", endianness: "Big", sign: "Signed"},
+	"twos": {format: "PCM", endianness: "Big", sign: "Signed"},
+	"sowt": {format: "PCM", endianness: "Little", sign: "Signed"},
+	"raw ": {format: "PCM", endianness: "Big", sign: "Unsigned", bitDepth: 8},
+	"in24": {format: "PCM", endianness: "Big", sign: "Signed", bitDepth: 24},
+	"42ni": {format: "PCM", endianness: "Little", sign: "Signed", bitDepth: 24},
+	"in32": {format: "PCM", endianness: "Big", sign: "Signed", bitDepth: 32},
+	"23ni": {format: "PCM", endianness: "Little", sign: "Signed", bitDepth: 32},
+	"fl32": {format: "PCM", profile: "Float", endianness: "Big", bitDepth: 32},
+	"FL32": {format: "PCM", profile: "Float", endianness: "Big", bitDepth: 32},
+	"fl64": {format: "PCM", profile: "Float", endianness: "Big", bitDepth: 64},
+	"FL64": {format: "PCM", profile: "Float", endianness: "Big", bitDepth: 64},
+	"ulaw": {format: "ADPCM", profile: "U-Law", bitDepth: 8},
+	"ULAW": {format: "ADPCM", profile: "U-Law", bitDepth: 8},
+	"alaw": {format: "ADPCM", profile: "A-Law", bitDepth: 8},
+	"ALAW": {format: "ADPCM", profile: "A-Law", bitDepth: 8},
+	"ima4": {format: "ADPCM", profile: "IMA", bitDepth: 4},
+}
+
+// QuickTime IMA ADPCM packs 64 samples of one channel into 34 bytes, and AIFF-C counts
+// those packets (not samples) in the COMM frame count.
+const (
+	aiffIMA4PacketBytes   = 34
+	aiffIMA4PacketSamples = 64
+)
+
+func ParseAIFF(file io.ReadSeeker, size int64) (ContainerInfo, []Stream, []Field, map[string]string, bool) {
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return ContainerInfo{}, nil, nil, nil, false
+	}
+
+	var header [12]byte
+	if _, err := io.ReadFull(file, header[:]); err != nil {
+		return ContainerInfo{}, nil, nil, nil, false
+	}
+	form := string(header[8:12])
+	if string(header[0:4]) != "FORM" || (form != "AIFF" && form != "AIFC") {
+		return ContainerInfo{}, nil, nil, nil, false
+	}
+	isAIFC := form == "AIFC"
+
+	var (
+		channels    uint16
+		frames      uint32
+		sampleSize  uint16
+		sampleRate  float64
+		compression = "NONE"
+		commFound   bool
+		dataSize    int64
+		tags        = map[string]string{}
+		id3         id3v2Data
+	)
+
+	offset := int64(12)
+	for size <= 0 || offset+8 <= size {
+		var chunkHeader [8]byte
+		if _, err := io.ReadFull(file, chunkHeader[:]); err != nil {
+			break
+		}
+		offset += 8
+		chunkID := string(chunkHeader[0:4])
+		chunkSize := int64(binary.BigEndian.Uint32(chunkHeader[4:8]))
+		next := offset + chunkSize + chunkSize%2
+		// Tag chunks are read whole, so only when they are small and actually present.
+		fits := size <= 0 || offset+chunkSize <= size
+
+		switch chunkID {
+		case "COMM":
+			if chunkSize < 18 {
+				return ContainerInfo{}, nil, nil, nil, false
+			}
+			payload := make([]byte, min(chunkSize, 256))
+			if _, err := io.ReadFull(file, payload); err != nil {
+				return ContainerInfo{}, nil, nil, nil, false
+			}
+			channels = binary.BigEndian.Uint16(payload[0:2])
+			frames = binary.BigEndian.Uint32(payload[2:6])
+			sampleSize = binary.BigEndian.Uint16(payload[6:8])
+			sampleRate = float80(payload[8:18])
+			if isAIFC && len(payload) >= 22 {
+				compression = string(payload[18:22])
+			}
+			commFound = true
+		case "SSND":
+			// SSND: offset and blockSize words, then offset bytes of padding before the samples.
+			var ssnd [8]byte
+			if chunkSize >= 8 {
+				if _, err := io.ReadFull(file, ssnd[:]); err != nil {
+					return ContainerInfo{}, nil, nil, nil, false
+				}
+				dataSize = chunkSize - 8 - int64(binary.BigEndian.Uint32(ssnd[0:4]))
+			}
+			if size > 0 && next > size {
+				// Truncated or still-growing file: only what is on disk is audio.
+				dataSize -= next - size
+			}
+			dataSize = max(dataSize, 0)
+		case "NAME", "AUTH", "(c) ", "ANNO":
+			if chunkSize > 64<<10 || !fits {
+				break
+			}
+			payload := make([]byte, chunkSize)
+			if _, err := io.ReadFull(file, payload); err != nil {
+				return ContainerInfo{}, nil, nil, nil, false
+			}
+			value := strings.TrimSpace(strings.TrimRight(string(payload), "\x00"))
+			if value == "" {
+				break
+			}
+			key := map[string]string{"NAME": "Title", "AUTH": "Performer", "(c) ": "Copyright", "ANNO": "Comment"}[chunkID]
+			if tags[key] == "" {
+				tags[key] = value
+			} else if chunkID == "ANNO" {
+				tags[key] += " / " + value
+			}
+		case "ID3 ", "id3 ":
+			if chunkSize > 16<<20 || !fits {
+				break
+			}
+			payload := make([]byte, chunkSize)
+			if _, err := io.ReadFull(file, payload); err != nil {
+				return ContainerInfo{}, nil, nil, nil, false
+			}
+			if parsed, ok := parseID3v2(bytes.NewReader(payload)); ok {
+				id3 = parsed
+			}
+		}
+
+		if _, err := file.Seek(next, io.SeekStart); err != nil {
+			break
+		}
+		offset = next
+	}
+
+	if !commFound {
+		return ContainerInfo{}, nil, nil, nil, false
+	}
+
+	codec, known := aiffCodecs[compression]
+	if !known {
+		codec = aiffCodec{format: strings.TrimSpace(compression)}
+		if codec.format == "" {
+			codec.format = "Unknown"
+		}
+	}
+	bitDepth := int(sampleSize)
+	if codec.bitDepth > 0 {
+		bitDepth = codec.bitDepth
+	}
+
+	samples := int64(frames)
+	var bitrate float64
+	switch {
+	case compression == "ima4":
+		samples *= aiffIMA4PacketSamples
+		bitrate = sampleRate * float64(channels) * aiffIMA4PacketBytes * 8 / aiffIMA4PacketSamples
+	case known:
+		bitrate = sampleRate * float64(channels) * float64((bitDepth+7)/8*8)
+	}
+	duration := 0.0
+	if sampleRate > 0 {
+		duration = float64(samples) / sampleRate
+	}
+
+	mode := "Variable"
+	if bitrate > 0 {
+		mode = "Constant"
+	}
+	info := ContainerInfo{
+		DurationSeconds: duration,
+		BitrateMode:     mode,
+	}
+	if size > 0 && dataSize > 0 && dataSize <= size {
+		info.StreamOverheadBytes = size - dataSize
+	}
+
+	streamFields := []Field{
+		{Name: "Format", Value: codec.format},
+	}
+	if codec.profile != "" {
+		streamFields = append(streamFields, Field{Name: "Format profile", Value: codec.profile})
+	}
+	if isAIFC {
+		streamFields = append(streamFields, Field{Name: "Codec ID", Value: strings.TrimSpace(compression)})
+	}
+	if channels > 0 {
+		streamFields = append(streamFields, Field{Name: "Channel(s)", Value: formatChannels(uint64(channels))})
+	}
+	if sampleRate > 0 {
+		streamFields = append(streamFields, Field{Name: "Sampling rate", Value: formatSampleRate(sampleRate)})
+	}
+	if bitDepth > 0 && bitDepth <= math.MaxUint8 {
+		streamFields = append(streamFields, Field{Name: "Bit depth", Value: formatBitDepth(uint8(bitDepth))})
+	}
+	if codec.endianness != "" && bitDepth > 8 {
+		streamFields = append(streamFields, Field{Name: "Format settings, Endianness", Value: codec.endianness})
+	}
+	if codec.sign != "" {
+		streamFields = append(streamFields, Field{Name: "Format settings, Sign", Value: codec.sign})
+	}
+	streamFields = addStreamDuration(streamFields, duration)
+	streamFields = append(streamFields, Field{Name: "Bit rate mode", Value: mode})
+	if bitrate > 0 {
+		streamFields = append(streamFields, Field{Name: "Bit rate", Value: formatBitrate(bitrate)})
+	}
+
+	streamJSON := map[string]string{}
+	if dataSize > 0 {
+		streamJSON["StreamSize"] = strconv.FormatInt(dataSize, 10)
+	}
+	if samples > 0 {
+		streamJSON["SamplingCount"] = strconv.FormatInt(samples, 10)
+	}
+
+	generalFields := []Field{}
+	if isAIFC {
+		generalFields = append(generalFields, Field{Name: "Format profile", Value: "AIFF-C"})
+	}
+	generalJSON := map[string]string{}
+	if info.StreamOverheadBytes > 0 {
+		generalJSON["StreamSize"] = strconv.FormatInt(info.StreamOverheadBytes, 10)
+	}
+	for key, value := range tags {
+		generalJSON[key] = value
+	}
+	if id3.Text != nil {
+		// Native text chunks win; ID3 fills in the rest.
+		applyID3TextToGeneralJSON(generalJSON, nil, id3.Text)
+	}
+	if len(id3.Pictures) > 0 {
+		pic := id3.Pictures[0]
+		generalJSON["Cover"] = "Yes"
+		switch pic.Type {
+		case 0x03:
+			generalJSON["Cover_Type"] = "Cover (front)"
+		case 0x04:
+			generalJSON["Cover_Type"] = "Cover (back)"
+		}
+		if pic.Description != "" {
+			generalJSON["Cover_Description"] = pic.Description
+		}
+		if pic.MIME != "" {
+			generalJSON["Cover_Mime"] = pic.MIME
+		}
+	}
+
+	streams := []Stream{{
+		Kind:                StreamAudio,
+		Fields:              streamFields,
+		JSON:                streamJSON,
+		JSONSkipStreamOrder: true,
+		JSONSkipComputed:    true,
+	}}
+	return info, streams, generalFields, generalJSON, true
+}
+
+// float80 decodes the IEEE 754 80-bit extended value AIFF uses for the sample rate.
+func float80(b []byte) float64 {
+	if len(b) < 10 {
+		return 0
+	}
+	exponent := int(binary.BigEndian.Uint16(b[0:2]) & 0x7FFF)
+	mantissa := binary.BigEndian.Uint64(b[2:10])
+	if exponent == 0 && mantissa == 0 || exponent == 0x7FFF {
+		return 0
+	}
+	value := math.Ldexp(float64(mantissa), exponent-16383-63)
+	if math.IsInf(value, 0) {
+		return 0
+	}
+	if b[0]&0x80 != 0 {
+		value = -value
+	}
+	return value
+}
@@ -0,0 +1,207 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+)
+
+type aiffChunk struct {
+	id   string
+	data []byte
+}
+
+func buildAIFF(form string, chunks ...aiffChunk) []byte {
+	var body bytes.Buffer
+	body.WriteString(form)
+	for _, chunk := range chunks {
+		body.WriteString(chunk.id)
+		binary.Write(&body, binary.BigEndian, uint32(len(chunk.data)))
+		body.Write(chunk.data)
+		if len(chunk.data)%2 == 1 {
+			body.WriteByte(0)
+		}
+	}
+	var out bytes.Buffer
+	out.WriteString("FORM")
+	binary.Write(&out, binary.BigEndian, uint32(body.Len()))
+	out.Write(body.Bytes())
+	return out.Bytes()
+}
+
+// aiffCOMM builds a COMM chunk; compression is only written for AIFF-C.
+func aiffCOMM(channels uint16, frames uint32, bits uint16, rate float64, compression string) aiffChunk {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.BigEndian, channels)
+	binary.Write(&buf, binary.BigEndian, frames)
+	binary.Write(&buf, binary.BigEndian, bits)
+	frac, exp := math.Frexp(rate)
+	binary.Write(&buf, binary.BigEndian, uint16(exp-1+16383))
+	binary.Write(&buf, binary.BigEndian, uint64(frac*(1<<64)))
+	if compression != "" {
+		buf.WriteString(compression)
+		buf.Write([]byte{0}) // empty pascal string name
+		buf.Write([]byte{0})
+	}
+	return aiffChunk{id: "COMM", data: buf.Bytes()}
+}
+
+func aiffSSND(offset uint32, samples int) aiffChunk {
+	data := make([]byte, 8+int(offset)+samples)
+	binary.BigEndian.PutUint32(data[0:4], offset)
+	return aiffChunk{id: "SSND", data: data}
+}
+
+// aiffID3 builds an ID3v2.3 tag with Latin-1 text frames.
+func aiffID3(frames ...string) []byte {
+	var body bytes.Buffer
+	for i := 0; i+1 < len(frames); i += 2 {
+		body.WriteString(frames[i])
+		binary.Write(&body, binary.BigEndian, uint32(1+len(frames[i+1])))
+		body.Write([]byte{0, 0, 0})
+		body.WriteString(frames[i+1])
+	}
+	size := body.Len()
+	header := []byte{'I', 'D', '3', 3, 0, 0, byte(size >> 21 & 0x7F), byte(size >> 14 & 0x7F), byte(size >> 7 & 0x7F), byte(size & 0x7F)}
+	return append(header, body.Bytes()...)
+}
+
+func TestFloat80(t *testing.T) {
+	for _, rate := range []float64{8000, 22050, 44100, 48000, 96000, 176400} {
+		chunk := aiffCOMM(1, 0, 16, rate, "")
+		if got := float80(chunk.data[8:18]); got != rate {
+			t.Fatalf("float80=%v, want %v", got, rate)
+		}
+	}
+}
+
+func TestParseAIFFPCM(t *testing.T) {
+	id3 := aiffID3("TALB", "Sessions", "TPE1", "From ID3")
+	data := buildAIFF("AIFF",
+		aiffCOMM(2, 44100, 16, 44100, ""),
+		aiffChunk{id: "NAME", data: []byte("Take 3")},
+		aiffChunk{id: "AUTH", data: []byte("Studio A")},
+		aiffChunk{id: "(c) ", data: []byte("2026 Label")},
+		aiffSSND(4, 44100*4),
+		aiffChunk{id: "ID3 ", data: id3},
+	)
+	info, streams, general, generalJSON, ok := ParseAIFF(bytes.NewReader(data), int64(len(data)))
+	if !ok || len(streams) != 1 {
+		t.Fatalf("ParseAIFF ok=%v streams=%d", ok, len(streams))
+	}
+	if info.DurationSeconds != 1 {
+		t.Fatalf("duration=%v, want 1", info.DurationSeconds)
+	}
+	if len(general) != 0 {
+		t.Fatalf("general fields=%v, want none for plain AIFF", general)
+	}
+	fields := streams[0].Fields
+	for name, want := range map[string]string{
+		"Format":                      "PCM",
+		"Channel(s)":                  "2 channels",
+		"Sampling rate":               "44.1 kHz",
+		"Bit depth":                   "16 bits",
+		"Format settings, Endianness": "Big",
+		"Format settings, Sign":       "Signed",
+		"Bit rate mode":               "Constant",
+		"Bit rate":                    "1 411 kb/s",
+	} {
+		if got := findField(fields, name); got != want {
+			t.Fatalf("%s=%q, want %q", name, got, want)
+		}
+	}
+	if got := streams[0].JSON["StreamSize"]; got != "176400" {
+		t.Fatalf("StreamSize=%q, want 176400", got)
+	}
+	if got := streams[0].JSON["SamplingCount"]; got != "44100" {
+		t.Fatalf("SamplingCount=%q, want 44100", got)
+	}
+	for key, want := range map[string]string{
+		"Title":      "Take 3",
+		"Performer":  "Studio A",
+		"Copyright":  "2026 Label",
+		"Album":      "Sessions",
+		"StreamSize": strconv.Itoa(len(data) - 176400),
+	} {
+		if got := generalJSON[key]; got != want {
+			t.Fatalf("General %s=%q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestParseAIFFCompressionTypes(t *testing.T) {
+	cases := []struct {
+		compression string
+		bits        uint16
+		frames      uint32
+		format      string
+		profile     string
+		endianness  string
+		bitDepth    string
+		bitrate     string
+		duration    float64
+	}{
+		{compression: "sowt", bits: 16, frames: 48000, format: "PCM", endianness: "Little", bitDepth: "16 bits", bitrate: "1 536 kb/s", duration: 1},
+		{compression: "fl32", bits: 32, frames: 48000, format: "PCM", profile: "Float", endianness: "Big", bitDepth: "32 bits", bitrate: "3 072 kb/s", duration: 1},
+		{compression: "ulaw", bits: 16, frames: 48000, format: "ADPCM", profile: "U-Law", bitDepth: "8 bits", bitrate: "768 kb/s", duration: 1},
+		{compression: "ima4", bits: 16, frames: 750, format: "ADPCM", profile: "IMA", bitDepth: "4 bits", bitrate: "408 kb/s", duration: 1},
+	}
+	for _, tc := range cases {
+		t.Run(tc.compression, func(t *testing.T) {
+			data := buildAIFF("AIFC", aiffCOMM(2, tc.frames, tc.bits, 48000, tc.compression), aiffSSND(0, 16))
+			info, streams, general, _, ok := ParseAIFF(bytes.NewReader(data), int64(len(data)))
+			if !ok {
+				t.Fatalf("ParseAIFF failed")
+			}
+			if info.DurationSeconds != tc.duration {
+				t.Fatalf("duration=%v, want %v", info.DurationSeconds, tc.duration)
+			}
+			if got := findField(general, "Format profile"); got != "AIFF-C" {
+				t.Fatalf("General Format profile=%q, want AIFF-C", got)
+			}
+			fields := streams[0].Fields
+			for name, want := range map[string]string{
+				"Format":                      tc.format,
+				"Format profile":              tc.profile,
+				"Codec ID":                    tc.compression,
+				"Bit depth":                   tc.bitDepth,
+				"Format settings, Endianness": tc.endianness,
+				"Bit rate":                    tc.bitrate,
+			} {
+				if got := findField(fields, name); got != want {
+					t.Fatalf("%s=%q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAIFFRejectsOtherForms(t *testing.T) {
+	data := buildAIFF("8SVX", aiffCOMM(1, 1, 8, 8000, ""))
+	if _, _, _, _, ok := ParseAIFF(bytes.NewReader(data), int64(len(data))); ok {
+		t.Fatalf("ParseAIFF accepted an 8SVX form")
+	}
+	data = buildAIFF("AIFF", aiffSSND(0, 4))
+	if _, _, _, _, ok := ParseAIFF(bytes.NewReader(data), int64(len(data))); ok {
+		t.Fatalf("ParseAIFF accepted a file without COMM")
+	}
+}
+
+func TestAnalyzeReaderAIFF(t *testing.T) {
+	data := buildAIFF("AIFC", aiffCOMM(1, 8000, 16, 8000, "NONE"), aiffSSND(0, 16000))
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "take.aifc", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze reader: %v", err)
+	}
+	if got := findField(report.General.Fields, "Format"); got != "AIFF" {
+		t.Fatalf("Format=%q, want AIFF", got)
+	}
+	if len(report.Streams) != 1 || report.Streams[0].Kind != StreamAudio {
+		t.Fatalf("streams=%+v, want one audio stream", report.Streams)
+	}
+	if got := findField(report.Streams[0].Fields, "Duration"); got != "1 s 0 ms" {
+		t.Fatalf("Duration=%q, want 1 s 0 ms", got)
+	}
+}
@@ -1,12 +1,13 @@
 package mediainfo
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"math"
 	"os"
 	"path/filepath"
-	"sort"
 	"strconv"
 	"strings"
 )
@@ -16,21 +17,72 @@ func AnalyzeFile(path string) (Report, error) {
 }
 
 func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
-	opts = normalizeAnalyzeOptions(opts)
+	return AnalyzeFileContext(context.Background(), path, opts)
+}
+
+// AnalyzeFileContext is AnalyzeFileWithOptions bound to ctx. When ctx ends before analysis
+// finishes, it returns ctx.Err() together with the partially parsed Report (Incomplete=true).
+func AnalyzeFileContext(ctx context.Context, path string, opts AnalyzeOptions) (Report, error) {
+	if err := ctx.Err(); err != nil {
+		return Report{}, err
+	}
 	stat, err := os.Stat(path)
 	if err != nil {
 		return Report{}, err
 	}
-	fileSize := stat.Size()
-	var completeNameLast string
-
-	header := make([]byte, maxSniffBytes)
+	if opts.Cache != nil {
+		if report, ok := opts.Cache.lookup(path, stat, opts); ok {
+			return report, nil
+		}
+	}
 	file, err := os.Open(path)
 	if err != nil {
 		return Report{}, err
 	}
 	defer file.Close()
 
+	report, err := analyzeMedia(ctx, file, stat.Size(), path, true, opts)
+	if err == nil && opts.Cache != nil {
+		// A failed write only costs a re-parse next time.
+		_ = opts.Cache.store(path, stat, opts, report)
+	}
+	return report, err
+}
+
+// AnalyzeReader analyzes size bytes read from r. name is used as the report reference and for
+// extension-based format detection; it does not need to exist on disk. Path-only features
+// (continuous file names, sibling DVD files, file times) are skipped.
+func AnalyzeReader(r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	return AnalyzeReaderContext(context.Background(), r, size, name, opts)
+}
+
+// AnalyzeReaderContext is AnalyzeReader bound to ctx, with the same cancellation semantics as
+// AnalyzeFileContext.
+func AnalyzeReaderContext(ctx context.Context, r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	if size < 0 {
+		return Report{}, fmt.Errorf("invalid size %d", size)
+	}
+	if err := ctx.Err(); err != nil {
+		return Report{}, err
+	}
+	return analyzeMedia(ctx, io.NewSectionReader(r, 0, size), size, name, false, opts)
+}
+
+// mediaFile is the access pattern the container parsers need: sequential/seeking readers
+// (TS, PS, AVI, ...) and random access (MP4, Matroska).
+type mediaFile interface {
+	io.Reader
+	io.ReaderAt
+	io.Seeker
+}
+
+func analyzeMedia(ctx context.Context, file mediaFile, size int64, path string, onDisk bool, opts AnalyzeOptions) (Report, error) {
+	opts = normalizeAnalyzeOptions(opts)
+	file = newContextFile(ctx, file)
+	fileSize := size
+	var completeNameLast string
+
+	header := make([]byte, maxSniffBytes)
 	n, _ := io.ReadFull(file, header)
 	header = header[:n]
 
@@ -38,7 +90,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 
 	// MediaInfo CLI continuous file names behavior (File_TestContinuousFileNames=1) applies to both
 	// MPEG-TS and BDAV (M2TS) streams.
-	if opts.TestContinuousFileNames && (format == "MPEG-TS" || format == "BDAV") {
+	if onDisk && opts.TestContinuousFileNames && (format == "MPEG-TS" || format == "BDAV") {
 		if set, ok := detectContinuousFileSet(path); ok {
 			completeNameLast = set.LastPath
 			fileSize = set.TotalSize
@@ -62,10 +114,12 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 
 	info := ContainerInfo{}
 	streams := []Stream{}
+	sibling := false
 	switch format {
 	case "MPEG-4", "QuickTime":
-		if parsed, ok := ParseMP4(file, stat.Size()); ok {
+		if parsed, ok := parseMP4WithInit(file, size, path, onDisk, opts); ok {
 			info = parsed.Container
+			sibling = parsed.initSegment != ""
 			general.JSON = map[string]string{}
 			for _, field := range parsed.General {
 				general.Fields = appendFieldUnique(general.Fields, field)
@@ -80,8 +134,8 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				// Preserve fractional seconds in JSON (text Duration drops ms for long runtimes).
 				general.JSON["Duration"] = formatJSONSeconds(info.DurationSeconds)
 			}
-			setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
-			if headerSize, dataSize, footerSize, mdatCount, moovBeforeMdat, ok := mp4TopLevelSizes(file, stat.Size()); ok {
+			setOverallBitRate(general.JSON, size, info.DurationSeconds)
+			if headerSize, dataSize, footerSize, mdatCount, moovBeforeMdat, ok := mp4TopLevelSizes(file, size); ok {
 				general.JSON["HeaderSize"] = strconv.FormatInt(headerSize, 10)
 				general.JSON["DataSize"] = strconv.FormatInt(dataSize, 10)
 				general.JSON["FooterSize"] = strconv.FormatInt(footerSize, 10)
@@ -92,8 +146,32 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				}
 				_ = mdatCount
 			}
+			if parsed.Fragmented {
+				if general.JSONRaw == nil {
+					general.JSONRaw = map[string]string{}
+				}
+				extra := appendJSONExtra(general.JSONRaw["extra"], "IsFragmented", "Yes")
+				extra = appendJSONExtra(extra, "FragmentCount", strconv.Itoa(parsed.FragmentCount))
+				if parsed.FragmentDuration > 0 {
+					extra = appendJSONExtra(extra, "FragmentDuration", formatJSONSeconds(parsed.FragmentDuration))
+				}
+				general.JSONRaw["extra"] = extra
+			}
+			for _, field := range mp4TagGeneralFields(parsed.Tags) {
+				general.Fields = appendFieldUnique(general.Fields, field)
+			}
+			if len(parsed.TagExtra) > 0 {
+				if general.JSONRaw == nil {
+					general.JSONRaw = map[string]string{}
+				}
+				general.JSONRaw["extra"] = mergeJSONExtra(general.JSONRaw["extra"], parsed.TagExtra)
+			}
 			var generalFrameCount string
 			for _, track := range parsed.Tracks {
+				if track.Kind == StreamOther {
+					streams = append(streams, mp4TimeCodeStream(track))
+					continue
+				}
 				fields := []Field{}
 				displayDuration := track.DurationSeconds
 				sourceDuration := 0.0
@@ -230,7 +308,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 							streamBytes = int64(math.Round((bitrate * displayDuration) / 8))
 						}
 					}
-					if streamSize := formatStreamSize(streamBytes, stat.Size()); streamSize != "" {
+					if streamSize := formatStreamSize(streamBytes, size); streamSize != "" {
 						fields = appendFieldUnique(fields, Field{Name: "Stream size", Value: streamSize})
 					}
 					if streamBytes > 0 {
@@ -255,7 +333,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 						}
 					}
 					if sourceDuration > 0 {
-						if sourceSize := formatStreamSize(int64(track.SampleBytes), stat.Size()); sourceSize != "" {
+						if sourceSize := formatStreamSize(int64(track.SampleBytes), size); sourceSize != "" {
 							fields = appendFieldUnique(fields, Field{Name: "Source stream size", Value: sourceSize})
 						}
 						jsonExtras["Source_StreamSize"] = strconv.FormatInt(int64(track.SampleBytes), 10)
@@ -295,7 +373,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				if track.Kind == StreamAudio && findField(fields, "Codec ID") == "ac-3" &&
 					track.FirstChunkOff > 0 && len(track.SampleSizeHead) > 0 {
 					sz := int(track.SampleSizeHead[0])
-					if sz > 0 && int64(track.FirstChunkOff) > 0 && int64(track.FirstChunkOff) < stat.Size() {
+					if sz > 0 && int64(track.FirstChunkOff) > 0 && int64(track.FirstChunkOff) < size {
 						if sz > 1<<16 {
 							sz = 1 << 16
 						}
@@ -445,10 +523,10 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 			// MP4 General StreamSize: remaining bytes after summing track stream sizes.
 			streamSizeSum := sumStreamSizes(streams, true)
-			setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+			setRemainingStreamSize(general.JSON, size, streamSizeSum)
 		}
 	case "Matroska":
-		if parsed, ok := ParseMatroskaWithOptions(file, stat.Size(), opts); ok {
+		if parsed, ok := ParseMatroskaWithOptions(file, size, opts); ok {
 			info = parsed.Container
 			general.JSON = map[string]string{}
 			var rawWritingApp string
@@ -473,13 +551,13 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			if info.DurationSeconds > 0 {
 				general.JSON["Duration"] = formatJSONFloat(info.DurationSeconds)
 			}
-			setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
+			setOverallBitRate(general.JSON, size, info.DurationSeconds)
 			general.JSON["IsStreamable"] = "Yes"
 			streamSizeSum := sumStreamSizes(streams, true)
 			// Official mediainfo does not expose large Matroska overhead as General StreamSize when
 			// it's dominated by attachments (fonts).
 			if len(parsed.attachments) == 0 {
-				setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+				setRemainingStreamSize(general.JSON, size, streamSizeSum)
 			}
 			overallModeField := ""
 			for _, stream := range streams {
@@ -598,7 +676,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG-TS":
-		if parsedInfo, parsedStreams, generalFields, ok := ParseMPEGTS(file, stat.Size(), opts.ParseSpeed); ok {
+		if parsedInfo, parsedStreams, generalFields, ok := ParseMPEGTS(file, size, opts.ParseSpeed); ok {
 			info = parsedInfo
 			general.JSON = map[string]string{}
 			general.JSONRaw = map[string]string{}
@@ -729,7 +807,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			})
 		}
 	case "BDAV":
-		if parsedInfo, parsedStreams, generalFields, ok := ParseBDAV(file, stat.Size(), opts.ParseSpeed); ok {
+		if parsedInfo, parsedStreams, generalFields, ok := ParseBDAV(file, size, opts.ParseSpeed); ok {
 			info = parsedInfo
 			general.JSON = map[string]string{}
 			general.JSONRaw = map[string]string{}
@@ -809,7 +887,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				if f, err := os.Open(completeNameLast); err == nil {
 					if st, err := f.Stat(); err == nil {
 						lastSize = st.Size()
-						if li, ls, _, ok := ParseBDAV(f, st.Size(), opts.ParseSpeed); ok && li.DurationSeconds > 0 {
+						if li, ls, _, ok := ParseBDAV(newContextFile(ctx, f), st.Size(), opts.ParseSpeed); ok && li.DurationSeconds > 0 {
 							lastInfo = li
 							lastStreams = ls
 						}
@@ -1118,7 +1196,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG-PS":
-		psSize := stat.Size()
+		psSize := size
 		psPaths := []string{path}
 		var completeNameLast string
 		dvdExtras := false
@@ -1309,7 +1387,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG Audio":
-		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseMP3(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseMP3(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			// For audio-only formats, the Field-based duration formatting drops milliseconds
@@ -1319,9 +1397,9 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON["Duration"] = formatJSONSeconds(info.DurationSeconds)
 			}
 			// Match official: overall bitrate uses audio payload (not trailing junk bytes).
-			payloadSize := stat.Size() - info.StreamOverheadBytes
+			payloadSize := size - info.StreamOverheadBytes
 			if payloadSize < 0 {
-				payloadSize = stat.Size()
+				payloadSize = size
 			}
 			for _, s := range streams {
 				if s.Kind != StreamAudio || s.JSON == nil {
@@ -1361,7 +1439,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "FLAC":
-		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseFLAC(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, tagJSON, tagJSONRaw, ok := ParseFLAC(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			general.JSON = map[string]string{}
@@ -1372,7 +1450,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			if info.DurationSeconds > 0 {
 				durationMs := int64(math.Round(info.DurationSeconds * 1000))
 				if durationMs > 0 {
-					general.JSON["OverallBitRate"] = strconv.FormatInt((stat.Size()*8000+durationMs/2)/durationMs, 10)
+					general.JSON["OverallBitRate"] = strconv.FormatInt((size*8000+durationMs/2)/durationMs, 10)
 				}
 			}
 			// Official mediainfo sets General StreamSize=0 for FLAC.
@@ -1394,7 +1472,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "Wave":
-		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseWAV(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseWAV(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			if len(generalFields) > 0 {
@@ -1407,7 +1485,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON = map[string]string{}
 			}
 			if info.DurationSeconds > 0 {
-				setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
 			}
 			if info.StreamOverheadBytes > 0 {
 				general.JSON["StreamSize"] = strconv.FormatInt(info.StreamOverheadBytes, 10)
@@ -1418,8 +1496,28 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				}
 			}
 		}
+	case "AIFF":
+		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseAIFF(file, size); ok {
+			info = parsedInfo
+			streams = parsedStreams
+			for _, field := range generalFields {
+				general.Fields = appendFieldUnique(general.Fields, field)
+			}
+			// Same accounting as Wave: OverallBitRate uses the full file size; StreamSize is chunk overhead.
+			if general.JSON == nil {
+				general.JSON = map[string]string{}
+			}
+			if info.DurationSeconds > 0 {
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
+			}
+			for k, v := range generalJSON {
+				if v != "" {
+					general.JSON[k] = v
+				}
+			}
+		}
 	case "Ogg":
-		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseOgg(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, generalFields, generalJSON, ok := ParseOgg(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			if len(generalFields) > 0 {
@@ -1432,7 +1530,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON = map[string]string{}
 			}
 			if info.DurationSeconds > 0 {
-				setOverallBitRate(general.JSON, stat.Size(), info.DurationSeconds)
+				setOverallBitRate(general.JSON, size, info.DurationSeconds)
 			}
 			for k, v := range generalJSON {
 				if v != "" {
@@ -1441,7 +1539,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			}
 		}
 	case "MPEG Video":
-		if parsedInfo, parsedStreams, ok := ParseMPEGVideo(file, stat.Size()); ok {
+		if parsedInfo, parsedStreams, ok := ParseMPEGVideo(file, size); ok {
 			info = parsedInfo
 			streams = parsedStreams
 			general.JSON = map[string]string{}
@@ -1449,7 +1547,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			general.Fields = appendFieldUnique(general.Fields, Field{Name: "FileExtension_Invalid", Value: "mpgv mpv mp1v m1v mp2v m2v"})
 			if info.DurationSeconds > 0 {
 				jsonDuration := math.Round(info.DurationSeconds*1000) / 1000
-				setOverallBitRate(general.JSON, stat.Size(), jsonDuration)
+				setOverallBitRate(general.JSON, size, jsonDuration)
 			}
 			var frameCount string
 			for i := range streams {
@@ -1464,13 +1562,13 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON["FrameCount"] = frameCount
 			}
 			streamSizeSum := sumStreamSizes(streams, false)
-			setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+			setRemainingStreamSize(general.JSON, size, streamSizeSum)
 			general.JSONRaw = map[string]string{
 				"extra": "{\"FileExtension_Invalid\":\"mpgv mpv mp1v m1v mp2v m2v\"}",
 			}
 		}
 	case "AVI":
-		if parsedInfo, parsedStreams, generalFields, interleaved, ok := ParseAVIWithOptions(file, stat.Size(), opts); ok {
+		if parsedInfo, parsedStreams, generalFields, interleaved, ok := ParseAVIWithOptions(file, size, opts); ok {
 			info = parsedInfo
 			general.JSON = map[string]string{}
 			var rawWritingApp string
@@ -1522,7 +1620,7 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 			if info.DurationSeconds > 0 {
 				jsonDuration := math.Round(info.DurationSeconds*1000) / 1000
 				general.JSON["Duration"] = formatJSONSeconds(jsonDuration)
-				setOverallBitRate(general.JSON, stat.Size(), jsonDuration)
+				setOverallBitRate(general.JSON, size, jsonDuration)
 			}
 			var frameCount string
 			hasVBR := false
@@ -1551,10 +1649,10 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 				general.JSON["OverallBitRate_Mode"] = "VBR"
 			}
 			streamSizeSum := sumStreamSizes(streams, false)
-			setRemainingStreamSize(general.JSON, stat.Size(), streamSizeSum)
+			setRemainingStreamSize(general.JSON, size, streamSizeSum)
 		}
 	case "DVD Video":
-		if parsed, ok := parseDVDVideo(path, file, stat.Size(), opts); ok {
+		if parsed, ok := parseDVDVideo(path, file, size, opts); ok {
 			info = parsed.Container
 			if parsed.FileSize > 0 {
 				general.Fields = setFieldValue(general.Fields, "File size", formatBytes(parsed.FileSize))
@@ -1625,11 +1723,19 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 		sortFields(streams[i].Kind, streams[i].Fields)
 	}
 	sortStreams(streams)
-	return Report{
-		Ref:     path,
-		General: general,
-		Streams: streams,
-	}, nil
+	report := Report{
+		Ref:      path,
+		General:  general,
+		Streams:  streams,
+		virtual:  !onDisk,
+		fileSize: size,
+		sibling:  sibling,
+	}
+	if err := ctx.Err(); err != nil {
+		report.Incomplete = true
+		return report, err
+	}
+	return report, nil
 }
 
 func AnalyzeFiles(paths []string) ([]Report, int, error) {
@@ -1637,14 +1743,30 @@ func AnalyzeFiles(paths []string) ([]Report, int, error) {
 }
 
 func AnalyzeFilesWithOptions(paths []string, opts AnalyzeOptions) ([]Report, int, error) {
-	expanded, err := expandPaths(paths)
+	return AnalyzeFilesContext(context.Background(), paths, ScanOptions{}, opts)
+}
+
+// AnalyzeFilesContext is AnalyzeFilesWithOptions bound to ctx, with directories expanded by
+// scan. When ctx ends mid-batch, it returns ctx.Err() with the reports finished so far plus
+// the partial (Incomplete) one.
+func AnalyzeFilesContext(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions) ([]Report, int, error) {
+	expanded, err := ExpandPaths(paths, scan)
 	if err != nil {
 		return nil, 0, err
 	}
 	reports := make([]Report, 0, len(expanded))
 	for _, path := range expanded {
-		report, err := AnalyzeFileWithOptions(path, opts)
+		if err := ctx.Err(); err != nil {
+			return reports, len(reports), err
+		}
+		report, err := AnalyzeFileContext(ctx, path, opts)
 		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil && errors.Is(err, ctxErr) {
+				if report.Incomplete {
+					reports = append(reports, report)
+				}
+				return reports, len(reports), err
+			}
 			return nil, 0, fmt.Errorf("%s: %w", path, err)
 		}
 		reports = append(reports, report)
@@ -1652,36 +1774,6 @@ func AnalyzeFilesWithOptions(paths []string, opts AnalyzeOptions) ([]Report, int
 	return reports, len(reports), nil
 }
 
-func expandPaths(paths []string) ([]string, error) {
-	expanded := make([]string, 0, len(paths))
-	for _, path := range paths {
-		info, err := os.Stat(path)
-		if err != nil {
-			return nil, err
-		}
-		if !info.IsDir() {
-			expanded = append(expanded, path)
-			continue
-		}
-		entries, err := os.ReadDir(path)
-		if err != nil {
-			return nil, err
-		}
-		names := make([]string, 0, len(entries))
-		for _, entry := range entries {
-			if entry.IsDir() {
-				continue
-			}
-			names = append(names, entry.Name())
-		}
-		sort.Strings(names)
-		for _, name := range names {
-			expanded = append(expanded, filepath.Join(path, name))
-		}
-	}
-	return expanded, nil
-}
-
 func parsePixels(value string) (uint64, bool) {
 	parsedValue := extractLeadingNumber(value)
 	if parsedValue == "" {
@@ -0,0 +1,114 @@
+package mediainfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"runtime"
+	"sync"
+)
+
+// BatchResult is the outcome of analyzing one file in a batch. Err is set when the file could
+// not be analyzed; Report may still hold a partial (Incomplete) report when ctx ended.
+type BatchResult struct {
+	Path   string
+	Report Report
+	Err    error
+}
+
+// AnalyzeBatch analyzes paths (directories are expanded by scan) using up to workers
+// concurrent analyses (GOMAXPROCS when workers <= 0). It returns one result per file, in
+// input order; a failing file does not abort the batch.
+func AnalyzeBatch(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions, workers int) []BatchResult {
+	results := expandBatchPaths(paths, scan)
+	runBatch(ctx, results, opts, workers, nil)
+	return results
+}
+
+// AnalyzeBatchStream is the streaming form of AnalyzeBatch: results are delivered in input
+// order as soon as they (and every result before them) are ready, and the channel is closed
+// after the last one. Callers that stop reading early must cancel ctx.
+func AnalyzeBatchStream(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions, workers int) <-chan BatchResult {
+	results := expandBatchPaths(paths, scan)
+	ready := make([]chan struct{}, len(results))
+	for i := range ready {
+		ready[i] = make(chan struct{})
+	}
+	go runBatch(ctx, results, opts, workers, func(i int) { close(ready[i]) })
+
+	out := make(chan BatchResult)
+	go func() {
+		defer close(out)
+		for i := range results {
+			<-ready[i]
+			select {
+			case out <- results[i]:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
+
+// expandBatchPaths expands every input on its own so a missing path becomes a per-file error
+// instead of failing the whole batch.
+func expandBatchPaths(paths []string, scan ScanOptions) []BatchResult {
+	results := make([]BatchResult, 0, len(paths))
+	for _, path := range paths {
+		expanded, err := ExpandPaths([]string{path}, scan)
+		if err != nil {
+			results = append(results, BatchResult{Path: path, Err: err})
+			continue
+		}
+		for _, file := range expanded {
+			results = append(results, BatchResult{Path: file})
+		}
+	}
+	return results
+}
+
+// runBatch fills results in place with a bounded worker pool. done (optional) is called from
+// the worker goroutine once results[i] is final.
+func runBatch(ctx context.Context, results []BatchResult, opts AnalyzeOptions, workers int, done func(i int)) {
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = max(min(workers, len(results)), 1)
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if results[i].Err == nil {
+					report, err := AnalyzeFileContext(ctx, results[i].Path, opts)
+					results[i].Report = report
+					results[i].Err = batchError(results[i].Path, err)
+				}
+				if done != nil {
+					done(i)
+				}
+			}
+		}()
+	}
+	for i := range results {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+}
+
+func batchError(path string, err error) error {
+	if err == nil {
+		return nil
+	}
+	var pathErr *fs.PathError
+	if errors.As(err, &pathErr) {
+		return err
+	}
+	return fmt.Errorf("%s: %w", path, err)
+}
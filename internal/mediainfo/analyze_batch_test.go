@@ -0,0 +1,63 @@
+package mediainfo
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeBatchKeepsOrderAndPerFileErrors(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"sample.mkv", "sample.mp4", "sample.flac"} {
+		data, err := os.ReadFile(filepath.Join("samples", name))
+		if err != nil {
+			t.Fatalf("read sample: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), data, 0o600); err != nil {
+			t.Fatalf("write sample: %v", err)
+		}
+	}
+	missing := filepath.Join(dir, "missing.mkv")
+	paths := []string{missing, dir, filepath.Join("samples", "sample.wav")}
+
+	results := AnalyzeBatch(context.Background(), paths, ScanOptions{}, defaultAnalyzeOptions(), 3)
+	want := []string{
+		missing,
+		filepath.Join(dir, "sample.flac"),
+		filepath.Join(dir, "sample.mkv"),
+		filepath.Join(dir, "sample.mp4"),
+		filepath.Join("samples", "sample.wav"),
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(results), len(want))
+	}
+	for i, result := range results {
+		if result.Path != want[i] {
+			t.Fatalf("results[%d].Path=%q, want %q", i, result.Path, want[i])
+		}
+		if i == 0 {
+			if result.Err == nil {
+				t.Fatalf("missing path: Err=nil, want error")
+			}
+			continue
+		}
+		if result.Err != nil {
+			t.Fatalf("%s: %v", result.Path, result.Err)
+		}
+		if got := findField(result.Report.General.Fields, "Complete name"); got != result.Path {
+			t.Fatalf("Complete name=%q, want %q", got, result.Path)
+		}
+	}
+
+	i := 0
+	for result := range AnalyzeBatchStream(context.Background(), paths, ScanOptions{}, defaultAnalyzeOptions(), 2) {
+		if result.Path != want[i] {
+			t.Fatalf("stream[%d].Path=%q, want %q", i, result.Path, want[i])
+		}
+		i++
+	}
+	if i != len(want) {
+		t.Fatalf("stream delivered %d results, want %d", i, len(want))
+	}
+}
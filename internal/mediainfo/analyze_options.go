@@ -5,6 +5,11 @@ type AnalyzeOptions struct {
 	HasParseSpeed              bool
 	TestContinuousFileNames    bool
 	HasTestContinuousFileNames bool
+	// Cache, when set, serves unchanged files from disk and stores fresh analyses.
+	Cache *ReportCache
+	// InitSegment is the fragmented MP4 initialization segment used for media segments (.m4s)
+	// that have no moov of their own; "" looks for one next to the segment.
+	InitSegment string
 }
 
 func defaultAnalyzeOptions() AnalyzeOptions {
@@ -0,0 +1,72 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAnalyzeReaderMatchesAnalyzeFile(t *testing.T) {
+	samples := []string{
+		"sample.mp4",
+		"sample.mkv",
+		"sample.ts",
+		"sample.avi",
+		"sample.mpg",
+		"sample.vob",
+		"sample.mp3",
+		"sample.flac",
+		"sample.wav",
+		"sample.ogg",
+	}
+
+	for _, name := range samples {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join("samples", name)
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("read sample: %v", err)
+			}
+			fromFile, err := AnalyzeFile(path)
+			if err != nil {
+				t.Fatalf("analyze file: %v", err)
+			}
+			fromReader, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), path, defaultAnalyzeOptions())
+			if err != nil {
+				t.Fatalf("analyze reader: %v", err)
+			}
+
+			if got, want := RenderText([]Report{fromReader}), RenderText([]Report{fromFile}); got != want {
+				t.Fatalf("text mismatch:\n%s\nwant:\n%s", got, want)
+			}
+
+			general := buildJSONGeneralFields(fromReader)
+			if got := jsonFieldValue(general, "FileSize"); got != jsonFieldValue(buildJSONGeneralFields(fromFile), "FileSize") {
+				t.Fatalf("FileSize=%q, want file-based value", got)
+			}
+			if got := jsonFieldValue(general, "File_Modified_Date"); got != "" {
+				t.Fatalf("File_Modified_Date=%q, want empty for reader input", got)
+			}
+		})
+	}
+}
+
+func TestAnalyzeReaderNameNotOnDisk(t *testing.T) {
+	data, err := os.ReadFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("read sample: %v", err)
+	}
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "uploads/blob.mkv", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze reader: %v", err)
+	}
+	if got := findField(report.General.Fields, "Complete name"); got != "uploads/blob.mkv" {
+		t.Fatalf("Complete name=%q", got)
+	}
+	var root map[string]any
+	if err := json.Unmarshal([]byte(RenderJSON([]Report{report})), &root); err != nil {
+		t.Fatalf("parse json output: %v", err)
+	}
+}
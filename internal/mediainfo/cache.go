@@ -0,0 +1,409 @@
+package mediainfo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// cacheFormat is bumped whenever the stored entry layout or the meaning of a cached report
+// changes in a way AppVersion alone would not reveal.
+const cacheFormat = 1
+
+// ReportCache is an opt-in on-disk store of analyzed reports, keyed by file identity (device,
+// inode, size, modification time), the analysis options and the tool version. Set
+// AnalyzeOptions.Cache to use it from AnalyzeFile* and the batch APIs. It is safe for
+// concurrent use, including by several processes sharing the directory.
+type ReportCache struct {
+	dir    string
+	hits   atomic.Int64
+	misses atomic.Int64
+	writes atomic.Int64
+}
+
+// CacheStats summarizes a cache directory and this process's use of it.
+type CacheStats struct {
+	Dir     string `json:"dir"`
+	Entries int    `json:"entries"`
+	Bytes   int64  `json:"bytes"`
+	// Stale counts entries written by another go-mediainfo version; Prune removes them.
+	Stale  int   `json:"stale"`
+	Hits   int64 `json:"hits"`
+	Misses int64 `json:"misses"`
+	Writes int64 `json:"writes"`
+}
+
+type cacheKey struct {
+	Version    string  `json:"version"`
+	Device     uint64  `json:"device,omitempty"`
+	Inode      uint64  `json:"inode,omitempty"`
+	Path       string  `json:"path,omitempty"` // only when the platform has no inode numbers
+	Ext        string  `json:"ext"`
+	Size       int64   `json:"size"`
+	ModTime    int64   `json:"mtime"`
+	ParseSpeed float64 `json:"parseSpeed"`
+}
+
+type cacheEntry struct {
+	Key cacheKey `json:"key"`
+	// Path is where the file was last seen, so Prune can tell whether the entry is still live.
+	Path    string         `json:"path"`
+	General cachedStream   `json:"general"`
+	Streams []cachedStream `json:"streams"`
+}
+
+type cachedStream struct {
+	Kind                StreamKind        `json:"kind"`
+	Fields              []Field           `json:"fields"`
+	JSON                map[string]string `json:"json,omitempty"`
+	JSONRaw             map[string]string `json:"jsonRaw,omitempty"`
+	JSONSkipStreamOrder bool              `json:"skipStreamOrder,omitempty"`
+	JSONSkipComputed    bool              `json:"skipComputed,omitempty"`
+}
+
+// DefaultCacheDir returns the per-user cache location, e.g. ~/.cache/go-mediainfo on Linux.
+func DefaultCacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, AppName), nil
+}
+
+// OpenReportCache opens (creating if needed) a cache in dir; "" selects DefaultCacheDir.
+func OpenReportCache(dir string) (*ReportCache, error) {
+	if dir == "" {
+		var err error
+		if dir, err = DefaultCacheDir(); err != nil {
+			return nil, err
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("cache: %w", err)
+	}
+	return &ReportCache{dir: dir}, nil
+}
+
+// Dir returns the cache directory.
+func (c *ReportCache) Dir() string {
+	return c.dir
+}
+
+// Lookup returns the cached report for the file at path, if one matches its current identity.
+func (c *ReportCache) Lookup(path string, opts AnalyzeOptions) (Report, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return Report{}, false
+	}
+	return c.lookup(path, info, opts)
+}
+
+// Store saves report as the analysis of the file at path.
+func (c *ReportCache) Store(path string, opts AnalyzeOptions, report Report) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	return c.store(path, info, opts, report)
+}
+
+func (c *ReportCache) lookup(path string, info os.FileInfo, opts AnalyzeOptions) (Report, bool) {
+	key, ok := newCacheKey(path, info, opts)
+	if !ok {
+		return Report{}, false
+	}
+	entryPath := c.entryPath(key)
+	data, err := os.ReadFile(entryPath)
+	if err != nil {
+		c.misses.Add(1)
+		return Report{}, false
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil || entry.Key != key {
+		// Corrupt or colliding entry: drop it so the fresh analysis replaces it.
+		os.Remove(entryPath)
+		c.misses.Add(1)
+		return Report{}, false
+	}
+	c.hits.Add(1)
+	return entry.report(path), true
+}
+
+func (c *ReportCache) store(path string, info os.FileInfo, opts AnalyzeOptions, report Report) error {
+	if report.Incomplete || report.virtual || report.sibling {
+		return nil
+	}
+	key, ok := newCacheKey(path, info, opts)
+	if !ok {
+		return nil
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		abs = path
+	}
+	entry := cacheEntry{Key: key, Path: abs, General: newCachedStream(report.General)}
+	for _, stream := range report.Streams {
+		entry.Streams = append(entry.Streams, newCachedStream(stream))
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if err := writeCacheFile(c.entryPath(key), data); err != nil {
+		return fmt.Errorf("cache: %w", err)
+	}
+	c.writes.Add(1)
+	return nil
+}
+
+// Stats counts the entries on disk and reports this process's hits, misses and writes.
+func (c *ReportCache) Stats() (CacheStats, error) {
+	stats := CacheStats{Dir: c.dir, Hits: c.hits.Load(), Misses: c.misses.Load(), Writes: c.writes.Load()}
+	version := cacheVersion()
+	err := c.walk(func(path string, entry *cacheEntry, size int64) {
+		stats.Entries++
+		stats.Bytes += size
+		if entry == nil || entry.Key.Version != version {
+			stats.Stale++
+		}
+	})
+	return stats, err
+}
+
+// Purge deletes every entry. Only the cache's own shard directories are touched, so a
+// mistyped directory never loses unrelated files.
+func (c *ReportCache) Purge() error {
+	shards, err := c.shards()
+	if err != nil {
+		return err
+	}
+	for _, shard := range shards {
+		if err := os.RemoveAll(shard); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Prune deletes entries from other versions, unreadable entries, and entries whose file is
+// gone or has changed since it was cached. It returns how many were removed.
+func (c *ReportCache) Prune() (int, error) {
+	version := cacheVersion()
+	removed := 0
+	err := c.walk(func(path string, entry *cacheEntry, _ int64) {
+		if entry != nil && entry.Key.Version == version && entry.live() {
+			return
+		}
+		if os.Remove(path) == nil {
+			removed++
+		}
+	})
+	return removed, err
+}
+
+// Rebuild re-analyzes paths (directories are expanded by scan) without reading the cache and
+// stores the fresh reports, replacing any existing entries. It returns the number of files
+// stored and the per-file failures.
+func (c *ReportCache) Rebuild(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions) (int, error) {
+	opts.Cache = nil
+	var errs []error
+	stored := 0
+	for _, result := range AnalyzeBatch(ctx, paths, scan, opts, 0) {
+		if result.Err == nil {
+			result.Err = c.Store(result.Path, opts, result.Report)
+		}
+		if result.Err != nil {
+			errs = append(errs, result.Err)
+			continue
+		}
+		stored++
+	}
+	return stored, errors.Join(errs...)
+}
+
+// walk calls fn for every entry file; entry is nil when the file cannot be decoded.
+func (c *ReportCache) walk(fn func(path string, entry *cacheEntry, size int64)) error {
+	shards, err := c.shards()
+	if err != nil {
+		return err
+	}
+	for _, shard := range shards {
+		files, err := os.ReadDir(shard)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			return err
+		}
+		for _, file := range files {
+			if file.IsDir() || !strings.HasSuffix(file.Name(), ".json") {
+				continue
+			}
+			info, err := file.Info()
+			if err != nil {
+				continue
+			}
+			path := filepath.Join(shard, file.Name())
+			var entry *cacheEntry
+			if data, err := os.ReadFile(path); err == nil {
+				var decoded cacheEntry
+				if json.Unmarshal(data, &decoded) == nil {
+					entry = &decoded
+				}
+			}
+			fn(path, entry, info.Size())
+		}
+	}
+	return nil
+}
+
+// shards lists the two-hex-digit subdirectories entries live in.
+func (c *ReportCache) shards() ([]string, error) {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, err
+	}
+	var shards []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if _, err := hex.DecodeString(name); entry.IsDir() && len(name) == 2 && err == nil {
+			shards = append(shards, filepath.Join(c.dir, name))
+		}
+	}
+	return shards, nil
+}
+
+// entryPath spreads entries over 256 subdirectories named after the first hash byte.
+func (c *ReportCache) entryPath(key cacheKey) string {
+	data, _ := json.Marshal(key)
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(c.dir, name[:2], name+".json")
+}
+
+// newCacheKey identifies the file and the analysis; continuous file sets and MP4 media
+// segments are not cacheable because their reports depend on sibling files. Segments paired
+// with an init segment found next to them are only known after analysis; store skips those.
+func newCacheKey(path string, info os.FileInfo, opts AnalyzeOptions) (cacheKey, bool) {
+	opts = normalizeAnalyzeOptions(opts)
+	if opts.TestContinuousFileNames || !info.Mode().IsRegular() {
+		return cacheKey{}, false
+	}
+	if opts.InitSegment != "" || strings.EqualFold(filepath.Ext(path), ".m4s") {
+		return cacheKey{}, false
+	}
+	key := cacheKey{
+		Version:    cacheVersion(),
+		Ext:        strings.ToLower(filepath.Ext(path)),
+		Size:       info.Size(),
+		ModTime:    info.ModTime().UnixNano(),
+		ParseSpeed: opts.ParseSpeed,
+	}
+	if device, inode, ok := fileIdentity(info); ok {
+		key.Device, key.Inode = device, inode
+	} else if abs, err := filepath.Abs(path); err == nil {
+		key.Path = abs
+	} else {
+		return cacheKey{}, false
+	}
+	return key, true
+}
+
+// cacheVersion changes with the release version and, for development builds, the VCS revision,
+// so reports produced by different parsers never mix.
+func cacheVersion() string {
+	return fmt.Sprintf("%s+%d", AppVersion, cacheFormat) + buildRevision()
+}
+
+var buildRevision = sync.OnceValue(func() string {
+	version := ""
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			switch {
+			case setting.Key == "vcs.revision":
+				version += "+" + setting.Value
+			case setting.Key == "vcs.modified" && setting.Value == "true":
+				version += "-dirty"
+			}
+		}
+	}
+	return version
+})
+
+func (e cacheEntry) live() bool {
+	info, err := os.Stat(e.Path)
+	if err != nil {
+		return false
+	}
+	key, ok := newCacheKey(e.Path, info, AnalyzeOptions{ParseSpeed: e.Key.ParseSpeed, HasParseSpeed: true})
+	return ok && key == e.Key
+}
+
+// report rebuilds the Report, re-pointing it at path since the same inode may have moved.
+func (e cacheEntry) report(path string) Report {
+	report := Report{Ref: path, General: e.General.stream()}
+	for i, field := range report.General.Fields {
+		if field.Name == "Complete name" {
+			report.General.Fields[i].Value = path
+			break
+		}
+	}
+	for _, stream := range e.Streams {
+		report.Streams = append(report.Streams, stream.stream())
+	}
+	return report
+}
+
+func newCachedStream(stream Stream) cachedStream {
+	return cachedStream{
+		Kind:                stream.Kind,
+		Fields:              stream.Fields,
+		JSON:                stream.JSON,
+		JSONRaw:             stream.JSONRaw,
+		JSONSkipStreamOrder: stream.JSONSkipStreamOrder,
+		JSONSkipComputed:    stream.JSONSkipComputed,
+	}
+}
+
+func (s cachedStream) stream() Stream {
+	return Stream{
+		Kind:                s.Kind,
+		Fields:              s.Fields,
+		JSON:                s.JSON,
+		JSONRaw:             s.JSONRaw,
+		JSONSkipStreamOrder: s.JSONSkipStreamOrder,
+		JSONSkipComputed:    s.JSONSkipComputed,
+	}
+}
+
+// writeCacheFile replaces path atomically so concurrent readers never see a partial entry.
+func writeCacheFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
@@ -0,0 +1,176 @@
+package mediainfo
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func copyTestSample(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("samples", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReportCacheHitsAndInvalidation(t *testing.T) {
+	cache, err := OpenReportCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := copyTestSample(t, "sample.mkv")
+	opts := AnalyzeOptions{Cache: cache}
+
+	fresh, err := AnalyzeFileWithOptions(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := AnalyzeFileWithOptions(path, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := cache.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Writes != 1 || stats.Entries != 1 {
+		t.Fatalf("stats=%+v", stats)
+	}
+	if got, want := RenderJSON([]Report{cached}), RenderJSON([]Report{fresh}); got != want {
+		t.Fatalf("cached JSON differs:\n%s\nwant:\n%s", got, want)
+	}
+	if got, want := RenderText([]Report{cached}), RenderText([]Report{fresh}); got != want {
+		t.Fatalf("cached text differs:\n%s\nwant:\n%s", got, want)
+	}
+
+	// The same inode under another name is still a hit, reported under the new name.
+	moved := filepath.Join(filepath.Dir(path), "renamed.mkv")
+	if err := os.Rename(path, moved); err != nil {
+		t.Fatal(err)
+	}
+	report, ok := cache.Lookup(moved, opts)
+	if !ok || report.Ref != moved || findField(report.General.Fields, "Complete name") != moved {
+		t.Fatalf("rename: ok=%v ref=%q", ok, report.Ref)
+	}
+
+	if _, ok := cache.Lookup(moved, AnalyzeOptions{ParseSpeed: 1, HasParseSpeed: true}); ok {
+		t.Fatalf("hit with a different ParseSpeed")
+	}
+	later := time.Now().Add(time.Hour)
+	if err := os.Chtimes(moved, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Lookup(moved, opts); ok {
+		t.Fatalf("hit after the modification time changed")
+	}
+	if removed, err := cache.Prune(); err != nil || removed != 1 {
+		t.Fatalf("Prune removed %d (err %v), want the outdated entry", removed, err)
+	}
+}
+
+func TestReportCacheSkipsPairedSegments(t *testing.T) {
+	cache, err := OpenReportCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	var init bytes.Buffer
+	writeMP4Box(&init, "ftyp", []byte{'i', 's', 'o', '6', 0, 0, 0, 0})
+	init.Write(buildFragmentedMoov(0))
+	if err := os.WriteFile(filepath.Join(dir, "init.mp4"), init.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var seg bytes.Buffer
+	writeFragment(&seg, 1, 25)
+	path := filepath.Join(dir, "segment.mp4")
+	if err := os.WriteFile(path, seg.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := AnalyzeFileWithOptions(path, AnalyzeOptions{Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := findField(report.General.Fields, "Format"); got != "MPEG-4" {
+		t.Fatalf("Format=%q, want the segment paired with init.mp4", got)
+	}
+	if stats, _ := cache.Stats(); stats.Writes != 0 || stats.Entries != 0 {
+		t.Fatalf("a report depending on init.mp4 was cached: %+v", stats)
+	}
+}
+
+func TestReportCacheVersionChange(t *testing.T) {
+	cache, err := OpenReportCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := copyTestSample(t, "sample.mp3")
+	if _, err := AnalyzeFileWithOptions(path, AnalyzeOptions{Cache: cache}); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := AppVersion
+	AppVersion = previous + "-next"
+	defer func() { AppVersion = previous }()
+
+	if _, ok := cache.Lookup(path, AnalyzeOptions{}); ok {
+		t.Fatalf("entry from another version was served")
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 1 || stats.Stale != 1 {
+		t.Fatalf("stats=%+v, want one stale entry", stats)
+	}
+	if removed, _ := cache.Prune(); removed != 1 {
+		t.Fatalf("Prune removed %d, want 1", removed)
+	}
+	stored, err := cache.Rebuild(t.Context(), []string{filepath.Dir(path)}, ScanOptions{}, AnalyzeOptions{})
+	if err != nil || stored != 1 {
+		t.Fatalf("Rebuild stored %d (err %v)", stored, err)
+	}
+	if _, ok := cache.Lookup(path, AnalyzeOptions{}); !ok {
+		t.Fatalf("no entry after Rebuild")
+	}
+}
+
+func TestReportCachePurgeAndCorruption(t *testing.T) {
+	dir := t.TempDir()
+	unrelated := filepath.Join(dir, "notes.json")
+	os.WriteFile(unrelated, []byte("{}"), 0o644)
+	cache, err := OpenReportCache(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := copyTestSample(t, "sample.flac")
+	report, err := AnalyzeFileWithOptions(path, AnalyzeOptions{Cache: cache})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, _ := os.Stat(path)
+	key, _ := newCacheKey(path, info, AnalyzeOptions{})
+	if err := os.WriteFile(cache.entryPath(key), []byte("{truncated"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cache.Lookup(path, AnalyzeOptions{}); ok {
+		t.Fatalf("corrupt entry was served")
+	}
+	if _, err := os.Stat(cache.entryPath(key)); !os.IsNotExist(err) {
+		t.Fatalf("corrupt entry was kept")
+	}
+
+	if err := cache.Store(path, AnalyzeOptions{}, report); err != nil {
+		t.Fatal(err)
+	}
+	if err := cache.Purge(); err != nil {
+		t.Fatal(err)
+	}
+	if stats, _ := cache.Stats(); stats.Entries != 0 {
+		t.Fatalf("entries=%d after Purge", stats.Entries)
+	}
+	if _, err := os.Stat(unrelated); err != nil {
+		t.Fatalf("Purge removed a file it does not own: %v", err)
+	}
+}
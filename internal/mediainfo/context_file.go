@@ -0,0 +1,53 @@
+package mediainfo
+
+import (
+	"context"
+	"io"
+)
+
+// contextFile ties a mediaFile to a context: once ctx is done every read fails with ctx.Err().
+// Parsers treat read errors as end of data, so cancellation unwinds them and they return
+// whatever they parsed so far.
+type contextFile struct {
+	ctx  context.Context
+	file mediaFile
+}
+
+func newContextFile(ctx context.Context, file mediaFile) mediaFile {
+	if ctx.Done() == nil {
+		// Background/TODO contexts can never be canceled; skip the per-read check.
+		return file
+	}
+	return contextFile{ctx: ctx, file: file}
+}
+
+func (f contextFile) Read(p []byte) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.file.Read(p)
+}
+
+func (f contextFile) ReadAt(p []byte, off int64) (int, error) {
+	if err := f.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return f.file.ReadAt(p, off)
+}
+
+func (f contextFile) Seek(offset int64, whence int) (int64, error) {
+	return f.file.Seek(offset, whence)
+}
+
+// readerCanceled reports whether r (or the ReaderAt under a SectionReader) is bound to a
+// context that is done. Long scan loops use it to stop between buffered reads.
+func readerCanceled(r any) bool {
+	switch v := r.(type) {
+	case contextFile:
+		return v.ctx.Err() != nil
+	case *io.SectionReader:
+		base, _, _ := v.Outer()
+		return readerCanceled(base)
+	}
+	return false
+}
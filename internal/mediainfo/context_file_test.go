@@ -0,0 +1,73 @@
+package mediainfo
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// cancelAfterReader cancels its context once more than limit reads have been served.
+type cancelAfterReader struct {
+	r      *bytes.Reader
+	cancel context.CancelFunc
+	limit  int
+	reads  int
+}
+
+func (c *cancelAfterReader) ReadAt(p []byte, off int64) (int, error) {
+	c.reads++
+	if c.reads > c.limit {
+		c.cancel()
+	}
+	return c.r.ReadAt(p, off)
+}
+
+func TestAnalyzeReaderContextCanceledMidParse(t *testing.T) {
+	for _, name := range []string{"sample.ts", "sample.mkv"} {
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("samples", name))
+			if err != nil {
+				t.Fatalf("read sample: %v", err)
+			}
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			src := &cancelAfterReader{r: bytes.NewReader(data), cancel: cancel, limit: 2}
+
+			report, err := AnalyzeReaderContext(ctx, src, int64(len(data)), name, defaultAnalyzeOptions())
+			if !errors.Is(err, context.Canceled) {
+				t.Fatalf("err=%v, want context.Canceled", err)
+			}
+			if !report.Incomplete {
+				t.Fatalf("Incomplete=false, want true")
+			}
+			if got := findField(report.General.Fields, "Complete name"); got != name {
+				t.Fatalf("Complete name=%q, want %q", got, name)
+			}
+		})
+	}
+}
+
+func TestAnalyzeFileContextAlreadyCanceled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := AnalyzeFileContext(ctx, filepath.Join("samples", "sample.mp4"), defaultAnalyzeOptions()); !errors.Is(err, context.Canceled) {
+		t.Fatalf("err=%v, want context.Canceled", err)
+	}
+	reports, count, err := AnalyzeFilesContext(ctx, []string{filepath.Join("samples", "sample.mp4")}, ScanOptions{}, defaultAnalyzeOptions())
+	if !errors.Is(err, context.Canceled) || count != 0 || len(reports) != 0 {
+		t.Fatalf("reports=%d count=%d err=%v, want none and context.Canceled", len(reports), count, err)
+	}
+}
+
+func TestAnalyzeFileContextCompletes(t *testing.T) {
+	report, err := AnalyzeFileContext(context.Background(), filepath.Join("samples", "sample.mkv"), defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if report.Incomplete {
+		t.Fatalf("Incomplete=true, want false")
+	}
+}
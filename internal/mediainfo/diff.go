@@ -0,0 +1,238 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DiffChange classifies a FieldDiff.
+type DiffChange string
+
+const (
+	// DiffMissing marks a field or track present on the left side only.
+	DiffMissing DiffChange = "missing"
+	// DiffExtra marks a field or track present on the right side only.
+	DiffExtra DiffChange = "extra"
+	// DiffChanged marks a field whose values differ beyond the tolerance.
+	DiffChanged DiffChange = "changed"
+)
+
+// DiffOptions tunes DiffReports. Tolerances are relative (0.01 accepts a 1% difference) and only
+// apply when both values are numbers; FieldTolerances overrides Tolerance per field name.
+type DiffOptions struct {
+	Tolerance       float64
+	FieldTolerances map[string]float64
+	// Ignore lists field names to skip, such as File_Modified_Date.
+	Ignore []string
+}
+
+// FieldDiff is one difference between two reports. Field is empty when a whole track is missing
+// or extra. Fields inside "extra" are named "extra/<name>".
+type FieldDiff struct {
+	Track     string     `json:"track"`
+	Kind      StreamKind `json:"kind"`
+	TypeOrder int        `json:"typeorder,omitempty"`
+	Field     string     `json:"field,omitempty"`
+	Change    DiffChange `json:"change"`
+	Left      string     `json:"left,omitempty"`
+	Right     string     `json:"right,omitempty"`
+}
+
+// ReportDiff is the result of DiffReports.
+type ReportDiff struct {
+	Left   string      `json:"left"`
+	Right  string      `json:"right"`
+	Fields []FieldDiff `json:"differences"`
+}
+
+// Equal reports whether no differences were found.
+func (d ReportDiff) Equal() bool {
+	return len(d.Fields) == 0
+}
+
+type diffTrack struct {
+	title     string
+	kind      StreamKind
+	typeOrder int
+	fields    []jsonKV
+}
+
+// DiffReports compares two reports field by field, matching tracks by stream kind and type order.
+// Values are compared in their raw (JSON) form, so reports parsed with ParseJSONReport or
+// ParseXMLReport compare directly with freshly analyzed files.
+func DiffReports(left, right Report, opts DiffOptions) ReportDiff {
+	result := ReportDiff{Left: left.Ref, Right: right.Ref}
+	ignore := map[string]bool{}
+	for _, name := range opts.Ignore {
+		ignore[name] = true
+	}
+	leftTracks := diffTracks(left)
+	rightTracks := diffTracks(right)
+	rightByTitle := map[string]diffTrack{}
+	for _, track := range rightTracks {
+		rightByTitle[track.title] = track
+	}
+	matched := map[string]bool{}
+	for _, lt := range leftTracks {
+		rt, ok := rightByTitle[lt.title]
+		if !ok {
+			result.Fields = append(result.Fields, FieldDiff{Track: lt.title, Kind: lt.kind, TypeOrder: lt.typeOrder, Change: DiffMissing})
+			continue
+		}
+		matched[lt.title] = true
+		result.Fields = append(result.Fields, diffTrackFields(lt, rt, ignore, opts)...)
+	}
+	for _, rt := range rightTracks {
+		if !matched[rt.title] {
+			result.Fields = append(result.Fields, FieldDiff{Track: rt.title, Kind: rt.kind, TypeOrder: rt.typeOrder, Change: DiffExtra})
+		}
+	}
+	return result
+}
+
+func diffTracks(report Report) []diffTrack {
+	tracks := []diffTrack{{title: string(StreamGeneral), kind: StreamGeneral, fields: flattenDiffFields(buildJSONGeneralFields(report))}}
+	containerFormat := findField(report.General.Fields, "Format")
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, index, total, order int) {
+		typeOrder := 0
+		if total > 1 {
+			typeOrder = index
+		}
+		tracks = append(tracks, diffTrack{
+			// Titles always carry the index so "Audio #1" still matches when the other side has
+			// a second audio track.
+			title:     fmt.Sprintf("%s #%d", stream.Kind, index),
+			kind:      stream.Kind,
+			typeOrder: typeOrder,
+			fields:    flattenDiffFields(buildJSONStreamFields(stream, order, 0, containerFormat)),
+		})
+	})
+	return tracks
+}
+
+// flattenDiffFields drops the @ attributes and expands raw objects into "key/name" fields.
+func flattenDiffFields(fields []jsonKV) []jsonKV {
+	var out []jsonKV
+	for _, field := range fields {
+		if strings.HasPrefix(field.Key, "@") {
+			continue
+		}
+		if !field.Raw {
+			out = append(out, field)
+			continue
+		}
+		parsed, err := parseOrderedJSON(field.Val)
+		if err != nil || parsed.kind != orderedObject {
+			out = append(out, field)
+			continue
+		}
+		for _, kv := range parsed.obj {
+			value := kv.val.str
+			if kv.val.kind != orderedString {
+				value = renderOrderedJSON(kv.val)
+			}
+			out = append(out, jsonKV{Key: field.Key + "/" + kv.key, Val: value})
+		}
+	}
+	return out
+}
+
+func diffTrackFields(left, right diffTrack, ignore map[string]bool, opts DiffOptions) []FieldDiff {
+	var out []FieldDiff
+	add := func(name string, change DiffChange, l, r string) {
+		out = append(out, FieldDiff{Track: left.title, Kind: left.kind, TypeOrder: left.typeOrder, Field: name, Change: change, Left: l, Right: r})
+	}
+	rightValues := map[string]string{}
+	for _, field := range right.fields {
+		if _, exists := rightValues[field.Key]; !exists {
+			rightValues[field.Key] = field.Val
+		}
+	}
+	seen := map[string]bool{}
+	for _, field := range left.fields {
+		if ignore[field.Key] || seen[field.Key] {
+			continue
+		}
+		seen[field.Key] = true
+		value, ok := rightValues[field.Key]
+		switch {
+		case !ok:
+			add(field.Key, DiffMissing, field.Val, "")
+		case !diffValuesEqual(field.Key, field.Val, value, opts):
+			add(field.Key, DiffChanged, field.Val, value)
+		}
+	}
+	for _, field := range right.fields {
+		if ignore[field.Key] || seen[field.Key] {
+			continue
+		}
+		seen[field.Key] = true
+		add(field.Key, DiffExtra, "", field.Val)
+	}
+	return out
+}
+
+func diffValuesEqual(name, left, right string, opts DiffOptions) bool {
+	if left == right {
+		return true
+	}
+	tolerance := opts.Tolerance
+	if fieldTolerance, ok := opts.FieldTolerances[name]; ok {
+		tolerance = fieldTolerance
+	}
+	if tolerance <= 0 {
+		return false
+	}
+	a, errA := strconv.ParseFloat(left, 64)
+	b, errB := strconv.ParseFloat(right, 64)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return math.Abs(a-b) <= tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// RenderDiffText renders differences grouped by track: "-" missing on the right, "+" extra on
+// the right, "~" changed.
+func RenderDiffText(d ReportDiff) string {
+	var buf bytes.Buffer
+	buf.WriteString("--- " + d.Left + "\n")
+	buf.WriteString("+++ " + d.Right + "\n")
+	if d.Equal() {
+		buf.WriteString("No differences\n")
+		return buf.String()
+	}
+	track := ""
+	for _, field := range d.Fields {
+		if field.Track != track {
+			track = field.Track
+			buf.WriteString(track + "\n")
+		}
+		marker := map[DiffChange]string{DiffMissing: "-", DiffExtra: "+", DiffChanged: "~"}[field.Change]
+		switch {
+		case field.Field == "":
+			buf.WriteString(fmt.Sprintf("  %s (track)\n", marker))
+		case field.Change == DiffChanged:
+			buf.WriteString(fmt.Sprintf("  %s %s: %s -> %s\n", marker, field.Field, field.Left, field.Right))
+		default:
+			buf.WriteString(fmt.Sprintf("  %s %s: %s\n", marker, field.Field, field.Left+field.Right))
+		}
+	}
+	buf.WriteString(fmt.Sprintf("%d difference(s)\n", len(d.Fields)))
+	return buf.String()
+}
+
+// RenderDiffJSON renders the diff as a JSON document.
+func RenderDiffJSON(d ReportDiff) string {
+	if d.Fields == nil {
+		d.Fields = []FieldDiff{}
+	}
+	data, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(data) + "\n"
+}
@@ -0,0 +1,107 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func diffTestReport(duration, channels string, extraAudio bool) Report {
+	report := Report{
+		Ref: "a.mkv",
+		General: Stream{Kind: StreamGeneral, parsedJSON: []jsonKV{
+			{Key: "Format", Val: "Matroska"},
+			{Key: "Duration", Val: duration},
+		}},
+		Streams: []Stream{
+			{Kind: StreamAudio, parsedJSON: []jsonKV{
+				{Key: "Format", Val: "AC-3"},
+				{Key: "Channels", Val: channels},
+				{Key: "extra", Val: `{"dialnorm":"-27","bsid":"8"}`, Raw: true},
+			}},
+		},
+	}
+	if extraAudio {
+		report.Streams = append(report.Streams, Stream{Kind: StreamAudio, parsedJSON: []jsonKV{{Key: "Format", Val: "AAC"}}})
+	}
+	return report
+}
+
+func TestDiffReports(t *testing.T) {
+	left := diffTestReport("4.021", "6", false)
+	right := diffTestReport("4.025", "6", true)
+	right.Streams[0].parsedJSON[2].Val = `{"dialnorm":"-31","bsid":"8"}`
+	right.General.parsedJSON = append(right.General.parsedJSON, jsonKV{Key: "Title", Val: "New"})
+
+	result := DiffReports(left, right, DiffOptions{})
+	var got []string
+	for _, field := range result.Fields {
+		got = append(got, field.Track+"|"+field.Field+"|"+string(field.Change))
+	}
+	want := []string{
+		"General|Duration|changed",
+		"General|Title|extra",
+		"Audio #1|extra/dialnorm|changed",
+		"Audio #2||extra",
+	}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("diff=%v, want %v", got, want)
+	}
+
+	reverse := DiffReports(right, left, DiffOptions{})
+	if reverse.Fields[1].Change != DiffMissing || reverse.Fields[3].Change != DiffMissing {
+		t.Fatalf("reverse diff should report missing fields and tracks: %+v", reverse.Fields)
+	}
+}
+
+func TestDiffTolerance(t *testing.T) {
+	left := diffTestReport("4.021", "6", false)
+	right := diffTestReport("4.025", "2", false)
+	cases := []struct {
+		opts DiffOptions
+		want int
+	}{
+		{DiffOptions{}, 2},
+		{DiffOptions{Tolerance: 0.01}, 1},
+		{DiffOptions{FieldTolerances: map[string]float64{"Duration": 0.0001}}, 2},
+		{DiffOptions{Tolerance: 0.01, Ignore: []string{"Channels"}}, 0},
+	}
+	for i, tc := range cases {
+		if got := len(DiffReports(left, right, tc.opts).Fields); got != tc.want {
+			t.Fatalf("case %d: %d differences, want %d", i, got, tc.want)
+		}
+	}
+}
+
+func TestRenderDiff(t *testing.T) {
+	result := DiffReports(diffTestReport("4.021", "6", true), diffTestReport("4.021", "2", false), DiffOptions{})
+	text := RenderDiffText(result)
+	for _, want := range []string{"--- a.mkv\n", "Audio #1\n  ~ Channels: 6 -> 2\n", "Audio #2\n  - (track)\n", "2 difference(s)\n"} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("missing %q in:\n%s", want, text)
+		}
+	}
+	var decoded ReportDiff
+	if err := json.Unmarshal([]byte(RenderDiffJSON(result)), &decoded); err != nil || len(decoded.Fields) != 2 {
+		t.Fatalf("JSON diff: %v %+v", err, decoded)
+	}
+
+	same := DiffReports(diffTestReport("1", "2", false), diffTestReport("1", "2", false), DiffOptions{})
+	if !same.Equal() || !strings.Contains(RenderDiffText(same), "No differences") || !strings.Contains(RenderDiffJSON(same), `"differences": []`) {
+		t.Fatalf("equal reports should render no differences")
+	}
+}
+
+func TestDiffAnalyzedAgainstParsed(t *testing.T) {
+	report, err := AnalyzeFile("samples/sample.mp4")
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := ParseXMLReport([]byte(RenderXML([]Report{report})))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result := DiffReports(report, parsed[0], DiffOptions{}); !result.Equal() {
+		t.Fatalf("analyzed file differs from its own XML report:\n%s", RenderDiffText(result))
+	}
+}
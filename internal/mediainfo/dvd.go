@@ -67,7 +67,7 @@ type dvdMenuLists struct {
 	subPanScan string
 }
 
-func parseDVDVideo(path string, file *os.File, size int64, opts AnalyzeOptions) (dvdInfo, bool) {
+func parseDVDVideo(path string, file io.ReadSeeker, size int64, opts AnalyzeOptions) (dvdInfo, bool) {
 	if _, err := file.Seek(0, io.SeekStart); err != nil {
 		return dvdInfo{}, false
 	}
@@ -414,7 +414,7 @@ func parseDVDVideo(path string, file *os.File, size int64, opts AnalyzeOptions)
 	return info, true
 }
 
-func readSizedFile(file *os.File, size int64) ([]byte, error) {
+func readSizedFile(file io.Reader, size int64) ([]byte, error) {
 	if size <= 0 {
 		return io.ReadAll(file)
 	}
@@ -984,6 +984,24 @@ func appendJSONExtra(raw string, key string, value string) string {
 	return raw
 }
 
+// mergeJSONExtra appends fields to a rendered extra object, escaping values the way
+// renderJSONObject does (tag values may carry arbitrary text).
+func mergeJSONExtra(raw string, fields []jsonKV) string {
+	if len(fields) == 0 {
+		return raw
+	}
+	rendered := renderJSONObject(fields, false)
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == "{}" {
+		return rendered
+	}
+	before, ok := strings.CutSuffix(raw, "}")
+	if !ok {
+		return raw
+	}
+	return before + "," + rendered[1:]
+}
+
 func findStreamField(streams []Stream, kind StreamKind, name string) string {
 	for _, stream := range streams {
 		if stream.Kind != kind {
@@ -0,0 +1,153 @@
+package mediainfo
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ScanOptions controls how directories are expanded into files. Filters only apply to files
+// found inside directories; files named explicitly are always kept.
+type ScanOptions struct {
+	// Recursive descends into subdirectories (symlinked ones included, loops are skipped).
+	Recursive bool
+	// MaxDepth limits how many directory levels below each root are entered when Recursive
+	// is set (0 = unlimited).
+	MaxDepth int
+	// Include keeps only files whose base name matches one of these globs (case-insensitive).
+	Include []string
+	// Exclude drops files whose base name matches one of these globs (case-insensitive).
+	Exclude []string
+	// SkipUnknown drops files whose header DetectFormat does not recognize.
+	SkipUnknown bool
+	// OnError, when set, is told about subdirectories that cannot be resolved or read. They
+	// are skipped either way; only an unreadable argument fails ExpandPaths.
+	OnError func(path string, err error)
+}
+
+// ExpandPaths turns file and directory arguments into the list of files to analyze, in
+// argument order with each directory listed in lexical order.
+func ExpandPaths(paths []string, opts ScanOptions) ([]string, error) {
+	for _, pattern := range append(append([]string{}, opts.Include...), opts.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+		}
+	}
+	expanded := make([]string, 0, len(paths))
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			expanded = append(expanded, path)
+			continue
+		}
+		visited := map[string]struct{}{}
+		expanded, err = expandDir(expanded, path, 0, opts, visited)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return expanded, nil
+}
+
+func expandDir(expanded []string, dir string, depth int, opts ScanOptions, visited map[string]struct{}) ([]string, error) {
+	resolved, err := filepath.EvalSymlinks(dir)
+	if err != nil {
+		return nil, err
+	}
+	if _, seen := visited[resolved]; seen {
+		// Symlink loop (or the same directory reached twice).
+		return expanded, nil
+	}
+	visited[resolved] = struct{}{}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(entries))
+	isDir := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		name := entry.Name()
+		dirEntry := entry.IsDir()
+		if entry.Type()&os.ModeSymlink != 0 {
+			target, err := os.Stat(filepath.Join(dir, name))
+			if err != nil {
+				// Dangling symlink.
+				continue
+			}
+			dirEntry = target.IsDir()
+		}
+		names = append(names, name)
+		isDir[name] = dirEntry
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		if isDir[name] {
+			if !opts.Recursive || (opts.MaxDepth > 0 && depth >= opts.MaxDepth) {
+				continue
+			}
+			next, err := expandDir(expanded, path, depth+1, opts, visited)
+			if err != nil {
+				if opts.OnError != nil {
+					opts.OnError(path, err)
+				}
+				continue
+			}
+			expanded = next
+			continue
+		}
+		if !scanNameAllowed(name, opts) {
+			continue
+		}
+		if opts.SkipUnknown && !hasKnownFormat(path) {
+			continue
+		}
+		expanded = append(expanded, path)
+	}
+	return expanded, nil
+}
+
+func scanNameAllowed(name string, opts ScanOptions) bool {
+	lower := strings.ToLower(name)
+	if len(opts.Include) > 0 && !matchAnyPattern(lower, opts.Include) {
+		return false
+	}
+	return !matchAnyPattern(lower, opts.Exclude)
+}
+
+func matchAnyPattern(lowerName string, patterns []string) bool {
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(strings.ToLower(pattern), lowerName); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func hasKnownFormat(path string) bool {
+	format, err := DetectFileFormat(path)
+	// Unreadable files are kept; the analysis step reports the error.
+	return err != nil || format != "Unknown"
+}
+
+// DetectFileFormat runs DetectFormat on the first bytes of the file at path.
+func DetectFileFormat(path string) (string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	header := make([]byte, maxSniffBytes)
+	n, err := io.ReadFull(file, header)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	return DetectFormat(header[:n], path), nil
+}
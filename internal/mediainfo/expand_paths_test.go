@@ -0,0 +1,157 @@
+package mediainfo
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeScanTree(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	mkv, err := os.ReadFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("read sample: %v", err)
+	}
+	files := map[string][]byte{
+		"a.mkv":                            mkv,
+		"release.nfo":                      []byte("not media"),
+		"Show/Season 01/S01E01.mkv":        mkv,
+		"Show/Season 01/S01E01.srr":        []byte("not media"),
+		"Show/Season 01/Extras/Deep.MKV":   mkv,
+		"Show/Season 02/notes.txt":         []byte("not media"),
+		"Show/Season 02/S02E01.unknownext": mkv,
+	}
+	for name, data := range files {
+		path := filepath.Join(root, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("mkdir: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	// Symlink back to the root: must not loop.
+	if err := os.Symlink(root, filepath.Join(root, "Show", "loop")); err != nil {
+		t.Skipf("symlinks unsupported: %v", err)
+	}
+	return root
+}
+
+func relPaths(t *testing.T, root string, paths []string) []string {
+	t.Helper()
+	out := make([]string, 0, len(paths))
+	for _, path := range paths {
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			t.Fatalf("rel: %v", err)
+		}
+		out = append(out, filepath.ToSlash(rel))
+	}
+	return out
+}
+
+func TestExpandPathsScanOptions(t *testing.T) {
+	root := writeScanTree(t)
+	cases := []struct {
+		name string
+		opts ScanOptions
+		want []string
+	}{
+		{
+			name: "flat",
+			opts: ScanOptions{},
+			want: []string{"a.mkv", "release.nfo"},
+		},
+		{
+			name: "recursive",
+			opts: ScanOptions{Recursive: true},
+			want: []string{
+				"Show/Season 01/Extras/Deep.MKV",
+				"Show/Season 01/S01E01.mkv",
+				"Show/Season 01/S01E01.srr",
+				"Show/Season 02/S02E01.unknownext",
+				"Show/Season 02/notes.txt",
+				"a.mkv",
+				"release.nfo",
+			},
+		},
+		{
+			name: "max depth",
+			opts: ScanOptions{Recursive: true, MaxDepth: 2, Include: []string{"*.mkv"}},
+			want: []string{"Show/Season 01/S01E01.mkv", "a.mkv"},
+		},
+		{
+			name: "include exclude",
+			opts: ScanOptions{Recursive: true, Include: []string{"*.mkv", "*.txt"}, Exclude: []string{"deep*"}},
+			want: []string{"Show/Season 01/S01E01.mkv", "Show/Season 02/notes.txt", "a.mkv"},
+		},
+		{
+			name: "skip unknown",
+			opts: ScanOptions{Recursive: true, SkipUnknown: true},
+			want: []string{
+				"Show/Season 01/Extras/Deep.MKV",
+				"Show/Season 01/S01E01.mkv",
+				"Show/Season 02/S02E01.unknownext",
+				"a.mkv",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := ExpandPaths([]string{root}, tc.opts)
+			if err != nil {
+				t.Fatalf("ExpandPaths: %v", err)
+			}
+			if rel := relPaths(t, root, got); !reflect.DeepEqual(rel, tc.want) {
+				t.Fatalf("got %q, want %q", rel, tc.want)
+			}
+		})
+	}
+}
+
+func TestExpandPathsKeepsExplicitFiles(t *testing.T) {
+	root := writeScanTree(t)
+	nfo := filepath.Join(root, "release.nfo")
+	got, err := ExpandPaths([]string{nfo}, ScanOptions{Include: []string{"*.mkv"}, SkipUnknown: true})
+	if err != nil {
+		t.Fatalf("ExpandPaths: %v", err)
+	}
+	if len(got) != 1 || got[0] != nfo {
+		t.Fatalf("got %q, want [%q]", got, nfo)
+	}
+}
+
+func TestExpandPathsInvalidPattern(t *testing.T) {
+	if _, err := ExpandPaths([]string{"samples"}, ScanOptions{Include: []string{"["}}); err == nil {
+		t.Fatalf("expected error for invalid pattern")
+	}
+}
+
+func TestExpandPathsSkipsUnreadableSubdir(t *testing.T) {
+	root := writeScanTree(t)
+	locked := filepath.Join(root, "Show", "Season 02")
+	if err := os.Chmod(locked, 0); err != nil {
+		t.Fatalf("chmod: %v", err)
+	}
+	defer os.Chmod(locked, 0o755)
+	if _, err := os.ReadDir(locked); err == nil {
+		t.Skip("directory permissions are not enforced (running as root?)")
+	}
+
+	var reported []string
+	opts := ScanOptions{Recursive: true, Include: []string{"*.mkv"}}
+	opts.OnError = func(path string, err error) { reported = append(reported, path) }
+	got, err := ExpandPaths([]string{root}, opts)
+	if err != nil {
+		t.Fatalf("ExpandPaths: %v", err)
+	}
+	want := []string{"Show/Season 01/Extras/Deep.MKV", "Show/Season 01/S01E01.mkv", "a.mkv"}
+	if rel := relPaths(t, root, got); !reflect.DeepEqual(rel, want) {
+		t.Fatalf("got %q, want %q", rel, want)
+	}
+	if len(reported) != 1 || reported[0] != locked {
+		t.Fatalf("reported %q, want [%q]", reported, locked)
+	}
+}
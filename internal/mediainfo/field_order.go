@@ -16,18 +16,40 @@ var generalFieldOrder = map[string]int{
 	"Overall bit rate mode": 9,
 	"Overall bit rate":      10,
 	"Frame rate":            11,
-	"Writing application":   12,
-	"Writing library":       13,
-	"Encoded date":          14,
-	"Tagged date":           15,
-	"FileExtension_Invalid": 16,
-	"Conformance warnings":  17,
-	" General compliance":   18,
+	"Title":                 12,
+	"Collection":            13,
+	"Album":                 14,
+	"Album/Performer":       15,
+	"Part/Position":         16,
+	"Part/Total":            17,
+	"Track name/Position":   18,
+	"Track name/Total":      19,
+	"Grouping":              20,
+	"Performer":             21,
+	"Composer":              22,
+	"Service name":          23,
+	"Genre":                 24,
+	"Content type":          25,
+	"Law rating":            26,
+	"Recorded date":         27,
+	"Writing application":   28,
+	"Writing library":       29,
+	"Encoded date":          30,
+	"Tagged date":           31,
+	"Copyright":             32,
+	"Cover":                 33,
+	"Cover MIME":            34,
+	"Lyrics":                35,
+	"Comment":               36,
+	"FileExtension_Invalid": 37,
+	"Conformance warnings":  38,
+	" General compliance":   39,
 }
 
 var streamFieldOrder = map[string]int{
 	"ID":                                0,
 	"Menu ID":                           1,
+	"Type":                              1,
 	"Format":                            2,
 	"Format/Info":                       3,
 	"Commercial name":                   4,
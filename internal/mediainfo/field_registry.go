@@ -0,0 +1,371 @@
+package mediainfo
+
+import "strings"
+
+// FieldInfo describes one parameter the analyzer can report for a stream kind.
+type FieldInfo struct {
+	Kind        StreamKind `json:"kind"`
+	Name        string     `json:"name"`
+	Text        string     `json:"text,omitempty"`
+	Type        string     `json:"type"`
+	Description string     `json:"description"`
+}
+
+// Parameter value types, as rendered in JSON/XML (text output uses the display form).
+const (
+	fieldString   = "string"
+	fieldInteger  = "integer"
+	fieldFloat    = "float"
+	fieldDuration = "duration"
+	fieldDate     = "date"
+	fieldBoolean  = "boolean"
+	fieldObject   = "object"
+)
+
+// fieldDef is one registry row. kinds lists the stream kinds by initial (General, Video, Audio,
+// Text, Other, Image, Menu); name is the JSON/XML key and Inform name, text the text output label.
+type fieldDef struct {
+	kinds       string
+	name        string
+	text        string
+	typ         string
+	description string
+}
+
+var fieldDefs = []fieldDef{
+	// Identification
+	{"VATOIM", "StreamOrder", "", fieldString, "Order of the stream in the container"},
+	{"VATIM", "FirstPacketOrder", "", fieldInteger, "Order of the first packet of this stream in the container"},
+	{"GVATOIM", "ID", "ID", fieldString, "Identifier of the stream in the container"},
+	{"VATM", "MenuID", "Menu ID", fieldString, "Identifier of the program this stream belongs to"},
+	{"GVATI", "UniqueID", "Unique ID", fieldString, "Unique identifier of the file or stream"},
+	{"G", "VideoCount", "", fieldInteger, "Count of video streams"},
+	{"G", "AudioCount", "", fieldInteger, "Count of audio streams"},
+	{"G", "TextCount", "", fieldInteger, "Count of text streams"},
+	{"G", "OtherCount", "", fieldInteger, "Count of other streams"},
+	{"G", "ImageCount", "", fieldInteger, "Count of image streams"},
+	{"G", "MenuCount", "", fieldInteger, "Count of menu streams"},
+
+	// File
+	{"G", "CompleteName", "Complete name", fieldString, "Full path of the file"},
+	{"G", "CompleteName_Last", "CompleteName_Last", fieldString, "Full path of the last file of a sequence"},
+	{"G", "FileExtension", "", fieldString, "File extension"},
+	{"G", "FileExtension_Invalid", "FileExtension_Invalid", fieldString, "Extensions usually used for this format when the current one is not"},
+	{"G", "FileSize", "File size", fieldInteger, "File size in bytes"},
+	{"G", "File_Created_Date", "", fieldDate, "File creation date (UTC)"},
+	{"G", "File_Created_Date_Local", "", fieldDate, "File creation date (local time)"},
+	{"G", "File_Modified_Date", "", fieldDate, "File last modification date (UTC)"},
+	{"G", "File_Modified_Date_Local", "", fieldDate, "File last modification date (local time)"},
+	{"G", "HeaderSize", "", fieldInteger, "Size of the container header in bytes"},
+	{"G", "DataSize", "", fieldInteger, "Size of the payload in bytes"},
+	{"G", "FooterSize", "", fieldInteger, "Size of the container footer in bytes"},
+	{"G", "IsStreamable", "", fieldBoolean, "Whether the file can be played while it is being downloaded"},
+	{"G", "IsFragmented", "", fieldBoolean, "Whether the samples are stored in movie fragments (fragmented MP4)"},
+	{"G", "FragmentCount", "", fieldInteger, "Count of movie fragments"},
+	{"G", "FragmentDuration", "", fieldDuration, "Duration of the first movie fragment"},
+	{"G", "BytesFetched", "", fieldInteger, "Bytes downloaded from the remote source"},
+	{"G", "Interleaved", "", fieldBoolean, "Whether audio and video are interleaved"},
+	{"G", "ErrorDetectionType", "ErrorDetectionType", fieldString, "Error detection mechanism used by the container"},
+
+	// Format
+	{"GVATOIM", "Format", "Format", fieldString, "Format used"},
+	{"GVATIM", "Format/Info", "Format/Info", fieldString, "Long name of the format"},
+	{"GVATI", "Format_Commercial_IfAny", "Commercial name", fieldString, "Commercial name of the format, if any"},
+	{"GVATI", "Format_Version", "Format version", fieldString, "Version of the format"},
+	{"GVAI", "Format_Profile", "Format profile", fieldString, "Profile of the format"},
+	{"VA", "Format_Level", "Format level", fieldString, "Level of the format"},
+	{"V", "Format_Tier", "Format tier", fieldString, "Tier of the format"},
+	{"A", "Format_AdditionalFeatures", "", fieldString, "Additional features of the format (e.g. LC, SBR, JOC)"},
+	{"GVA", "Format_Settings", "Format settings", fieldString, "Summary of the format settings"},
+	{"V", "Format_Settings_CABAC", "Format settings, CABAC", fieldBoolean, "Whether CABAC entropy coding is used"},
+	{"V", "Format_Settings_RefFrames", "Format settings, Reference frames", fieldInteger, "Count of reference frames"},
+	{"V", "Format_Settings_BVOP", "Format settings, BVOP", fieldBoolean, "Whether B-VOPs are used"},
+	{"V", "Format_Settings_QPel", "Format settings, QPel", fieldBoolean, "Whether quarter-pixel motion is used"},
+	{"V", "Format_Settings_GMC", "Format settings, GMC", fieldInteger, "Count of global motion compensation warp points"},
+	{"V", "Format_Settings_Matrix", "Format settings, Matrix", fieldString, "Quantization matrix in use"},
+	{"V", "Format_Settings_Matrix_Data", "", fieldString, "Custom quantization matrix data"},
+	{"V", "Format_Settings_GOP", "Format settings, GOP", fieldString, "GOP structure (M and N)"},
+	{"V", "Format_Settings_PictureStructure", "Format settings, Picture structure", fieldString, "Picture structure (frame or field)"},
+	{"V", "Format_Settings_SliceCount", "Format settings, Slice count", fieldInteger, "Count of slices per frame"},
+	{"A", "Format_Settings_Endianness", "Format settings, Endianness", fieldString, "Byte order of PCM samples"},
+	{"A", "Format_Settings_Sign", "Format settings, Sign", fieldString, "Whether PCM samples are signed"},
+	{"A", "Format_Settings_SBR", "", fieldString, "Whether spectral band replication is used"},
+	{"A", "Format_Settings_Mode", "", fieldString, "Channel mode of the format (e.g. Joint stereo)"},
+	{"A", "Format_Settings_ModeExtension", "", fieldString, "Mode extension of the format"},
+	{"I", "Format_Compression", "", fieldString, "Compression applied to the image data"},
+	{"VATI", "MuxingMode", "Muxing mode", fieldString, "How the stream is muxed in the container"},
+	{"VAT", "MuxingMode_MoreInfo", "Muxing mode, more info", fieldString, "Additional muxing information, such as the carrying stream"},
+	{"GVATI", "CodecID", "Codec ID", fieldString, "Codec identifier as stored in the container"},
+	{"GVATI", "CodecID/Info", "Codec ID/Info", fieldString, "Information about the codec identifier"},
+	{"G", "CodecID_Compatible", "", fieldString, "Compatible brands declared by the container"},
+	{"V", "CodecConfigurationBox", "Codec configuration box", fieldString, "Codec configuration box names"},
+
+	// Timing
+	{"GVATOM", "Duration", "Duration", fieldDuration, "Play time of the stream in seconds"},
+	{"A", "Source_Duration", "Source duration", fieldDuration, "Play time of the source stream in seconds"},
+	{"A", "Source_Duration_LastFrame", "Source_Duration_LastFrame", fieldDuration, "Duration of the last frame of the source stream"},
+	{"T", "Duration_Start2End", "", fieldDuration, "Time from the first to the last event"},
+	{"T", "Duration_Start_Command", "", fieldDuration, "Time of the first command"},
+	{"T", "Duration_Start", "Start time", fieldDuration, "Time of the first event"},
+	{"T", "Duration_End", "End time", fieldDuration, "Time of the last event"},
+	{"T", "Duration_End_Command", "", fieldDuration, "Time of the last command"},
+	{"VATM", "Delay", "Delay", fieldDuration, "Delay of the stream relative to the start of the file"},
+	{"V", "Delay_Settings", "", fieldString, "Delay settings (e.g. drop frame)"},
+	{"V", "Delay_DropFrame", "", fieldBoolean, "Whether the delay time code uses drop frame"},
+	{"VA", "Delay_Source", "", fieldString, "Where the delay comes from (container or stream)"},
+	{"V", "Delay_Original", "", fieldDuration, "Delay stored in the stream itself"},
+	{"V", "Delay_Original_DropFrame", "", fieldBoolean, "Whether the original delay uses drop frame"},
+	{"V", "Delay_Original_Source", "", fieldString, "Where the original delay comes from"},
+	{"A", "Source_Delay", "", fieldDuration, "Delay of the source stream"},
+	{"A", "Source_Delay_Source", "", fieldString, "Where the source delay comes from"},
+	{"AT", "Video_Delay", "Delay relative to video", fieldDuration, "Delay relative to the first video stream"},
+	{"O", "Type", "Type", fieldString, "Type of the data carried by the stream (e.g. time code)"},
+	{"VO", "TimeCode_FirstFrame", "Time code of first frame", fieldString, "Time code of the first frame"},
+	{"V", "TimeCode_Source", "Time code source", fieldString, "Where the first frame time code comes from"},
+	{"O", "TimeCode_DropFrame", "", fieldBoolean, "Whether the time code uses drop frame"},
+	{"A", "Interleave_Duration", "", fieldDuration, "Interleave duration"},
+	{"A", "Interleave_VideoFrames", "", fieldFloat, "Interleave duration in video frames"},
+	{"A", "Interleave_Preload", "", fieldDuration, "Preload duration before the first video frame"},
+
+	// Bit rate and size
+	{"G", "OverallBitRate_Mode", "Overall bit rate mode", fieldString, "Bit rate mode of all streams (CBR or VBR)"},
+	{"G", "OverallBitRate", "Overall bit rate", fieldInteger, "Bit rate of all streams in bits per second"},
+	{"G", "OverallBitRate_Maximum", "", fieldInteger, "Maximum bit rate of all streams in bits per second"},
+	{"G", "OverallBitRate_Precision_Min", "", fieldInteger, "Lower bound of the overall bit rate estimate"},
+	{"G", "OverallBitRate_Precision_Max", "", fieldInteger, "Upper bound of the overall bit rate estimate"},
+	{"VAT", "BitRate_Mode", "Bit rate mode", fieldString, "Bit rate mode (CBR or VBR)"},
+	{"VAT", "BitRate", "Bit rate", fieldInteger, "Bit rate in bits per second"},
+	{"VA", "BitRate_Maximum", "Maximum bit rate", fieldInteger, "Maximum bit rate in bits per second"},
+	{"VA", "BitRate_Nominal", "Nominal bit rate", fieldInteger, "Nominal bit rate in bits per second"},
+	{"VA", "BitRate_Encoded", "", fieldInteger, "Bit rate of the encoded stream, before container padding"},
+	{"V", "BufferSize", "", fieldInteger, "Decoder buffer size in bytes"},
+	{"GVATI", "StreamSize", "Stream size", fieldInteger, "Size of the stream in bytes"},
+	{"VA", "StreamSize_Encoded", "", fieldInteger, "Size of the encoded stream in bytes"},
+	{"A", "Source_StreamSize", "Source stream size", fieldInteger, "Size of the source stream in bytes"},
+	{"V", "Bits-(Pixel*Frame)", "Bits/(Pixel*Frame)", fieldFloat, "Bits per pixel per frame"},
+
+	// Video and image
+	{"VI", "Width", "Width", fieldInteger, "Width in pixels"},
+	{"VI", "Height", "Height", fieldInteger, "Height in pixels"},
+	{"V", "Stored_Height", "", fieldInteger, "Height of the coded picture in pixels"},
+	{"V", "Sampled_Width", "", fieldInteger, "Width of the sampled picture in pixels"},
+	{"V", "Sampled_Height", "", fieldInteger, "Height of the sampled picture in pixels"},
+	{"VI", "PixelAspectRatio", "", fieldFloat, "Pixel aspect ratio"},
+	{"VI", "DisplayAspectRatio", "Display aspect ratio", fieldFloat, "Display aspect ratio"},
+	{"V", "DisplayAspectRatio_Original", "", fieldFloat, "Display aspect ratio stored in the stream"},
+	{"V", "Rotation", "", fieldFloat, "Rotation in degrees"},
+	{"GV", "FrameRate_Mode", "Frame rate mode", fieldString, "Frame rate mode (CFR or VFR)"},
+	{"V", "FrameRate_Mode_Original", "", fieldString, "Frame rate mode stored in the stream"},
+	{"GVATOM", "FrameRate", "Frame rate", fieldFloat, "Frames per second"},
+	{"VOM", "FrameRate_Num", "", fieldInteger, "Frame rate numerator"},
+	{"VOM", "FrameRate_Den", "", fieldInteger, "Frame rate denominator"},
+	{"GVATM", "FrameCount", "Frame count", fieldInteger, "Count of frames"},
+	{"V", "Standard", "Standard", fieldString, "Broadcast standard (PAL, NTSC, Component)"},
+	{"VI", "ColorSpace", "Color space", fieldString, "Color space"},
+	{"VI", "ChromaSubsampling", "Chroma subsampling", fieldString, "Chroma subsampling"},
+	{"V", "ChromaSubsampling_Position", "Chroma subsampling position", fieldString, "Position of the chroma samples"},
+	{"VATI", "BitDepth", "Bit depth", fieldInteger, "Bits per sample"},
+	{"V", "ScanType", "Scan type", fieldString, "Scan type (Progressive, Interlaced, MBAFF)"},
+	{"V", "ScanOrder", "Scan order", fieldString, "Field order of interlaced content"},
+	{"VAI", "Compression_Mode", "Compression mode", fieldString, "Compression mode (Lossy or Lossless)"},
+	{"V", "Gop_OpenClosed", "GOP, Open/Closed", fieldString, "Whether GOPs are open or closed"},
+	{"V", "Gop_OpenClosed_FirstFrame", "GOP, Open/Closed of first frame", fieldString, "Whether the first GOP is open or closed"},
+	{"V", "colour_description_present", "", fieldBoolean, "Whether color description is present"},
+	{"V", "colour_description_present_Source", "", fieldString, "Where the color description comes from"},
+	{"V", "colour_range", "Color range", fieldString, "Color range (Limited or Full)"},
+	{"V", "colour_range_Source", "", fieldString, "Where the color range comes from"},
+	{"V", "colour_primaries", "Color primaries", fieldString, "Color primaries"},
+	{"V", "colour_primaries_Source", "", fieldString, "Where the color primaries come from"},
+	{"V", "transfer_characteristics", "Transfer characteristics", fieldString, "Transfer characteristics"},
+	{"V", "transfer_characteristics_Source", "", fieldString, "Where the transfer characteristics come from"},
+	{"V", "matrix_coefficients", "Matrix coefficients", fieldString, "Matrix coefficients"},
+	{"V", "matrix_coefficients_Source", "", fieldString, "Where the matrix coefficients come from"},
+	{"V", "HDR_Format", "HDR format", fieldString, "HDR format (e.g. Dolby Vision, SMPTE ST 2086)"},
+	{"V", "HDR_Format_Version", "", fieldString, "Version of the HDR format"},
+	{"V", "HDR_Format_Profile", "", fieldString, "Profile of the HDR format"},
+	{"V", "HDR_Format_Level", "", fieldString, "Level of the HDR format"},
+	{"V", "HDR_Format_Settings", "", fieldString, "Settings of the HDR format (e.g. BL+EL+RPU)"},
+	{"V", "HDR_Format_Compatibility", "", fieldString, "Compatibility of the HDR format"},
+	{"V", "MasteringDisplay_ColorPrimaries", "Mastering display color primaries", fieldString, "Mastering display color primaries"},
+	{"V", "MasteringDisplay_ColorPrimaries_Source", "", fieldString, "Where the mastering display color primaries come from"},
+	{"V", "MasteringDisplay_Luminance", "Mastering display luminance", fieldString, "Mastering display luminance"},
+	{"V", "MasteringDisplay_Luminance_Source", "", fieldString, "Where the mastering display luminance comes from"},
+	{"V", "MaxCLL", "Maximum Content Light Level", fieldString, "Maximum content light level"},
+	{"V", "MaxCLL_Source", "", fieldString, "Where the maximum content light level comes from"},
+	{"V", "MaxFALL", "Maximum Frame-Average Light Level", fieldString, "Maximum frame-average light level"},
+	{"V", "MaxFALL_Source", "", fieldString, "Where the maximum frame-average light level comes from"},
+	{"V", "intra_dc_precision", "", fieldInteger, "MPEG-2 intra DC precision in bits"},
+
+	// Audio
+	{"A", "Channels", "Channel(s)", fieldInteger, "Count of channels"},
+	{"A", "ChannelPositions", "", fieldString, "Position of the channels"},
+	{"A", "ChannelLayout", "Channel layout", fieldString, "Layout of the channels"},
+	{"A", "SamplesPerFrame", "", fieldInteger, "Count of samples per frame"},
+	{"A", "SamplingRate", "Sampling rate", fieldInteger, "Sampling rate in Hz"},
+	{"A", "SamplingCount", "", fieldInteger, "Count of samples"},
+	{"A", "Source_FrameCount", "", fieldInteger, "Count of frames of the source stream"},
+	{"A", "Alignment", "", fieldString, "How the audio is aligned with the container packets"},
+	{"A", "ServiceKind", "Service kind", fieldString, "Type of audio service (e.g. Complete Main)"},
+	{"A", "AlternateGroup", "Alternate group", fieldInteger, "Alternate group the track belongs to"},
+	{"A", "BedChannelCount", "Bed channel count", fieldInteger, "Count of bed channels of an object-based stream"},
+	{"A", "BedChannelConfiguration", "Bed channel configuration", fieldString, "Bed channel configuration of an object-based stream"},
+	{"A", "NumberOfDynamicObjects", "Number of dynamic objects", fieldInteger, "Count of dynamic objects of an object-based stream"},
+	{"A", "ComplexityIndex", "Complexity index", fieldInteger, "Complexity index of an object-based stream"},
+	{"A", "MD5_Unencoded", "", fieldString, "MD5 of the decoded audio"},
+	{"A", "dialnorm", "Dialog Normalization", fieldInteger, "Dialog normalization in dB"},
+	{"A", "dialnorm_String", "", fieldString, "Dialog normalization, display form"},
+	{"A", "dialnorm_Average", "dialnorm_Average", fieldInteger, "Average dialog normalization in dB"},
+	{"A", "dialnorm_Average_String", "", fieldString, "Average dialog normalization, display form"},
+	{"A", "dialnorm_Minimum", "dialnorm_Minimum", fieldInteger, "Minimum dialog normalization in dB"},
+	{"A", "dialnorm_Minimum_String", "", fieldString, "Minimum dialog normalization, display form"},
+	{"A", "dialnorm_Maximum", "dialnorm_Maximum", fieldInteger, "Maximum dialog normalization in dB"},
+	{"A", "dialnorm_Maximum_String", "", fieldString, "Maximum dialog normalization, display form"},
+	{"A", "dialnorm_Count", "dialnorm_Count", fieldInteger, "Count of frames used for dialog normalization statistics"},
+	{"A", "compr", "compr", fieldFloat, "Compression gain in dB"},
+	{"A", "compr_String", "", fieldString, "Compression gain, display form"},
+	{"A", "compr_Average", "compr_Average", fieldFloat, "Average compression gain in dB"},
+	{"A", "compr_Average_String", "", fieldString, "Average compression gain, display form"},
+	{"A", "compr_Minimum", "compr_Minimum", fieldFloat, "Minimum compression gain in dB"},
+	{"A", "compr_Minimum_String", "", fieldString, "Minimum compression gain, display form"},
+	{"A", "compr_Maximum", "compr_Maximum", fieldFloat, "Maximum compression gain in dB"},
+	{"A", "compr_Maximum_String", "", fieldString, "Maximum compression gain, display form"},
+	{"A", "compr_Count", "compr_Count", fieldInteger, "Count of frames with a compression gain"},
+	{"A", "dynrng", "dynrng", fieldFloat, "Dynamic range gain in dB"},
+	{"A", "dynrng_Average", "", fieldFloat, "Average dynamic range gain in dB"},
+	{"A", "dynrng_Minimum", "", fieldFloat, "Minimum dynamic range gain in dB"},
+	{"A", "dynrng_Maximum", "", fieldFloat, "Maximum dynamic range gain in dB"},
+	{"A", "dynrng_Count", "", fieldInteger, "Count of frames with a dynamic range gain"},
+	{"A", "acmod", "acmod", fieldInteger, "AC-3 audio coding mode"},
+	{"A", "bsid", "bsid", fieldInteger, "AC-3 bit stream identification"},
+	{"A", "lfeon", "lfeon", fieldInteger, "Whether the AC-3 LFE channel is on"},
+	{"A", "dsurmod", "dsurmod", fieldInteger, "AC-3 Dolby Surround mode"},
+	{"A", "cmixlev", "cmixlev", fieldFloat, "AC-3 center mix level"},
+	{"A", "cmixlev_String", "", fieldString, "AC-3 center mix level, display form"},
+	{"A", "surmixlev", "surmixlev", fieldFloat, "AC-3 surround mix level"},
+	{"A", "surmixlev_String", "", fieldString, "AC-3 surround mix level, display form"},
+	{"A", "mixlevel", "mixlevel", fieldInteger, "AC-3 mixing level"},
+	{"A", "roomtyp", "roomtyp", fieldString, "AC-3 room type"},
+
+	// Text
+	{"T", "ElementCount", "", fieldInteger, "Count of subtitle elements"},
+	{"T", "FirstDisplay_Delay_Frames", "Count of frames before first event", fieldInteger, "Count of frames before the first displayed event"},
+	{"T", "FirstDisplay_Type", "Type of the first event", fieldString, "Type of the first displayed event"},
+	{"T", "CaptionServiceName", "Caption service name", fieldString, "Name of the caption service"},
+	{"T", "CaptionServiceDescriptor_IsPresent", "", fieldBoolean, "Whether a caption service descriptor is present"},
+	{"T", "subtitle_stream_id", "", fieldInteger, "DVD subtitle stream identifier"},
+	{"T", "page_id", "", fieldInteger, "DVB subtitle page identifier"},
+	{"T", "region_id", "", fieldInteger, "DVB subtitle region identifier"},
+	{"T", "region_width", "", fieldInteger, "DVB subtitle region width in pixels"},
+	{"T", "region_height", "", fieldInteger, "DVB subtitle region height in pixels"},
+	{"T", "region_depth", "", fieldInteger, "DVB subtitle region depth in bits"},
+	{"T", "region_horizontal_address", "", fieldInteger, "DVB subtitle region horizontal position"},
+	{"T", "region_vertical_address", "", fieldInteger, "DVB subtitle region vertical position"},
+
+	// Menu
+	{"VM", "List_StreamKind", "", fieldString, "Kinds of the streams referenced by this program"},
+	{"VM", "List_StreamPos", "", fieldString, "Positions of the streams referenced by this program"},
+	{"M", "List", "List", fieldString, "Streams referenced by this program"},
+	{"M", "List_Audio", "List (Audio)", fieldString, "DVD audio streams referenced by this menu"},
+	{"M", "List_Subtitles_4_3", "List (Subtitles 4/3)", fieldString, "DVD 4:3 subtitle streams referenced by this menu"},
+	{"M", "List_Subtitles_Wide", "List (Subtitles Wide)", fieldString, "DVD widescreen subtitle streams referenced by this menu"},
+	{"M", "List_Subtitles_Letterbox", "List (Subtitles Letterbox)", fieldString, "DVD letterbox subtitle streams referenced by this menu"},
+	{"M", "List_Subtitles_PanScan", "List (Subtitles Pan&Scan)", fieldString, "DVD pan and scan subtitle streams referenced by this menu"},
+	{"GVM", "ServiceName", "Service name", fieldString, "Name of the broadcast service"},
+	{"VM", "ServiceProvider", "Service provider", fieldString, "Provider of the broadcast service"},
+	{"VM", "ServiceType", "Service type", fieldString, "Type of the broadcast service"},
+	{"M", "format_identifier", "", fieldString, "Registration descriptor format identifier"},
+	{"M", "pointer_field", "", fieldInteger, "MPEG-TS section pointer field"},
+	{"M", "section_length", "", fieldInteger, "MPEG-TS section length"},
+
+	// Tags
+	{"GVATI", "Title", "Title", fieldString, "Title of the file or stream"},
+	{"G", "Movie", "Movie name", fieldString, "Name of the movie"},
+	{"G", "Collection", "Collection", fieldString, "Name of the series the content belongs to"},
+	{"G", "Album", "Album", fieldString, "Name of the album"},
+	{"G", "Album_Performer", "Album/Performer", fieldString, "Performer credited for the whole album"},
+	{"G", "Part", "", fieldString, "Name of the disc or part"},
+	{"G", "Part_Position", "Part/Position", fieldInteger, "Number of the disc or part in the set"},
+	{"G", "Part_Position_Total", "Part/Total", fieldInteger, "Count of discs or parts in the set"},
+	{"G", "Track", "", fieldString, "Name of the track"},
+	{"G", "Track_Position", "Track name/Position", fieldInteger, "Number of the track on the album"},
+	{"G", "Track_Position_Total", "Track name/Total", fieldInteger, "Count of tracks on the album"},
+	{"G", "Season", "", fieldInteger, "Season number of a TV show"},
+	{"G", "Episode", "", fieldInteger, "Episode number of a TV show"},
+	{"G", "EpisodeID", "", fieldString, "Episode identifier of a TV show"},
+	{"G", "Grouping", "Grouping", fieldString, "Group the content belongs to"},
+	{"G", "Performer", "Performer", fieldString, "Main performer or artist"},
+	{"G", "Original_Performer", "", fieldString, "Performer of the original work"},
+	{"G", "Conductor", "", fieldString, "Conductor"},
+	{"G", "RemixedBy", "", fieldString, "Person who remixed the content"},
+	{"G", "Composer", "Composer", fieldString, "Composer or writer"},
+	{"G", "Lyricist", "", fieldString, "Writer of the lyrics"},
+	{"G", "Original_Lyricist", "", fieldString, "Writer of the original lyrics"},
+	{"G", "Publisher", "", fieldString, "Publisher of the content"},
+	{"G", "Label", "", fieldString, "Record label"},
+	{"G", "EncodedBy", "", fieldString, "Person or organisation that encoded the file"},
+	{"G", "SortTitle", "", fieldString, "Title used for sorting"},
+	{"G", "SortPerformer", "", fieldString, "Performer used for sorting"},
+	{"G", "SortAlbum", "", fieldString, "Album used for sorting"},
+	{"G", "Genre", "Genre", fieldString, "Genre of the content"},
+	{"G", "ContentType", "Content type", fieldString, "Kind of content (movie, audiobook, TV show)"},
+	{"G", "LongDescription", "", fieldString, "Long description of the content"},
+	{"G", "HDVideo", "", fieldString, "HD flag of the iTunes tags (No, 720p, 1080p, 2160p)"},
+	{"G", "ISRC", "", fieldString, "International Standard Recording Code"},
+	{"GVAT", "Description", "Description", fieldString, "Description of the content"},
+	{"VATO", "Language", "Language", fieldString, "Language of the stream"},
+	{"VAT", "Default", "Default", fieldBoolean, "Whether the stream is selected by default"},
+	{"VAT", "Forced", "Forced", fieldBoolean, "Whether the stream is forced"},
+	{"G", "LawRating", "Law rating", fieldString, "Legal rating of the content"},
+	{"G", "URL", "", fieldString, "URL stored in the tags"},
+	{"G", "Recorded_Date", "Recorded date", fieldDate, "Date the content was recorded or released"},
+	{"G", "Copyright", "Copyright", fieldString, "Copyright notice"},
+	{"G", "Cover", "Cover", fieldBoolean, "Whether a cover image is attached"},
+	{"G", "Cover_Description", "", fieldString, "Description of the cover image"},
+	{"G", "Cover_Type", "", fieldString, "Type of the cover image (front, back)"},
+	{"G", "Cover_Mime", "Cover MIME", fieldString, "MIME type of the cover image"},
+	{"G", "Cover_Count", "", fieldInteger, "Count of cover images"},
+	{"G", "Lyrics", "Lyrics", fieldString, "Lyrics of the song"},
+	{"G", "Comment", "Comment", fieldString, "Free-form comment"},
+	{"GVAT", "Encoded_Date", "Encoded date", fieldDate, "Date the content was encoded"},
+	{"GVAT", "Tagged_Date", "Tagged date", fieldDate, "Date the tags were written"},
+	{"G", "Encoded_Application", "Writing application", fieldString, "Application used to create the file"},
+	{"GVA", "Encoded_Library", "Writing library", fieldString, "Library used to create the file or stream"},
+	{"GV", "Encoded_Library_Name", "", fieldString, "Name of the encoding library"},
+	{"GV", "Encoded_Library_Version", "", fieldString, "Version of the encoding library"},
+	{"VA", "Encoded_Library_Date", "", fieldDate, "Release date of the encoding library"},
+	{"GV", "Encoded_Library_Settings", "Encoding settings", fieldString, "Parameters used by the encoder"},
+	{"GVATOIM", "extra", "", fieldObject, "Format-specific fields without a standard name"},
+}
+
+var fieldKindInitials = []struct {
+	initial byte
+	kind    StreamKind
+}{
+	{'G', StreamGeneral},
+	{'V', StreamVideo},
+	{'A', StreamAudio},
+	{'T', StreamText},
+	{'O', StreamOther},
+	{'I', StreamImage},
+	{'M', StreamMenu},
+}
+
+// FieldRegistry lists every parameter the analyzer can report, grouped by stream kind.
+func FieldRegistry() []FieldInfo {
+	var out []FieldInfo
+	for _, entry := range fieldKindInitials {
+		for _, def := range fieldDefs {
+			if strings.IndexByte(def.kinds, entry.initial) < 0 {
+				continue
+			}
+			out = append(out, FieldInfo{
+				Kind:        entry.kind,
+				Name:        def.name,
+				Text:        def.text,
+				Type:        def.typ,
+				Description: def.description,
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,169 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func registryIndex() (names, labels map[StreamKind]map[string]bool) {
+	names = map[StreamKind]map[string]bool{}
+	labels = map[StreamKind]map[string]bool{}
+	for _, info := range FieldRegistry() {
+		if names[info.Kind] == nil {
+			names[info.Kind] = map[string]bool{}
+			labels[info.Kind] = map[string]bool{}
+		}
+		names[info.Kind][info.Name] = true
+		if info.Text != "" {
+			labels[info.Kind][info.Text] = true
+		}
+	}
+	return names, labels
+}
+
+func TestFieldRegistryCoversJSONOrder(t *testing.T) {
+	names, _ := registryIndex()
+	for kind, order := range map[StreamKind]map[string]int{
+		StreamGeneral: jsonGeneralFieldOrder,
+		StreamVideo:   jsonVideoFieldOrder,
+		StreamAudio:   jsonAudioFieldOrder,
+		StreamText:    jsonTextFieldOrder,
+		StreamOther:   jsonOtherFieldOrder,
+		StreamMenu:    jsonMenuFieldOrder,
+	} {
+		for key := range order {
+			if !strings.HasPrefix(key, "@") && !names[kind][key] {
+				t.Errorf("%s JSON key %q is not in the field registry", kind, key)
+			}
+		}
+	}
+}
+
+// checkRegistryCoversReport fails on text labels, JSON keys and extra keys of report that are
+// missing from the field registry. dynamic lists extra keys copied verbatim from the file.
+func checkRegistryCoversReport(t *testing.T, path string, report Report, dynamic ...string) {
+	t.Helper()
+	names, labels := registryIndex()
+	check := func(kind StreamKind, fields []Field, jsonFields []jsonKV) {
+		for _, field := range fields {
+			if kind == StreamMenu && field.Name != "" && field.Name[0] >= '0' && field.Name[0] <= '9' {
+				continue // chapter timestamps
+			}
+			if !labels[kind][field.Name] {
+				t.Errorf("%s: %s text label %q is not in the field registry", path, kind, field.Name)
+			}
+		}
+		for _, field := range jsonFields {
+			if strings.HasPrefix(field.Key, "@") {
+				continue
+			}
+			if !names[kind][field.Key] {
+				t.Errorf("%s: %s JSON key %q is not in the field registry", path, kind, field.Key)
+			}
+			if field.Key != "extra" || !field.Raw {
+				continue
+			}
+			parsed, err := parseOrderedJSON(field.Val)
+			if err != nil {
+				continue
+			}
+			for _, kv := range parsed.obj {
+				if kind == StreamMenu && len(kv.key) > 1 && kv.key[0] == '_' && kv.key[1] >= '0' && kv.key[1] <= '9' {
+					continue // chapter timestamps
+				}
+				if !names[kind][kv.key] && !slices.Contains(dynamic, kv.key) {
+					t.Errorf("%s: %s extra key %q is not in the field registry", path, kind, kv.key)
+				}
+			}
+		}
+	}
+	check(StreamGeneral, report.General.Fields, buildJSONGeneralFields(report))
+	for _, stream := range report.Streams {
+		check(stream.Kind, stream.Fields, buildJSONStreamFields(stream, 0, 0, ""))
+	}
+}
+
+func TestFieldRegistryCoversSamples(t *testing.T) {
+	paths, _ := filepath.Glob(filepath.Join("samples", "*"))
+	if len(paths) == 0 {
+		t.Skip("no samples")
+	}
+	for _, path := range paths {
+		report, err := AnalyzeFile(path)
+		if err != nil {
+			continue
+		}
+		checkRegistryCoversReport(t, path, report)
+	}
+}
+
+// TestFieldRegistryCoversFixtures runs the check on the synthetic files of the parser tests,
+// which exercise tags and boxes the samples lack.
+func TestFieldRegistryCoversFixtures(t *testing.T) {
+	fragmented := bytes.Buffer{}
+	writeMP4Box(&fragmented, "ftyp", []byte{'i', 's', 'o', '6', 0, 0, 0, 0})
+	fragmented.Write(buildFragmentedMoov(3200))
+	for i := 1; i <= 3; i++ {
+		writeFragment(&fragmented, uint32(i), 25)
+	}
+	id3 := aiffID3("TALB", "Sessions", "TPE1", "Band", "TPE2", "Various", "TIT2", "Take 3", "TRCK", "3/9",
+		"TPOS", "1/2", "TCOM", "Writer", "TPE3", "Conductor", "TENC", "Encoder", "TCON", "Jazz",
+		"TCOP", "2026 Label", "TEXT", "Lyricist", "TOLY", "Original lyricist", "TOPE", "Original performer",
+		"TPE4", "Remixer", "TRSN", "Radio", "TPUB", "Publisher", "TYER", "2026")
+	fixtures := map[string][]byte{
+		"book.m4b":     buildMP4TaggedFile(),
+		"chapters.mov": buildQuickTimeChapterFile(),
+		"frag.mp4":     fragmented.Bytes(),
+		"take.aiff": buildAIFF("AIFF",
+			aiffCOMM(2, 44100, 16, 44100, ""),
+			aiffChunk{id: "NAME", data: []byte("Take 3")},
+			aiffChunk{id: "ANNO", data: []byte("First take")},
+			aiffSSND(4, 44100*4),
+			aiffChunk{id: "ID3 ", data: id3},
+		),
+	}
+	for name, data := range fixtures {
+		report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), name, defaultAnalyzeOptions())
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		// iTunSMPB is a freeform ilst item, named by the file.
+		checkRegistryCoversReport(t, name, report, "iTunSMPB")
+	}
+}
+
+func TestInfoParameters(t *testing.T) {
+	out := InfoParameters()
+	for _, want := range []string{
+		"General\nID                                       : Identifier of the stream in the container\n",
+		"\nAudio\n",
+		"SamplingRate                             : Sampling rate in Hz\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+
+	var decoded []FieldInfo
+	if err := json.Unmarshal([]byte(InfoParametersJSON()), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded) != len(FieldRegistry()) {
+		t.Fatalf("JSON has %d entries, want %d", len(decoded), len(FieldRegistry()))
+	}
+	seen := map[string]bool{}
+	for _, info := range decoded {
+		if info.Name == "" || info.Type == "" || info.Description == "" {
+			t.Fatalf("incomplete entry: %+v", info)
+		}
+		key := string(info.Kind) + "/" + info.Name
+		if seen[key] {
+			t.Fatalf("duplicate registry entry %s", key)
+		}
+		seen[key] = true
+	}
+}
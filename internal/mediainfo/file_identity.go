@@ -0,0 +1,17 @@
+//go:build unix
+
+package mediainfo
+
+import (
+	"os"
+	"syscall"
+)
+
+// fileIdentity returns the device and inode numbers behind info, when the platform has them.
+func fileIdentity(info os.FileInfo) (uint64, uint64, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok {
+		return 0, 0, false
+	}
+	return uint64(stat.Dev), uint64(stat.Ino), true
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package mediainfo
+
+import "os"
+
+// fileIdentity is unavailable here; cache keys fall back to the absolute path.
+func fileIdentity(os.FileInfo) (uint64, uint64, bool) {
+	return 0, 0, false
+}
@@ -35,6 +35,10 @@ func DetectFormat(header []byte, filename string) string {
 			}
 			return "MPEG-4"
 		}
+		// Fragmented MP4 media segments start with styp, sidx or moof instead of ftyp.
+		if box := string(header[4:8]); box == "styp" || box == "sidx" || box == "moof" {
+			return "MPEG-4"
+		}
 	}
 	if len(header) >= 12 {
 		sig := string(header[0:4])
@@ -47,7 +51,7 @@ func DetectFormat(header []byte, filename string) string {
 				return "Wave"
 			}
 		}
-		if sig == "FORM" && string(header[8:12]) == "AIFF" {
+		if sig == "FORM" && (string(header[8:12]) == "AIFF" || string(header[8:12]) == "AIFC") {
 			return "AIFF"
 		}
 	}
@@ -36,6 +36,71 @@ func formatDuration(seconds float64) string {
 	return fmt.Sprintf("%d min %d s", minutes, secondsOnly)
 }
 
+// durationUnits splits seconds into MediaInfo's h/min/s/ms units, from the most significant
+// non-zero unit down to milliseconds.
+func durationUnits(seconds float64) []string {
+	totalMs := int64(math.Round(seconds * 1000))
+	values := []int64{totalMs / 3600000, (totalMs / 60000) % 60, (totalMs / 1000) % 60, totalMs % 1000}
+	names := []string{"h", "min", "s", "ms"}
+	units := []string{}
+	for i, value := range values {
+		if len(units) == 0 && value == 0 && i < len(values)-1 {
+			continue
+		}
+		units = append(units, fmt.Sprintf("%d %s", value, names[i]))
+	}
+	return units
+}
+
+// formatDurationString1 matches Duration/String1: every unit ("1 h 2 min 3 s 456 ms").
+func formatDurationString1(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return strings.Join(durationUnits(seconds), " ")
+}
+
+// formatDurationString2 matches Duration/String2: the two most significant units ("1 h 2 min").
+func formatDurationString2(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	units := durationUnits(seconds)
+	return strings.Join(units[:min(2, len(units))], " ")
+}
+
+// formatDurationString3 matches Duration/String3: "HH:MM:SS.mmm".
+func formatDurationString3(seconds float64) string {
+	if seconds <= 0 {
+		return ""
+	}
+	totalMs := int64(math.Round(seconds * 1000))
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", totalMs/3600000, (totalMs/60000)%60, (totalMs/1000)%60, totalMs%1000)
+}
+
+// formatDurationString4 matches Duration/String4: a "HH:MM:SS:FF" timecode at fps.
+func formatDurationString4(seconds, fps float64) string {
+	if seconds <= 0 || fps <= 0 {
+		return ""
+	}
+	whole := int64(seconds)
+	frames := int64(math.Round((seconds - float64(whole)) * fps))
+	if frames >= int64(math.Ceil(fps)) {
+		whole++
+		frames = 0
+	}
+	return fmt.Sprintf("%02d:%02d:%02d:%02d", whole/3600, (whole/60)%60, whole%60, frames)
+}
+
+// formatDurationString5 matches Duration/String5: "HH:MM:SS.mmm (HH:MM:SS:FF)".
+func formatDurationString5(seconds, fps float64) string {
+	base := formatDurationString3(seconds)
+	if timecode := formatDurationString4(seconds, fps); timecode != "" {
+		return base + " (" + timecode + ")"
+	}
+	return base
+}
+
 func formatBitrate(bitsPerSecond float64) string {
 	if bitsPerSecond <= 0 {
 		return ""
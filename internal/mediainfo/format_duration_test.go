@@ -27,3 +27,25 @@ func TestFormatBitrate(t *testing.T) {
 		t.Fatalf("formatBitrate=%q", got)
 	}
 }
+
+func TestFormatDurationStrings(t *testing.T) {
+	seconds := 3723.456
+	if got := formatDurationString1(seconds); got != "1 h 2 min 3 s 456 ms" {
+		t.Fatalf("String1=%q", got)
+	}
+	if got := formatDurationString2(seconds); got != "1 h 2 min" {
+		t.Fatalf("String2=%q", got)
+	}
+	if got := formatDurationString2(0.25); got != "250 ms" {
+		t.Fatalf("String2(0.25)=%q", got)
+	}
+	if got := formatDurationString3(seconds); got != "01:02:03.456" {
+		t.Fatalf("String3=%q", got)
+	}
+	if got := formatDurationString4(seconds, 25); got != "01:02:03:11" {
+		t.Fatalf("String4=%q", got)
+	}
+	if got := formatDurationString5(seconds, 0); got != "01:02:03.456" {
+		t.Fatalf("String5 without fps=%q", got)
+	}
+}
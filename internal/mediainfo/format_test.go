@@ -14,6 +14,8 @@ func TestDetectFormat(t *testing.T) {
 		{name: "quicktime", header: []byte{0x00, 0x00, 0x00, 0x18, 'f', 't', 'y', 'p', 'q', 't', ' ', ' '}, filename: "movie.mov", want: "QuickTime"},
 		{name: "avi", header: []byte{'R', 'I', 'F', 'F', 0, 0, 0, 0, 'A', 'V', 'I', ' '}, filename: "movie.avi", want: "AVI"},
 		{name: "wave", header: []byte{'R', 'I', 'F', 'F', 0, 0, 0, 0, 'W', 'A', 'V', 'E'}, filename: "sound.wav", want: "Wave"},
+		{name: "aiff", header: []byte{'F', 'O', 'R', 'M', 0, 0, 0, 0, 'A', 'I', 'F', 'F'}, filename: "sound.aif", want: "AIFF"},
+		{name: "aifc", header: []byte{'F', 'O', 'R', 'M', 0, 0, 0, 0, 'A', 'I', 'F', 'C'}, filename: "sound.aifc", want: "AIFF"},
 		{name: "flac", header: []byte{'f', 'L', 'a', 'C'}, filename: "sound.flac", want: "FLAC"},
 		{name: "ogg", header: []byte{'O', 'g', 'g', 'S'}, filename: "sound.ogg", want: "Ogg"},
 		{name: "mp3", header: []byte{'I', 'D', '3'}, filename: "sound.mp3", want: "MPEG Audio"},
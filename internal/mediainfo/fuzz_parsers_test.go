@@ -121,6 +121,17 @@ func FuzzParseFLACContainers(f *testing.F) {
 	})
 }
 
+func FuzzParseAIFFContainers(f *testing.F) {
+	f.Add([]byte{'F', 'O', 'R', 'M', 0x00, 0x00, 0x00, 0x04, 'A', 'I', 'F', 'F'})
+	f.Add([]byte{'F', 'O', 'R', 'M', 0x00, 0x00, 0x00, 0x1E, 'A', 'I', 'F', 'C', 'C', 'O', 'M', 'M', 0x00, 0x00, 0x00, 0x12})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		data = fuzzLimit(data)
+		r := bytes.NewReader(data)
+		_, _, _, _, _ = ParseAIFF(r, int64(len(data)))
+	})
+}
+
 func FuzzParseMP3Containers(f *testing.F) {
 	// Minimal ID3 header.
 	f.Add([]byte{'I', 'D', '3', 0x04, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00})
@@ -0,0 +1,329 @@
+package mediainfo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Defaults for HTTPReaderOptions zero values.
+const (
+	DefaultHTTPBlockSize       = 256 << 10
+	DefaultHTTPReadAhead       = 4
+	DefaultHTTPMaxCachedBlocks = 64
+)
+
+// HTTPReaderOptions tunes an HTTPReader.
+type HTTPReaderOptions struct {
+	// Client sends the requests; nil uses http.DefaultClient.
+	Client *http.Client
+	// Header is added to every request (authentication, cookies, ...).
+	Header http.Header
+	// BlockSize is the unit of fetching and caching.
+	BlockSize int64
+	// ReadAhead is how many extra blocks each miss fetches past the requested range.
+	ReadAhead int
+	// MaxCachedBlocks bounds memory use; the least recently used block is evicted first.
+	MaxCachedBlocks int
+}
+
+// HTTPReader is an io.ReaderAt over a remote file, read with HTTP Range requests. Fetched blocks
+// are cached, and every miss reads ahead so sequential scans need few round trips. The resource
+// is pinned to the ETag/Last-Modified seen when it was opened; a change makes reads fail.
+type HTTPReader struct {
+	ctx       context.Context
+	url       string
+	opts      HTTPReaderOptions
+	size      int64
+	validator string
+
+	mu     sync.Mutex
+	blocks map[int64]*httpBlock
+	clock  uint64
+
+	fetched  atomic.Int64
+	requests atomic.Int64
+}
+
+type httpBlock struct {
+	data []byte
+	used uint64
+}
+
+// errRangeUnsupported is returned when the server ignores Range headers for a large resource.
+var errRangeUnsupported = errors.New("server does not support range requests")
+
+// IsURL reports whether path is an http:// or https:// URL.
+func IsURL(path string) bool {
+	lower := strings.ToLower(path)
+	return strings.HasPrefix(lower, "http://") || strings.HasPrefix(lower, "https://")
+}
+
+// OpenHTTPReader probes rawURL with a first Range request, which also learns the size and
+// caches the first block. Requests made later by ReadAt use ctx as well.
+func OpenHTTPReader(ctx context.Context, rawURL string, opts HTTPReaderOptions) (*HTTPReader, error) {
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.BlockSize <= 0 {
+		opts.BlockSize = DefaultHTTPBlockSize
+	}
+	if opts.ReadAhead < 0 {
+		opts.ReadAhead = 0
+	} else if opts.ReadAhead == 0 {
+		opts.ReadAhead = DefaultHTTPReadAhead
+	}
+	if opts.MaxCachedBlocks <= opts.ReadAhead {
+		opts.MaxCachedBlocks = max(DefaultHTTPMaxCachedBlocks, opts.ReadAhead+1)
+	}
+	r := &HTTPReader{ctx: ctx, url: rawURL, opts: opts, blocks: map[int64]*httpBlock{}}
+
+	resp, err := r.get(0, opts.BlockSize-1)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		_, _, total, err := parseContentRange(resp.Header.Get("Content-Range"))
+		if err != nil {
+			return nil, err
+		}
+		r.size = total
+	case http.StatusRequestedRangeNotSatisfiable:
+		// Only an empty resource cannot satisfy bytes=0-...
+		r.size = 0
+		r.validator = resourceValidator(resp.Header)
+		return r, nil
+	case http.StatusOK:
+		// Range was ignored; that is only workable when the whole resource fits in one block.
+		if resp.ContentLength > opts.BlockSize {
+			return nil, fmt.Errorf("%s: %w", redactURL(rawURL), errRangeUnsupported)
+		}
+		r.size = -1
+	default:
+		return nil, fmt.Errorf("%s: %s", redactURL(rawURL), resp.Status)
+	}
+	r.validator = resourceValidator(resp.Header)
+	data, err := io.ReadAll(io.LimitReader(resp.Body, opts.BlockSize+1))
+	if err != nil {
+		return nil, err
+	}
+	r.fetched.Add(int64(len(data)))
+	if r.size < 0 {
+		if int64(len(data)) > opts.BlockSize {
+			return nil, fmt.Errorf("%s: %w", redactURL(rawURL), errRangeUnsupported)
+		}
+		r.size = int64(len(data))
+	}
+	if int64(len(data)) != min(opts.BlockSize, r.size) {
+		return nil, fmt.Errorf("%s: short response", redactURL(rawURL))
+	}
+	r.blocks[0] = &httpBlock{data: data}
+	return r, nil
+}
+
+// Size returns the resource length.
+func (r *HTTPReader) Size() int64 {
+	return r.size
+}
+
+// BytesFetched returns how many body bytes have been downloaded so far.
+func (r *HTTPReader) BytesFetched() int64 {
+	return r.fetched.Load()
+}
+
+// Requests returns how many HTTP requests have been made so far.
+func (r *HTTPReader) Requests() int64 {
+	return r.requests.Load()
+}
+
+// ReadAt implements io.ReaderAt.
+func (r *HTTPReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	if off >= r.size {
+		return 0, io.EOF
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	end := min(off+int64(len(p)), r.size)
+	bs := r.opts.BlockSize
+	first, last := off/bs, (end-1)/bs
+	for index := first; index <= last; index++ {
+		if _, ok := r.blocks[index]; ok {
+			continue
+		}
+		if err := r.fetch(index, first, last); err != nil {
+			return 0, err
+		}
+	}
+	n := 0
+	for index := first; index <= last; index++ {
+		block := r.blocks[index]
+		r.clock++
+		block.used = r.clock
+		start := max(off+int64(n)-index*bs, 0)
+		n += copy(p[n:], block.data[start:])
+	}
+	// A read wider than the cache may have kept extra blocks; trim now that they are copied.
+	r.evict(0, -1)
+	n = int(min(int64(n), end-off))
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// fetch downloads the run of missing blocks starting at index, through lastNeeded and
+// ReadAhead blocks beyond it, stopping at the first block already cached. Blocks from
+// firstNeeded to lastNeeded are never evicted, since the current read still needs them.
+func (r *HTTPReader) fetch(index, firstNeeded, lastNeeded int64) error {
+	bs := r.opts.BlockSize
+	lastBlock := (r.size - 1) / bs
+	stop := min(lastNeeded+int64(r.opts.ReadAhead), lastBlock)
+	end := index
+	for end < stop {
+		if _, ok := r.blocks[end+1]; ok {
+			break
+		}
+		end++
+	}
+	from, to := index*bs, min((end+1)*bs, r.size)-1
+
+	resp, err := r.get(from, to)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusPartialContent {
+		if resp.StatusCode == http.StatusOK {
+			// If-Range failed: the resource changed since it was opened.
+			return fmt.Errorf("%s: resource changed while reading", redactURL(r.url))
+		}
+		return fmt.Errorf("%s: %s", redactURL(r.url), resp.Status)
+	}
+	if start, _, _, err := parseContentRange(resp.Header.Get("Content-Range")); err != nil || start != from {
+		return fmt.Errorf("%s: unexpected Content-Range %q", redactURL(r.url), resp.Header.Get("Content-Range"))
+	}
+	data := make([]byte, to-from+1)
+	n, err := io.ReadFull(resp.Body, data)
+	r.fetched.Add(int64(n))
+	if err != nil {
+		return fmt.Errorf("%s: %w", redactURL(r.url), err)
+	}
+	r.clock++
+	for i := index; i <= end; i++ {
+		lo := (i - index) * bs
+		hi := min(lo+bs, int64(len(data)))
+		r.blocks[i] = &httpBlock{data: data[lo:hi:hi], used: r.clock}
+	}
+	r.evict(firstNeeded, lastNeeded)
+	return nil
+}
+
+// evict drops least recently used blocks over MaxCachedBlocks, keeping first..last.
+func (r *HTTPReader) evict(first, last int64) {
+	for len(r.blocks) > r.opts.MaxCachedBlocks {
+		var oldest int64 = -1
+		for index, block := range r.blocks {
+			if index >= first && index <= last {
+				continue
+			}
+			if oldest < 0 || block.used < r.blocks[oldest].used {
+				oldest = index
+			}
+		}
+		if oldest < 0 {
+			return
+		}
+		delete(r.blocks, oldest)
+	}
+}
+
+func (r *HTTPReader) get(from, to int64) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodGet, r.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range r.opts.Header {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", from, to))
+	// Servers compress otherwise, and byte ranges of a compressed body are meaningless here.
+	req.Header.Set("Accept-Encoding", "identity")
+	if r.validator != "" {
+		req.Header.Set("If-Range", r.validator)
+	}
+	r.requests.Add(1)
+	resp, err := r.opts.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// resourceValidator picks the If-Range value: a strong ETag, else Last-Modified.
+func resourceValidator(header http.Header) string {
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") {
+		return etag
+	}
+	return header.Get("Last-Modified")
+}
+
+// parseContentRange parses "bytes start-end/total".
+func parseContentRange(value string) (int64, int64, int64, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(value), "bytes ")
+	if ok {
+		span, totalText, found := strings.Cut(spec, "/")
+		startText, endText, hasDash := strings.Cut(span, "-")
+		start, err1 := strconv.ParseInt(startText, 10, 64)
+		end, err2 := strconv.ParseInt(endText, 10, 64)
+		total, err3 := strconv.ParseInt(totalText, 10, 64)
+		if found && hasDash && err1 == nil && err2 == nil && err3 == nil && start <= end && end < total {
+			return start, end, total, nil
+		}
+	}
+	return 0, 0, 0, fmt.Errorf("invalid Content-Range %q", value)
+}
+
+// redactURL drops credentials and the query, which often carries access tokens.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+// AnalyzeURL analyzes a remote file through an HTTPReader. The report is named after the URL
+// without credentials or query, and its General extra records the bytes actually fetched.
+func AnalyzeURL(ctx context.Context, rawURL string, opts AnalyzeOptions, httpOpts HTTPReaderOptions) (Report, error) {
+	reader, err := OpenHTTPReader(ctx, rawURL, httpOpts)
+	if err != nil {
+		return Report{}, err
+	}
+	report, err := AnalyzeReaderContext(ctx, reader, reader.Size(), redactURL(rawURL), opts)
+	if err != nil && !report.Incomplete {
+		return report, err
+	}
+	if report.General.JSONRaw == nil {
+		report.General.JSONRaw = map[string]string{}
+	}
+	report.General.JSONRaw["extra"] = appendJSONExtra(report.General.JSONRaw["extra"], "BytesFetched", strconv.FormatInt(reader.BytesFetched(), 10))
+	return report, err
+}
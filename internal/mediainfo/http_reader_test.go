@@ -0,0 +1,156 @@
+package mediainfo
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rangeServer serves files from samples/ with Range support, counting requests.
+func rangeServer(t *testing.T, etag *string) (*httptest.Server, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data, err := os.ReadFile(filepath.Join("samples", filepath.Base(r.URL.Path)))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		if etag != nil {
+			w.Header().Set("ETag", *etag)
+		}
+		http.ServeContent(w, r, r.URL.Path, time.Time{}, bytes.NewReader(data))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestHTTPReaderReadAt(t *testing.T) {
+	server, _ := rangeServer(t, nil)
+	want, err := os.ReadFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	reader, err := OpenHTTPReader(context.Background(), server.URL+"/sample.mkv", HTTPReaderOptions{BlockSize: 1000, ReadAhead: 1, MaxCachedBlocks: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reader.Size() != int64(len(want)) {
+		t.Fatalf("Size=%d, want %d", reader.Size(), len(want))
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		off := rng.Int64N(int64(len(want)))
+		buf := make([]byte, rng.IntN(5000)+1)
+		n, err := reader.ReadAt(buf, off)
+		wantN := min(len(buf), len(want)-int(off))
+		if n != wantN || !bytes.Equal(buf[:n], want[off:off+int64(n)]) {
+			t.Fatalf("ReadAt(%d, %d): n=%d, want %d", len(buf), off, n, wantN)
+		}
+		if (n < len(buf)) != (err == io.EOF) {
+			t.Fatalf("ReadAt(%d, %d): err=%v with n=%d", len(buf), off, err, n)
+		}
+		if len(reader.blocks) > 3 {
+			t.Fatalf("%d blocks cached, limit is 3", len(reader.blocks))
+		}
+	}
+}
+
+func TestHTTPReaderReadAhead(t *testing.T) {
+	server, requests := rangeServer(t, nil)
+	reader, err := OpenHTTPReader(context.Background(), server.URL+"/sample.wav", HTTPReaderOptions{BlockSize: 4096, ReadAhead: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 512)
+	for off := int64(0); off < 64*1024; off += int64(len(buf)) {
+		if _, err := reader.ReadAt(buf, off); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 64 KiB sequentially: the probe block, then runs of 1+7 blocks.
+	if *requests != 3 || reader.Requests() != 3 || reader.BytesFetched() != 4096+2*8*4096 {
+		t.Fatalf("requests=%d fetched=%d", *requests, reader.BytesFetched())
+	}
+}
+
+func TestAnalyzeURL(t *testing.T) {
+	server, _ := rangeServer(t, nil)
+	for _, name := range []string{"sample.mkv", "sample.mp4", "sample.wav"} {
+		local, err := AnalyzeFile(filepath.Join("samples", name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		remote, err := AnalyzeURL(context.Background(), server.URL+"/"+name+"?token=secret", AnalyzeOptions{}, HTTPReaderOptions{BlockSize: 16 << 10})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if remote.Ref != server.URL+"/"+name {
+			t.Fatalf("Ref=%q, want the URL without its query", remote.Ref)
+		}
+		if got, want := RenderText([]Report{remote}), RenderText([]Report{local}); stripTextRef(got) != stripTextRef(want) {
+			t.Fatalf("%s: remote text differs:\n%s\nwant:\n%s", name, got, want)
+		}
+		json := RenderJSON([]Report{remote})
+		if !strings.Contains(json, `"BytesFetched":"`) || strings.Contains(json, "secret") {
+			t.Fatalf("%s: JSON extra: %s", name, json)
+		}
+		checkRegistryCoversReport(t, remote.Ref, remote)
+	}
+
+	remote, err := AnalyzeURL(context.Background(), server.URL+"/sample.wav", AnalyzeOptions{}, HTTPReaderOptions{BlockSize: 4096, ReadAhead: -1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fetched := jsonFieldValue(buildJSONGeneralFields(remote), "extra"); !strings.Contains(fetched, `"BytesFetched":"`) || strings.Contains(fetched, `"384078"`) {
+		t.Fatalf("WAV header analysis fetched the whole file: %s", fetched)
+	}
+}
+
+func stripTextRef(text string) string {
+	var lines []string
+	for line := range strings.SplitSeq(text, "\n") {
+		if !strings.HasPrefix(line, "Complete name") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestHTTPReaderErrors(t *testing.T) {
+	etag := `"v1"`
+	server, _ := rangeServer(t, &etag)
+	reader, err := OpenHTTPReader(context.Background(), server.URL+"/sample.mkv", HTTPReaderOptions{BlockSize: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+	etag = `"v2"`
+	if _, err := reader.ReadAt(make([]byte, 10), 100_000); err == nil || !strings.Contains(err.Error(), "changed") {
+		t.Fatalf("err=%v, want a changed-resource error", err)
+	}
+
+	if _, err := OpenHTTPReader(context.Background(), server.URL+"/missing.mkv", HTTPReaderOptions{}); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("err=%v, want 404", err)
+	}
+
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := os.ReadFile("samples/sample.flac")
+		w.Write(data)
+	}))
+	defer plain.Close()
+	if _, err := OpenHTTPReader(context.Background(), plain.URL+"/sample.flac", HTTPReaderOptions{BlockSize: 1024}); err == nil || !strings.Contains(err.Error(), "range requests") {
+		t.Fatalf("err=%v, want a range-support error", err)
+	}
+	if reader, err := OpenHTTPReader(context.Background(), plain.URL+"/sample.flac", HTTPReaderOptions{BlockSize: 1 << 20}); err != nil || reader.Size() != 61033 {
+		t.Fatalf("small resource without ranges: err=%v", err)
+	}
+}
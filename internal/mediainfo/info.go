@@ -1,5 +1,37 @@
 package mediainfo
 
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// InfoParameters lists the registry like MediaInfo's --Info-Parameters: a section per stream
+// kind with one "Name : Description" line per parameter.
 func InfoParameters() string {
-	return "General\nComplete name\nFormat\nFile size\nDuration\nOverall bit rate mode\nOverall bit rate\n\nVideo\nID\nFormat\nWidth\nHeight\nFrame rate\nBit rate mode\nBit rate\nDuration\n\nAudio\nID\nFormat\nChannel(s)\nChannel layout\nSampling rate\nBit rate mode\nBit rate\nDuration\n\nText\nID\nFormat\nDuration\n\nMenu\n"
+	var buf bytes.Buffer
+	var current StreamKind
+	for _, info := range FieldRegistry() {
+		if info.Kind != current {
+			if current != "" {
+				buf.WriteString("\n")
+			}
+			current = info.Kind
+			buf.WriteString(string(current))
+			buf.WriteString("\n")
+		}
+		buf.WriteString(padRight(info.Name, 41))
+		buf.WriteString(": ")
+		buf.WriteString(info.Description)
+		buf.WriteString("\n")
+	}
+	return buf.String()
+}
+
+// InfoParametersJSON renders the registry as a JSON array of FieldInfo objects.
+func InfoParametersJSON() string {
+	data, err := json.MarshalIndent(FieldRegistry(), "", "  ")
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
 }
@@ -9,6 +9,7 @@ var jsonGeneralFieldOrder = map[string]int{
 	"VideoCount":               2,
 	"AudioCount":               3,
 	"TextCount":                4,
+	"OtherCount":               5,
 	"ImageCount":               5,
 	"MenuCount":                6,
 	"FileExtension":            7,
@@ -30,16 +31,40 @@ var jsonGeneralFieldOrder = map[string]int{
 	"DataSize":                 22,
 	"FooterSize":               23,
 	"IsStreamable":             24,
-	"File_Created_Date":        25,
-	"File_Created_Date_Local":  26,
-	"File_Modified_Date":       27,
-	"File_Modified_Date_Local": 28,
-	"Encoded_Application":      29,
-	"Encoded_Library":          30,
-	"Encoded_Library_Name":     31,
-	"Encoded_Library_Version":  32,
-	"Encoded_Library_Settings": 33,
-	"extra":                    34,
+	"Title":                    25,
+	"Movie":                    26,
+	"Collection":               27,
+	"Album":                    28,
+	"Album_Performer":          29,
+	"Part_Position":            30,
+	"Part_Position_Total":      31,
+	"Track_Position":           32,
+	"Track_Position_Total":     33,
+	"Grouping":                 34,
+	"Performer":                35,
+	"Composer":                 36,
+	"ServiceName":              37,
+	"Genre":                    38,
+	"ContentType":              39,
+	"LawRating":                40,
+	"Recorded_Date":            41,
+	"Encoded_Date":             42,
+	"Tagged_Date":              43,
+	"File_Created_Date":        44,
+	"File_Created_Date_Local":  45,
+	"File_Modified_Date":       46,
+	"File_Modified_Date_Local": 47,
+	"Encoded_Application":      48,
+	"Encoded_Library":          49,
+	"Encoded_Library_Name":     50,
+	"Encoded_Library_Version":  51,
+	"Encoded_Library_Settings": 52,
+	"Copyright":                53,
+	"Cover":                    54,
+	"Cover_Mime":               55,
+	"Lyrics":                   56,
+	"Comment":                  57,
+	"extra":                    58,
 }
 
 var jsonVideoFieldOrder = map[string]int{
@@ -206,6 +231,23 @@ var jsonTextFieldOrder = map[string]int{
 	"extra":                     30,
 }
 
+var jsonOtherFieldOrder = map[string]int{
+	"@type":               0,
+	"@typeorder":          1,
+	"StreamOrder":         2,
+	"ID":                  3,
+	"Type":                4,
+	"Format":              5,
+	"Duration":            6,
+	"FrameRate":           7,
+	"FrameRate_Num":       8,
+	"FrameRate_Den":       9,
+	"TimeCode_FirstFrame": 10,
+	"TimeCode_DropFrame":  11,
+	"Language":            12,
+	"extra":               13,
+}
+
 var jsonMenuFieldOrder = map[string]int{
 	"@type":            0,
 	"@typeorder":       1,
@@ -239,6 +281,8 @@ func sortJSONFields(kind StreamKind, fields []jsonKV) []jsonKV {
 		order = jsonVideoFieldOrder
 	case StreamText:
 		order = jsonTextFieldOrder
+	case StreamOther:
+		order = jsonOtherFieldOrder
 	case StreamMenu:
 		order = jsonMenuFieldOrder
 	case StreamImage:
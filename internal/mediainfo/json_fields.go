@@ -33,6 +33,9 @@ func buildJSONMedia(report Report) jsonMediaOut {
 
 func buildJSONGeneralFields(report Report) []jsonKV {
 	fields := []jsonKV{{Key: "@type", Val: string(StreamGeneral)}}
+	if report.General.parsedJSON != nil {
+		return append(fields, report.General.parsedJSON...)
+	}
 	counts := countStreams(report.Streams)
 	for _, key := range []struct {
 		Name  string
@@ -41,6 +44,7 @@ func buildJSONGeneralFields(report Report) []jsonKV {
 		{Name: "VideoCount", Count: counts[StreamVideo]},
 		{Name: "AudioCount", Count: counts[StreamAudio]},
 		{Name: "TextCount", Count: counts[StreamText]},
+		{Name: "OtherCount", Count: counts[StreamOther]},
 		{Name: "ImageCount", Count: counts[StreamImage]},
 		{Name: "MenuCount", Count: counts[StreamMenu]},
 	} {
@@ -53,18 +57,25 @@ func buildJSONGeneralFields(report Report) []jsonKV {
 		if ext != "" {
 			fields = append(fields, jsonKV{Key: "FileExtension", Val: ext})
 		}
-		if size := fileSizeBytes(report.Ref); size > 0 {
-			fields = append(fields, jsonKV{Key: "FileSize", Val: strconv.FormatInt(size, 10)})
-		}
-		if createdUTC, createdLocal, modifiedUTC, modifiedLocal, ok := fileTimes(report.Ref); ok {
-			if createdUTC != "" {
-				fields = append(fields, jsonKV{Key: "File_Created_Date", Val: createdUTC})
-			}
-			if createdLocal != "" {
-				fields = append(fields, jsonKV{Key: "File_Created_Date_Local", Val: createdLocal})
+		if report.virtual {
+			// No file on disk: use the analyzed size and skip File_*_Date fields.
+			if report.fileSize > 0 {
+				fields = append(fields, jsonKV{Key: "FileSize", Val: strconv.FormatInt(report.fileSize, 10)})
+			}
+		} else {
+			if size := fileSizeBytes(report.Ref); size > 0 {
+				fields = append(fields, jsonKV{Key: "FileSize", Val: strconv.FormatInt(size, 10)})
+			}
+			if createdUTC, createdLocal, modifiedUTC, modifiedLocal, ok := fileTimes(report.Ref); ok {
+				if createdUTC != "" {
+					fields = append(fields, jsonKV{Key: "File_Created_Date", Val: createdUTC})
+				}
+				if createdLocal != "" {
+					fields = append(fields, jsonKV{Key: "File_Created_Date_Local", Val: createdLocal})
+				}
+				fields = append(fields, jsonKV{Key: "File_Modified_Date", Val: modifiedUTC})
+				fields = append(fields, jsonKV{Key: "File_Modified_Date_Local", Val: modifiedLocal})
 			}
-			fields = append(fields, jsonKV{Key: "File_Modified_Date", Val: modifiedUTC})
-			fields = append(fields, jsonKV{Key: "File_Modified_Date_Local", Val: modifiedLocal})
 		}
 	}
 	fields = append(fields, mapStreamFieldsToJSON(StreamGeneral, report.General.Fields)...)
@@ -77,6 +88,9 @@ func buildJSONStreamFields(stream Stream, order int, typeOrder int, containerFor
 	if typeOrder > 0 {
 		fields = append(fields, jsonKV{Key: "@typeorder", Val: strconv.Itoa(typeOrder)})
 	}
+	if stream.parsedJSON != nil {
+		return append(fields, stream.parsedJSON...)
+	}
 	if !stream.JSONSkipStreamOrder {
 		fields = append(fields, jsonKV{Key: "StreamOrder", Val: strconv.Itoa(order)})
 	}
@@ -140,6 +154,8 @@ func mapStreamFieldsToJSON(kind StreamKind, fields []Field) []jsonKV {
 			out = append(out, jsonKV{Key: "ID", Val: field.Value})
 		case "Menu ID":
 			out = append(out, jsonKV{Key: "MenuID", Val: field.Value})
+		case "Type":
+			out = append(out, jsonKV{Key: "Type", Val: field.Value})
 		case "Unique ID":
 			value := strings.TrimSpace(field.Value)
 			if idx := strings.IndexAny(value, " ("); idx >= 0 {
@@ -297,6 +313,42 @@ func mapStreamFieldsToJSON(kind StreamKind, fields []Field) []jsonKV {
 			out = append(out, jsonKV{Key: "Movie", Val: field.Value})
 		case "Law rating":
 			out = append(out, jsonKV{Key: "LawRating", Val: field.Value})
+		case "Collection":
+			out = append(out, jsonKV{Key: "Collection", Val: field.Value})
+		case "Album":
+			out = append(out, jsonKV{Key: "Album", Val: field.Value})
+		case "Album/Performer":
+			out = append(out, jsonKV{Key: "Album_Performer", Val: field.Value})
+		case "Part/Position":
+			out = append(out, jsonKV{Key: "Part_Position", Val: field.Value})
+		case "Part/Total":
+			out = append(out, jsonKV{Key: "Part_Position_Total", Val: field.Value})
+		case "Track name/Position":
+			out = append(out, jsonKV{Key: "Track_Position", Val: field.Value})
+		case "Track name/Total":
+			out = append(out, jsonKV{Key: "Track_Position_Total", Val: field.Value})
+		case "Grouping":
+			out = append(out, jsonKV{Key: "Grouping", Val: field.Value})
+		case "Performer":
+			out = append(out, jsonKV{Key: "Performer", Val: field.Value})
+		case "Composer":
+			out = append(out, jsonKV{Key: "Composer", Val: field.Value})
+		case "Genre":
+			out = append(out, jsonKV{Key: "Genre", Val: field.Value})
+		case "Content type":
+			out = append(out, jsonKV{Key: "ContentType", Val: field.Value})
+		case "Recorded date":
+			out = append(out, jsonKV{Key: "Recorded_Date", Val: field.Value})
+		case "Copyright":
+			out = append(out, jsonKV{Key: "Copyright", Val: field.Value})
+		case "Cover":
+			out = append(out, jsonKV{Key: "Cover", Val: field.Value})
+		case "Cover MIME":
+			out = append(out, jsonKV{Key: "Cover_Mime", Val: field.Value})
+		case "Lyrics":
+			out = append(out, jsonKV{Key: "Lyrics", Val: field.Value})
+		case "Comment":
+			out = append(out, jsonKV{Key: "Comment", Val: field.Value})
 		case "Channel(s)":
 			out = append(out, jsonKV{Key: "Channels", Val: extractLeadingNumber(field.Value)})
 		case "Channel layout":
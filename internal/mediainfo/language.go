@@ -64,6 +64,33 @@ var languageMap3To2 = map[string]string{
 	"tur": "tr",
 }
 
+// languageMap2To3 gives the ISO 639-2/T (terminology) code of each two-letter code.
+var languageMap2To3 = map[string]string{
+	"en": "eng",
+	"fr": "fra",
+	"es": "spa",
+	"de": "deu",
+	"it": "ita",
+	"pt": "por",
+	"zh": "zho",
+	"ar": "ara",
+	"cs": "ces",
+	"da": "dan",
+	"fi": "fin",
+	"el": "ell",
+	"hr": "hrv",
+	"hu": "hun",
+	"ja": "jpn",
+	"no": "nor",
+	"fa": "fas",
+	"pl": "pol",
+	"ro": "ron",
+	"ru": "rus",
+	"sv": "swe",
+	"th": "tha",
+	"tr": "tur",
+}
+
 func normalizeLanguageCode(code string) string {
 	code = strings.TrimSpace(code)
 	if code == "" {
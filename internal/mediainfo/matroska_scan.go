@@ -295,6 +295,9 @@ func scanMatroskaClusters(r io.ReaderAt, offset int64, size int64, timecodeScale
 	}
 
 	for er.pos < size {
+		if readerCanceled(r) {
+			break
+		}
 		id, elemSize, err := readMatroskaElementHeader(er, size, 0)
 		if err != nil {
 			break
@@ -33,6 +33,11 @@ type MP4Track struct {
 	Timescale        uint32
 	Width            uint64
 	Height           uint64
+
+	// refs holds the tref track references by type (chap, tmcd); stbl is kept for text and
+	// time code tracks whose samples are read back after the moov pass.
+	refs map[string][]uint32
+	stbl []byte
 }
 
 type MP4Info struct {
@@ -43,6 +48,19 @@ type MP4Info struct {
 	MovieCreation  uint64
 	MovieModified  uint64
 	Chapters       []mp4Chapter
+	// Tags holds iTunes-style ilst metadata as General JSON fields; TagExtra the ones that
+	// belong under General extra.
+	Tags     map[string]string
+	TagExtra []jsonKV
+	// Fragmented is set when moov carries an mvex box: samples live in moof fragments.
+	Fragmented       bool
+	FragmentCount    int
+	FragmentDuration float64 // duration of the first fragment, in seconds
+
+	mehdDuration float64
+	trexDefaults map[uint32]mp4TrackDefaults
+	// initSegment is the path of the initialization segment a bare media segment was paired with.
+	initSegment string
 }
 
 type mp4Chapter struct {
@@ -80,6 +98,10 @@ func ParseMP4(r io.ReaderAt, size int64) (MP4Info, bool) {
 				if len(info.General) > 0 {
 					moovInfo.General = append(info.General, moovInfo.General...)
 				}
+				if moovInfo.Fragmented {
+					moovInfo.applyFragments(scanMP4Fragments(r, size, moovInfo.trexDefaults, moovInfo.trackTimescales()))
+				}
+				moovInfo.applyQuickTimeTracks(r, size)
 				return moovInfo, true
 			}
 		}
@@ -145,6 +167,12 @@ func parseMoov(buf []byte) (MP4Info, bool) {
 			if chapters := parseMP4Chpl(payload); len(chapters) > 0 {
 				info.Chapters = append(info.Chapters, chapters...)
 			}
+			if meta, ok := findMP4Box(payload, "meta"); ok {
+				info.addItunesTags(meta)
+			}
+		}
+		if boxType == "meta" {
+			info.addItunesTags(sliceBox(buf, dataOffset, boxSize-headerSize))
 		}
 		if boxType == "trak" {
 			payload := sliceBox(buf, dataOffset, boxSize-headerSize)
@@ -152,6 +180,15 @@ func parseMoov(buf []byte) (MP4Info, bool) {
 				info.Tracks = append(info.Tracks, track)
 			}
 		}
+		if boxType == "mvex" {
+			payload := sliceBox(buf, dataOffset, boxSize-headerSize)
+			fragmentDuration, defaults := parseMvex(payload)
+			info.Fragmented = true
+			info.trexDefaults = defaults
+			if fragmentDuration > 0 && info.MovieTimescale > 0 {
+				info.mehdDuration = float64(fragmentDuration) / float64(info.MovieTimescale)
+			}
+		}
 		offset += boxSize
 	}
 	if info.Container.HasDuration() || len(info.Tracks) > 0 {
@@ -200,6 +237,7 @@ func parseTrak(buf []byte, movieTimescale uint32) (MP4Track, bool) {
 	var hasTkhd bool
 	var editDuration float64
 	var editMediaTime int64
+	var refs map[string][]uint32
 	for offset+8 <= int64(len(buf)) {
 		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
 		if boxSize <= 0 {
@@ -220,6 +258,9 @@ func parseTrak(buf []byte, movieTimescale uint32) (MP4Track, bool) {
 				editMediaTime = mediaTime
 			}
 		}
+		if boxType == "tref" {
+			refs = parseMP4TrackRefs(sliceBox(buf, dataOffset, boxSize-headerSize))
+		}
 		if boxType == "mdia" {
 			payload := sliceBox(buf, dataOffset, boxSize-headerSize)
 			if track, ok := parseMdia(payload); ok {
@@ -236,6 +277,13 @@ func parseTrak(buf []byte, movieTimescale uint32) (MP4Track, bool) {
 					track.CreationTime = tkhdInfo.CreationTime
 					track.ModificationTime = tkhdInfo.ModifiedTime
 				}
+				if refs == nil {
+					// tref usually precedes mdia, but nothing requires it.
+					if tref, ok := findMP4Box(sliceBox(buf, offset+boxSize, int64(len(buf))), "tref"); ok {
+						refs = parseMP4TrackRefs(tref)
+					}
+				}
+				track.refs = refs
 				return track, true
 			}
 		}
@@ -252,6 +300,7 @@ func parseMdia(buf []byte) (MP4Track, bool) {
 	var trackDuration float64
 	var trackTimescale uint32
 	var language string
+	var minf []byte
 	for offset+8 <= int64(len(buf)) {
 		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
 		if boxSize <= 0 {
@@ -273,6 +322,7 @@ func parseMdia(buf []byte) (MP4Track, bool) {
 		}
 		if boxType == "minf" {
 			payload := sliceBox(buf, dataOffset, boxSize-headerSize)
+			minf = payload
 			if info, ok := parseMinfSample(payload); ok {
 				sampleInfo = info
 			}
@@ -289,6 +339,10 @@ func parseMdia(buf []byte) (MP4Track, bool) {
 	if sampleInfo.Format != "" {
 		format = sampleInfo.Format
 	}
+	var stbl []byte
+	if kind == StreamText || kind == StreamOther {
+		stbl, _ = findMP4Box(minf, "stbl")
+	}
 	return MP4Track{
 		Kind:            kind,
 		Format:          format,
@@ -308,6 +362,7 @@ func parseMdia(buf []byte) (MP4Track, bool) {
 		Timescale:       trackTimescale,
 		Width:           sampleInfo.Width,
 		Height:          sampleInfo.Height,
+		stbl:            stbl,
 	}, true
 }
 
@@ -449,6 +504,8 @@ func mapHandlerType(handler string) (StreamKind, string) {
 		return StreamAudio, "Audio"
 	case "text", "sbtl", "subt":
 		return StreamText, "Text"
+	case "tmcd":
+		return StreamOther, "QuickTime TC"
 	default:
 		return "", ""
 	}
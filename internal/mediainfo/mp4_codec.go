@@ -95,18 +95,40 @@ func parseStsdForSample(buf []byte) (SampleInfo, bool) {
 
 func mapMP4SampleEntry(sample string) string {
 	switch sample {
-	case "avc1", "avc3":
+	case "avc1", "avc3", "dvav", "dva1":
 		return "AVC"
-	case "hvc1", "hev1":
+	case "hvc1", "hev1", "dvh1", "dvhe":
 		return "HEVC"
+	case "av01", "dav1":
+		return "AV1"
+	case "vp09":
+		return "VP9"
 	case "mp4v":
 		return "MPEG-4 Visual"
+	case "apch", "apcn", "apcs", "apco", "ap4h", "ap4x":
+		return "ProRes"
+	case "AVdn":
+		return "VC-3"
+	case "mjpa", "jpeg":
+		return "JPEG"
+	case "2vuy", "v210":
+		return "YUV"
+	case "raw ":
+		return "RGB"
 	case "mp4a":
 		return "AAC"
-	case "ac-3", "ac-4":
+	case "ac-3":
 		return "AC-3"
 	case "ec-3":
 		return "E-AC-3"
+	case "ac-4":
+		return "AC-4"
+	case "ipcm", "lpcm", "sowt", "twos", "in24", "in32", "fl32", "fl64":
+		return "PCM"
+	case "mha1", "mhm1":
+		return "MPEG-H 3D Audio"
+	case "dtsc", "dtsh", "dtsl", "dtse", "dtsx":
+		return "DTS"
 	case "alac":
 		return "ALAC"
 	case "flac":
@@ -126,7 +148,8 @@ func mapMP4SampleEntry(sample string) string {
 
 func isVideoSampleEntry(sample string) bool {
 	switch sample {
-	case "avc1", "avc3", "hvc1", "hev1", "mp4v":
+	case "avc1", "avc3", "dvav", "dva1", "hvc1", "hev1", "dvh1", "dvhe", "av01", "dav1", "vp09", "mp4v",
+		"apch", "apcn", "apcs", "apco", "ap4h", "ap4x", "AVdn", "mjpa", "jpeg", "raw ", "2vuy", "v210":
 		return true
 	default:
 		return false
@@ -135,7 +158,9 @@ func isVideoSampleEntry(sample string) bool {
 
 func isAudioSampleEntry(sample string) bool {
 	switch sample {
-	case "mp4a", "ac-3", "ec-3", "alac", "flac", "opus":
+	case "mp4a", "ac-3", "ec-3", "ac-4", "alac", "flac", "opus",
+		"ipcm", "lpcm", "sowt", "twos", "in24", "in32", "fl32", "fl64",
+		"mha1", "mhm1", "dtsc", "dtsh", "dtsl", "dtse", "dtsx":
 		return true
 	default:
 		return false
@@ -167,7 +192,7 @@ func parseVisualSampleEntry(entry []byte, sampleType string) sampleEntryResult {
 		}
 	}
 	var spsInfo h264SPSInfo
-	if sampleType == "avc1" || sampleType == "avc3" {
+	if mapMP4SampleEntry(sampleType) == "AVC" {
 		if payload, ok := findMP4ChildBox(entry, mp4VisualSampleEntryHeaderSize, "avcC"); ok {
 			_, avcFields, parsedSPS := parseAVCConfig(payload)
 			spsInfo = parsedSPS
@@ -254,6 +279,12 @@ func parseVisualSampleEntry(entry []byte, sampleType string) sampleEntryResult {
 		}
 		fields = appendFieldUnique(fields, Field{Name: "Color space", Value: "YUV"})
 	}
+	configFields, configJSON := parseVisualCodecConfig(entry, sampleType)
+	fields = append(fields, configFields...)
+	for k, v := range configJSON {
+		jsonExtras[k] = v
+	}
+	fields = appendDolbyVisionSampleEntry(fields, entry)
 	// When AVC bitstream says "not fixed" but container timing is CFR, official MediaInfo keeps CFR
 	// and reports the bitstream hint as FrameRate_Mode_Original=VFR.
 	if spsInfo.HasFixedFrameRate && !spsInfo.FixedFrameRate {
@@ -289,14 +320,12 @@ func parseAudioSampleEntry(entry []byte, sampleType string) sampleEntryResult {
 	if len(entry) < 36 {
 		return sampleEntryResult{}
 	}
-	channels := binary.BigEndian.Uint16(entry[24:26])
-	sampleRate := binary.BigEndian.Uint32(entry[32:36])
+	channels, rate := mp4AudioSampleEntryLayout(entry)
 	codecID := sampleType
 	fields := []Field{}
 	jsonExtras := map[string]string{}
-	fields = appendChannelFields(fields, uint64(channels))
-	if sampleRate > 0 {
-		rate := float64(sampleRate) / 65536
+	fields = appendChannelFields(fields, channels)
+	if rate > 0 {
 		fields = appendSampleRateField(fields, rate)
 		if sampleType == "mp4a" {
 			frameRate := rate / 1024.0
@@ -327,6 +356,13 @@ func parseAudioSampleEntry(entry []byte, sampleType string) sampleEntryResult {
 	} else if sampleType == "ac-3" || sampleType == "ec-3" {
 		fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
 	}
+	configFields, configJSON := parseAudioCodecConfig(entry, sampleType)
+	for _, field := range configFields {
+		fields = appendFieldUnique(fields, field)
+	}
+	for k, v := range configJSON {
+		jsonExtras[k] = v
+	}
 	if sampleType == "mp4a" {
 		// Prefer ESDS avgBitrate/maxBitrate (DecoderConfigDescriptor) over container-level btrt.
 		if avg, max, ok := parseESDSBitrates(entry); ok {
@@ -568,10 +604,12 @@ func parseBtrt(entry []byte, start int) (uint32, uint32, uint32, bool) {
 
 func mapVideoFormatInfo(sampleType string) string {
 	switch sampleType {
-	case "avc1", "avc3":
+	case "avc1", "avc3", "dvav", "dva1":
 		return "Advanced Video Codec"
-	case "hvc1", "hev1":
+	case "hvc1", "hev1", "dvh1", "dvhe":
 		return "High Efficiency Video Coding"
+	case "av01", "dav1":
+		return "AOMedia Video 1"
 	case "mp4v":
 		return "MPEG-4 Visual"
 	default:
@@ -600,6 +638,10 @@ func mapAudioFormatInfo(sampleType string) string {
 		return "Audio Coding 3"
 	case "ec-3":
 		return "Enhanced AC-3"
+	case "ac-4":
+		return "Audio Coding 4"
+	case "dtsc", "dtsh", "dtsl", "dtse", "dtsx":
+		return "Digital Theater Systems"
 	default:
 		return ""
 	}
@@ -0,0 +1,455 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// maxMoofSize bounds a single movie fragment header; real ones are a few KiB.
+const maxMoofSize = int64(16 << 20)
+
+// mp4TrackDefaults holds the trex sample defaults that tfhd may override per fragment.
+type mp4TrackDefaults struct {
+	duration uint32
+	size     uint32
+}
+
+// mp4FragmentTrack aggregates the trun samples of one track across all fragments.
+type mp4FragmentTrack struct {
+	samples       uint64
+	bytes         uint64
+	ticks         uint64
+	firstDelta    uint32
+	lastDelta     uint32
+	variable      bool
+	firstOffset   uint64
+	sizeHead      []uint32
+	sizeTail      []uint32
+	hasFirstDelta bool
+}
+
+// mp4Fragments is what a scan of the moof and sidx boxes of a file yields.
+type mp4Fragments struct {
+	count         int
+	firstDuration float64
+	sidxDuration  float64
+	tracks        map[uint32]*mp4FragmentTrack
+}
+
+// parseMvex reads the mehd fragment duration (movie timescale units) and the trex defaults.
+func parseMvex(buf []byte) (uint64, map[uint32]mp4TrackDefaults) {
+	var fragmentDuration uint64
+	defaults := map[uint32]mp4TrackDefaults{}
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		payload := sliceBox(buf, offset+headerSize, boxSize-headerSize)
+		switch boxType {
+		case "mehd":
+			if len(payload) >= 12 && payload[0] == 1 {
+				fragmentDuration = binary.BigEndian.Uint64(payload[4:12])
+			} else if len(payload) >= 8 {
+				fragmentDuration = uint64(binary.BigEndian.Uint32(payload[4:8]))
+			}
+		case "trex":
+			if len(payload) >= 24 {
+				id := binary.BigEndian.Uint32(payload[4:8])
+				defaults[id] = mp4TrackDefaults{
+					duration: binary.BigEndian.Uint32(payload[12:16]),
+					size:     binary.BigEndian.Uint32(payload[16:20]),
+				}
+			}
+		}
+		offset += boxSize
+	}
+	return fragmentDuration, defaults
+}
+
+// scanMP4Fragments walks the top-level boxes of r and aggregates every moof and sidx.
+// timescales maps track IDs to their mdhd timescale, used for fragment durations.
+func scanMP4Fragments(r io.ReaderAt, size int64, defaults map[uint32]mp4TrackDefaults, timescales map[uint32]uint32) mp4Fragments {
+	frags := mp4Fragments{tracks: map[uint32]*mp4FragmentTrack{}}
+	var offset int64
+	for offset+8 <= size {
+		boxSize, boxType, headerSize, ok := readMP4BoxHeader(r, offset, size)
+		if !ok || boxSize <= 0 {
+			break
+		}
+		payloadSize := min(boxSize, size-offset) - headerSize
+		switch boxType {
+		case "moof":
+			if payloadSize > maxMoofSize || payloadSize < 0 {
+				break
+			}
+			buf := make([]byte, payloadSize)
+			if _, err := r.ReadAt(buf, offset+headerSize); err != nil && err != io.EOF {
+				return frags
+			}
+			durations := parseMoof(buf, offset, defaults, frags.tracks)
+			frags.count++
+			if frags.count == 1 {
+				for id, ticks := range durations {
+					if timescale := timescales[id]; timescale > 0 {
+						frags.firstDuration = max(frags.firstDuration, float64(ticks)/float64(timescale))
+					}
+				}
+			}
+		case "sidx":
+			if payloadSize > maxMoofSize || payloadSize < 0 {
+				break
+			}
+			buf := make([]byte, payloadSize)
+			if _, err := r.ReadAt(buf, offset+headerSize); err != nil && err != io.EOF {
+				return frags
+			}
+			frags.sidxDuration += parseSidxDuration(buf)
+		}
+		offset += boxSize
+	}
+	return frags
+}
+
+// parseMoof adds the samples of every traf to tracks and returns the ticks each track spans
+// in this fragment. moofOffset is the file offset of the moof box, the default data base.
+func parseMoof(buf []byte, moofOffset int64, defaults map[uint32]mp4TrackDefaults, tracks map[uint32]*mp4FragmentTrack) map[uint32]uint64 {
+	durations := map[uint32]uint64{}
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		if boxType == "traf" {
+			payload := sliceBox(buf, offset+headerSize, boxSize-headerSize)
+			if id, ticks, ok := parseTraf(payload, moofOffset, defaults, tracks); ok {
+				durations[id] += ticks
+			}
+		}
+		offset += boxSize
+	}
+	return durations
+}
+
+func parseTraf(buf []byte, moofOffset int64, defaults map[uint32]mp4TrackDefaults, tracks map[uint32]*mp4FragmentTrack) (uint32, uint64, bool) {
+	var (
+		trackID    uint32
+		hasTfhd    bool
+		base       = uint64(moofOffset)
+		sampleDur  uint32
+		sampleSize uint32
+		ticks      uint64
+	)
+	dataEnd := base
+	var offset int64
+	for offset+8 <= int64(len(buf)) {
+		boxSize, boxType, headerSize := readMP4BoxHeaderFrom(buf, offset)
+		if boxSize <= 0 {
+			break
+		}
+		payload := sliceBox(buf, offset+headerSize, boxSize-headerSize)
+		switch boxType {
+		case "tfhd":
+			if len(payload) < 8 {
+				return 0, 0, false
+			}
+			flags := binary.BigEndian.Uint32(payload[0:4]) & 0xFFFFFF
+			trackID = binary.BigEndian.Uint32(payload[4:8])
+			sampleDur, sampleSize = defaults[trackID].duration, defaults[trackID].size
+			pos := 8
+			read := func() (uint32, bool) {
+				if pos+4 > len(payload) {
+					return 0, false
+				}
+				v := binary.BigEndian.Uint32(payload[pos : pos+4])
+				pos += 4
+				return v, true
+			}
+			if flags&0x01 != 0 {
+				if pos+8 > len(payload) {
+					return 0, 0, false
+				}
+				base = binary.BigEndian.Uint64(payload[pos : pos+8])
+				dataEnd = base
+				pos += 8
+			}
+			if flags&0x02 != 0 {
+				read() // sample_description_index
+			}
+			if flags&0x08 != 0 {
+				if v, ok := read(); ok {
+					sampleDur = v
+				}
+			}
+			if flags&0x10 != 0 {
+				if v, ok := read(); ok {
+					sampleSize = v
+				}
+			}
+			hasTfhd = true
+		case "trun":
+			if !hasTfhd {
+				break
+			}
+			track := tracks[trackID]
+			if track == nil {
+				track = &mp4FragmentTrack{}
+				tracks[trackID] = track
+			}
+			added, end := parseTrun(payload, base, dataEnd, sampleDur, sampleSize, track)
+			ticks += added
+			dataEnd = end
+		}
+		offset += boxSize
+	}
+	return trackID, ticks, hasTfhd
+}
+
+// parseTrun adds one track run to track. Samples without explicit data offset follow the
+// previous run (dataEnd); the return values are the ticks covered and the new data end.
+func parseTrun(payload []byte, base, dataEnd uint64, defaultDur, defaultSize uint32, track *mp4FragmentTrack) (uint64, uint64) {
+	if len(payload) < 8 {
+		return 0, dataEnd
+	}
+	flags := binary.BigEndian.Uint32(payload[0:4]) & 0xFFFFFF
+	count := binary.BigEndian.Uint32(payload[4:8])
+	pos := 8
+	dataOffset := dataEnd
+	if flags&0x01 != 0 {
+		if pos+4 > len(payload) {
+			return 0, dataEnd
+		}
+		dataOffset = uint64(int64(base) + int64(int32(binary.BigEndian.Uint32(payload[pos:pos+4]))))
+		pos += 4
+	}
+	if flags&0x04 != 0 {
+		pos += 4 // first_sample_flags
+	}
+	entrySize := 0
+	for _, bit := range []uint32{0x100, 0x200, 0x400, 0x800} {
+		if flags&bit != 0 {
+			entrySize += 4
+		}
+	}
+	if entrySize > 0 && uint64(len(payload)-min(pos, len(payload)))/uint64(entrySize) < uint64(count) {
+		count = uint32((len(payload) - min(pos, len(payload))) / entrySize)
+	}
+	if count > 0 && track.samples == 0 {
+		track.firstOffset = dataOffset
+	}
+	var ticks, bytes uint64
+	for i := uint32(0); i < count; i++ {
+		duration, size := defaultDur, defaultSize
+		if flags&0x100 != 0 {
+			duration = binary.BigEndian.Uint32(payload[pos : pos+4])
+			pos += 4
+		}
+		if flags&0x200 != 0 {
+			size = binary.BigEndian.Uint32(payload[pos : pos+4])
+			pos += 4
+		}
+		if flags&0x400 != 0 {
+			pos += 4
+		}
+		if flags&0x800 != 0 {
+			pos += 4
+		}
+		if !track.hasFirstDelta {
+			track.firstDelta = duration
+			track.hasFirstDelta = true
+		} else if duration != track.firstDelta && i+1 < count {
+			// A short final sample per run is normal; only interior changes make the rate variable.
+			track.variable = true
+		}
+		track.lastDelta = duration
+		if len(track.sizeHead) < mp4SampleSizeHeadMax {
+			track.sizeHead = append(track.sizeHead, size)
+		}
+		track.sizeTail = append(track.sizeTail, size)
+		if len(track.sizeTail) > mp4SampleSizeTailMax {
+			track.sizeTail = track.sizeTail[1:]
+		}
+		ticks += uint64(duration)
+		bytes += uint64(size)
+	}
+	track.samples += uint64(count)
+	track.ticks += ticks
+	track.bytes += bytes
+	return ticks, dataOffset + bytes
+}
+
+// parseSidxDuration sums the subsegment durations of media references. References to other
+// sidx boxes are skipped, since those boxes are counted when they are reached.
+func parseSidxDuration(payload []byte) float64 {
+	if len(payload) < 12 {
+		return 0
+	}
+	version := payload[0]
+	timescale := binary.BigEndian.Uint32(payload[8:12])
+	pos := 12
+	if version == 0 {
+		pos += 8
+	} else {
+		pos += 16
+	}
+	if timescale == 0 || pos+4 > len(payload) {
+		return 0
+	}
+	count := int(binary.BigEndian.Uint16(payload[pos+2 : pos+4]))
+	pos += 4
+	var total uint64
+	for i := 0; i < count && pos+12 <= len(payload); i++ {
+		if payload[pos]&0x80 == 0 {
+			total += uint64(binary.BigEndian.Uint32(payload[pos+4 : pos+8]))
+		}
+		pos += 12
+	}
+	return float64(total) / float64(timescale)
+}
+
+// applyFragments folds fragment samples into the moov-described tracks and picks the movie
+// duration: mehd when present, else the sidx total, else the longest track.
+func (info *MP4Info) applyFragments(frags mp4Fragments) {
+	info.FragmentCount = frags.count
+	info.FragmentDuration = frags.firstDuration
+	longest := 0.0
+	for i := range info.Tracks {
+		track := &info.Tracks[i]
+		frag := frags.tracks[track.ID]
+		if frag != nil && frag.samples > 0 {
+			track.SampleCount += frag.samples
+			track.SampleBytes += frag.bytes
+			if track.Timescale > 0 {
+				track.DurationSeconds += float64(frag.ticks) / float64(track.Timescale)
+			}
+			if track.SampleDelta == 0 {
+				track.SampleDelta = frag.firstDelta
+			} else if track.SampleDelta != frag.firstDelta {
+				track.VariableDeltas = true
+			}
+			track.LastSampleDelta = frag.lastDelta
+			track.VariableDeltas = track.VariableDeltas || frag.variable
+			if track.FirstChunkOff == 0 {
+				track.FirstChunkOff = frag.firstOffset
+			}
+			if len(track.SampleSizeHead) == 0 {
+				track.SampleSizeHead = frag.sizeHead
+			}
+			track.SampleSizeTail = frag.sizeTail
+		}
+		longest = max(longest, track.DurationSeconds)
+	}
+	switch {
+	case info.mehdDuration > 0:
+		info.Container.DurationSeconds = info.mehdDuration
+	case frags.sidxDuration > 0:
+		info.Container.DurationSeconds = frags.sidxDuration
+	case longest > info.Container.DurationSeconds:
+		info.Container.DurationSeconds = longest
+	}
+}
+
+func (info *MP4Info) trackTimescales() map[uint32]uint32 {
+	timescales := make(map[uint32]uint32, len(info.Tracks))
+	for _, track := range info.Tracks {
+		timescales[track.ID] = track.Timescale
+	}
+	return timescales
+}
+
+// ParseMP4Segment analyzes a fragmented MP4 media segment (DASH/CMAF/HLS .m4s), which has no
+// moov of its own, with the track setup read from its initialization segment.
+func ParseMP4Segment(init io.ReaderAt, initSize int64, r io.ReaderAt, size int64) (MP4Info, bool) {
+	info, ok := ParseMP4(init, initSize)
+	if !ok || !info.Fragmented {
+		return MP4Info{}, false
+	}
+	frags := scanMP4Fragments(r, size, info.trexDefaults, info.trackTimescales())
+	if frags.count == 0 {
+		return MP4Info{}, false
+	}
+	// The init segment's mehd describes the whole presentation, not this segment.
+	info.mehdDuration = 0
+	info.Container.DurationSeconds = 0
+	info.applyFragments(frags)
+	return info, true
+}
+
+// parseMP4WithInit parses file as an MP4 and, when it is a bare media segment, pairs it with
+// opts.InitSegment or an initialization segment found next to it.
+func parseMP4WithInit(file io.ReaderAt, size int64, path string, onDisk bool, opts AnalyzeOptions) (MP4Info, bool) {
+	if info, ok := ParseMP4(file, size); ok {
+		return info, true
+	}
+	initPath := opts.InitSegment
+	if initPath == "" && onDisk && isMP4MediaSegment(file, path) {
+		initPath = findMP4InitSegment(path)
+	}
+	if initPath == "" {
+		return MP4Info{}, false
+	}
+	init, err := os.Open(initPath)
+	if err != nil {
+		return MP4Info{}, false
+	}
+	defer init.Close()
+	stat, err := init.Stat()
+	if err != nil {
+		return MP4Info{}, false
+	}
+	info, ok := ParseMP4Segment(init, stat.Size(), file, size)
+	info.initSegment = initPath
+	return info, ok
+}
+
+// isMP4MediaSegment reports whether a file without a usable moov is a media segment: it has a
+// segment extension or starts with a styp, sidx or moof box. A truncated movie is neither.
+func isMP4MediaSegment(file io.ReaderAt, path string) bool {
+	if strings.EqualFold(filepath.Ext(path), ".m4s") {
+		return true
+	}
+	var header [8]byte
+	if _, err := file.ReadAt(header[:], 0); err != nil {
+		return false
+	}
+	switch string(header[4:8]) {
+	case "styp", "sidx", "moof":
+		return true
+	}
+	return false
+}
+
+// ffmpeg's DASH muxer names segments chunk-stream<N>-<number>.m4s next to init-stream<N>.m4s.
+var dashChunkName = regexp.MustCompile(`^chunk-(stream\d+)-\d+\.m4s$`)
+
+// findMP4InitSegment looks for the initialization segment of a media segment using the naming
+// schemes of common packagers.
+func findMP4InitSegment(path string) string {
+	dir, name := filepath.Split(path)
+	var candidates []string
+	if m := dashChunkName.FindStringSubmatch(name); m != nil {
+		candidates = append(candidates, "init-"+m[1]+".m4s")
+	}
+	stem := strings.TrimRight(strings.TrimSuffix(name, filepath.Ext(name)), "0123456789")
+	stem = strings.TrimRight(stem, "-_.")
+	if stem != "" {
+		candidates = append(candidates, stem+"_init.mp4", stem+"-init.mp4", stem+"init.mp4", stem+"_init.m4s", stem+"-init.m4s")
+	}
+	candidates = append(candidates, "init.mp4", "init.m4s")
+	for _, candidate := range candidates {
+		full := filepath.Join(dir, candidate)
+		if full == filepath.Clean(path) {
+			continue
+		}
+		if info, err := os.Stat(full); err == nil && info.Mode().IsRegular() {
+			return full
+		}
+	}
+	return ""
+}
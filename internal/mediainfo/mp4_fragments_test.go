@@ -0,0 +1,236 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// buildFragmentedMoov builds a moov with one 1000 Hz video track (ID 1) and an mvex whose trex
+// defaults to 40-tick samples. mehd is written when fragmentDuration > 0.
+func buildFragmentedMoov(fragmentDuration uint32) []byte {
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+
+	tkhd := make([]byte, 84)
+	tkhd[3] = 0x03
+	binary.BigEndian.PutUint32(tkhd[12:16], 1)
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint32(mdhd[12:16], 1000)
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], "vide")
+	var mdia bytes.Buffer
+	writeMP4Box(&mdia, "mdhd", mdhd)
+	writeMP4Box(&mdia, "hdlr", hdlr)
+	var trak bytes.Buffer
+	writeMP4Box(&trak, "tkhd", tkhd)
+	writeMP4Box(&trak, "mdia", mdia.Bytes())
+
+	var mvex bytes.Buffer
+	if fragmentDuration > 0 {
+		mehd := make([]byte, 8)
+		binary.BigEndian.PutUint32(mehd[4:8], fragmentDuration)
+		writeMP4Box(&mvex, "mehd", mehd)
+	}
+	trex := make([]byte, 24)
+	binary.BigEndian.PutUint32(trex[4:8], 1)
+	binary.BigEndian.PutUint32(trex[8:12], 1)
+	binary.BigEndian.PutUint32(trex[12:16], 40)
+	writeMP4Box(&mvex, "trex", trex)
+
+	var moov bytes.Buffer
+	writeMP4Box(&moov, "mvhd", mvhd)
+	writeMP4Box(&moov, "trak", trak.Bytes())
+	writeMP4Box(&moov, "mvex", mvex.Bytes())
+	var out bytes.Buffer
+	writeMP4Box(&out, "moov", moov.Bytes())
+	return out.Bytes()
+}
+
+// writeFragment appends a moof with samples 100-byte samples of track 1, followed by its mdat.
+func writeFragment(buf *bytes.Buffer, sequence uint32, samples int) {
+	mfhd := make([]byte, 8)
+	binary.BigEndian.PutUint32(mfhd[4:8], sequence)
+	tfhd := make([]byte, 8)
+	binary.BigEndian.PutUint32(tfhd[0:4], 0x020000) // default-base-is-moof
+	binary.BigEndian.PutUint32(tfhd[4:8], 1)
+	trun := make([]byte, 12+4*samples)
+	binary.BigEndian.PutUint32(trun[0:4], 0x000201) // data offset, sample sizes
+	binary.BigEndian.PutUint32(trun[4:8], uint32(samples))
+	for i := 0; i < samples; i++ {
+		binary.BigEndian.PutUint32(trun[12+4*i:], 100)
+	}
+	// The data offset is relative to the moof start and points just past the mdat header:
+	// moof header, mfhd, traf header, tfhd, trun, mdat header.
+	binary.BigEndian.PutUint32(trun[8:12], uint32(8+16+8+16+8+len(trun)+8))
+	var traf bytes.Buffer
+	writeMP4Box(&traf, "tfhd", tfhd)
+	writeMP4Box(&traf, "trun", trun)
+	var moof bytes.Buffer
+	writeMP4Box(&moof, "mfhd", mfhd)
+	writeMP4Box(&moof, "traf", traf.Bytes())
+	writeMP4Box(buf, "moof", moof.Bytes())
+	writeMP4Box(buf, "mdat", make([]byte, 100*samples))
+}
+
+func TestParseMP4Fragmented(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		mehd     uint32
+		duration float64
+	}{
+		{name: "trun", duration: 3},
+		{name: "mehd", mehd: 3200, duration: 3.2},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			writeMP4Box(&buf, "ftyp", []byte{'i', 's', 'o', '6', 0, 0, 0, 0})
+			buf.Write(buildFragmentedMoov(tc.mehd))
+			for i := 1; i <= 3; i++ {
+				writeFragment(&buf, uint32(i), 25)
+			}
+			info, ok := ParseMP4(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if !ok || len(info.Tracks) != 1 {
+				t.Fatalf("ParseMP4 ok=%v tracks=%d", ok, len(info.Tracks))
+			}
+			if !info.Fragmented || info.FragmentCount != 3 || info.FragmentDuration != 1 {
+				t.Fatalf("fragmented=%v count=%d duration=%v", info.Fragmented, info.FragmentCount, info.FragmentDuration)
+			}
+			track := info.Tracks[0]
+			if track.SampleCount != 75 || track.SampleBytes != 7500 || track.DurationSeconds != 3 {
+				t.Fatalf("samples=%d bytes=%d duration=%v", track.SampleCount, track.SampleBytes, track.DurationSeconds)
+			}
+			if want := uint64(bytes.Index(buf.Bytes(), []byte("mdat")) + 4); track.FirstChunkOff != want {
+				t.Fatalf("first sample offset=%d, want %d", track.FirstChunkOff, want)
+			}
+			if track.SampleDelta != 40 || track.VariableDeltas {
+				t.Fatalf("delta=%d variable=%v", track.SampleDelta, track.VariableDeltas)
+			}
+			if info.Container.DurationSeconds != tc.duration {
+				t.Fatalf("movie duration=%v, want %v", info.Container.DurationSeconds, tc.duration)
+			}
+		})
+	}
+}
+
+func TestAnalyzeMP4TruncatedMovieNotPaired(t *testing.T) {
+	dir := t.TempDir()
+	var init bytes.Buffer
+	writeMP4Box(&init, "ftyp", []byte{'i', 's', 'o', '6', 0, 0, 0, 0})
+	init.Write(buildFragmentedMoov(0))
+	if err := os.WriteFile(filepath.Join(dir, "init.mp4"), init.Bytes(), 0o644); err != nil {
+		t.Fatalf("write init: %v", err)
+	}
+
+	// A fragmented movie that lost its moov: it starts with ftyp, so it is not a media segment.
+	var movie bytes.Buffer
+	writeMP4Box(&movie, "ftyp", []byte{'i', 's', 'o', 'm', 0, 0, 0, 0})
+	writeFragment(&movie, 1, 25)
+	path := filepath.Join(dir, "movie.mp4")
+	if err := os.WriteFile(path, movie.Bytes(), 0o644); err != nil {
+		t.Fatalf("write movie: %v", err)
+	}
+	if info, ok := parseMP4WithInit(bytes.NewReader(movie.Bytes()), int64(movie.Len()), path, true, defaultAnalyzeOptions()); ok {
+		t.Fatalf("truncated movie paired with init.mp4: %+v", info)
+	}
+
+	report, err := AnalyzeFile(path)
+	if err == nil && len(report.Streams) != 0 {
+		t.Fatalf("truncated movie reported streams: %+v", report.Streams)
+	}
+}
+
+func TestAnalyzeMP4MediaSegment(t *testing.T) {
+	dir := t.TempDir()
+	var init bytes.Buffer
+	writeMP4Box(&init, "ftyp", []byte{'i', 's', 'o', '6', 0, 0, 0, 0})
+	init.Write(buildFragmentedMoov(60000))
+	if err := os.WriteFile(filepath.Join(dir, "init.mp4"), init.Bytes(), 0o644); err != nil {
+		t.Fatalf("write init: %v", err)
+	}
+
+	var seg bytes.Buffer
+	writeMP4Box(&seg, "styp", []byte{'m', 's', 'd', 'h', 0, 0, 0, 0})
+	sidx := make([]byte, 36)
+	binary.BigEndian.PutUint32(sidx[4:8], 1)
+	binary.BigEndian.PutUint32(sidx[8:12], 1000)
+	binary.BigEndian.PutUint16(sidx[22:24], 1)
+	binary.BigEndian.PutUint32(sidx[28:32], 2000)
+	writeMP4Box(&seg, "sidx", sidx)
+	writeFragment(&seg, 7, 25)
+	writeFragment(&seg, 8, 25)
+	segPath := filepath.Join(dir, "segment_00007.m4s")
+	if err := os.WriteFile(segPath, seg.Bytes(), 0o644); err != nil {
+		t.Fatalf("write segment: %v", err)
+	}
+
+	report, err := AnalyzeFile(segPath)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if got := findField(report.General.Fields, "Format"); got != "MPEG-4" {
+		t.Fatalf("Format=%q, want MPEG-4", got)
+	}
+	if len(report.Streams) != 1 || report.Streams[0].Kind != StreamVideo {
+		t.Fatalf("streams=%+v, want one video stream", report.Streams)
+	}
+	// The segment's sidx, not the presentation-wide mehd, gives the duration.
+	if got := findField(report.General.Fields, "Duration"); got != "2 s 0 ms" {
+		t.Fatalf("Duration=%q, want 2 s 0 ms", got)
+	}
+	if got := report.Streams[0].JSON["FrameCount"]; got != "50" {
+		t.Fatalf("FrameCount=%q, want 50", got)
+	}
+	if got := report.General.JSONRaw["extra"]; got != `{"IsFragmented":"Yes","FragmentCount":"2","FragmentDuration":"1.000"}` {
+		t.Fatalf("extra=%s", got)
+	}
+
+	// Without an init segment there is nothing to describe the tracks.
+	if err := os.Remove(filepath.Join(dir, "init.mp4")); err != nil {
+		t.Fatalf("remove init: %v", err)
+	}
+	report, err = AnalyzeFile(segPath)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if len(report.Streams) != 0 {
+		t.Fatalf("streams=%d without init segment, want 0", len(report.Streams))
+	}
+	opts := defaultAnalyzeOptions()
+	opts.InitSegment = filepath.Join(t.TempDir(), "elsewhere.mp4")
+	if err := os.WriteFile(opts.InitSegment, init.Bytes(), 0o644); err != nil {
+		t.Fatalf("write init: %v", err)
+	}
+	report, err = AnalyzeFileWithOptions(segPath, opts)
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	if len(report.Streams) != 1 {
+		t.Fatalf("streams=%d with InitSegment, want 1", len(report.Streams))
+	}
+}
+
+func TestFindMP4InitSegment(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"init-stream0.m4s", "init-stream1.m4s", "video_init.mp4"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatalf("write: %v", err)
+		}
+	}
+	cases := map[string]string{
+		"chunk-stream1-00012.m4s": "init-stream1.m4s",
+		"video_0003.m4s":          "video_init.mp4",
+		"audio_0003.m4s":          "",
+	}
+	for segment, want := range cases {
+		got := findMP4InitSegment(filepath.Join(dir, segment))
+		if want != "" {
+			want = filepath.Join(dir, want)
+		}
+		if got != want {
+			t.Fatalf("findMP4InitSegment(%s)=%q, want %q", segment, got, want)
+		}
+	}
+}
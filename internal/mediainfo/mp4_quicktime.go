@@ -0,0 +1,328 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+// Chapter text tracks hold one short sample per chapter; cap what is read so a bogus sample
+// table cannot make us walk the whole file.
+const (
+	maxMP4ChapterSamples   = 1024
+	maxMP4ChapterTextBytes = 4 << 10
+)
+
+// tmcd sample entry flags (QuickTime File Format, "Timecode Sample Description").
+const (
+	mp4TimeCodeDropFrame = 0x0001
+	mp4TimeCode24Hour    = 0x0002
+	mp4TimeCodeCounter   = 0x0008
+)
+
+type mp4TimeCodeEntry struct {
+	flags         uint32
+	timescale     uint32
+	frameDuration uint32
+	frames        uint32 // nominal frames per second, as written in the entry
+}
+
+type mp4Sample struct {
+	offset uint64
+	size   uint32
+	time   uint64 // decode time in track timescale units
+}
+
+// parseMP4TrackRefs reads the tref child boxes (chap, tmcd, ...) into the referenced track IDs.
+func parseMP4TrackRefs(tref []byte) map[string][]uint32 {
+	refs := map[string][]uint32{}
+	pos := 0
+	for pos+8 <= len(tref) {
+		size := int(binary.BigEndian.Uint32(tref[pos : pos+4]))
+		if size < 8 || pos+size > len(tref) {
+			break
+		}
+		typ := string(tref[pos+4 : pos+8])
+		for i := pos + 8; i+4 <= pos+size; i += 4 {
+			if id := binary.BigEndian.Uint32(tref[i : i+4]); id > 0 {
+				refs[typ] = append(refs[typ], id)
+			}
+		}
+		pos += size
+	}
+	if len(refs) == 0 {
+		return nil
+	}
+	return refs
+}
+
+// applyQuickTimeTracks reads the samples moov only points at: the first frame of tmcd time
+// code tracks, carried over to the video tracks, and the chapter titles of the text track
+// referenced through tref/chap when there is no Nero chpl.
+func (info *MP4Info) applyQuickTimeTracks(r io.ReaderAt, size int64) {
+	for i := range info.Tracks {
+		if info.Tracks[i].Kind == StreamOther && len(info.Tracks[i].stbl) > 0 {
+			applyMP4TimeCode(r, size, &info.Tracks[i])
+		}
+	}
+	for i := range info.Tracks {
+		video := &info.Tracks[i]
+		if video.Kind != StreamVideo || findField(video.Fields, "Time code of first frame") != "" {
+			continue
+		}
+		if tc := info.timeCodeFor(*video); tc != "" {
+			video.Fields = append(video.Fields, Field{Name: "Time code of first frame", Value: tc})
+		}
+	}
+	if len(info.Chapters) == 0 {
+		info.Chapters = info.readQuickTimeChapters(r, size)
+	}
+}
+
+// timeCodeFor returns the first-frame time code of the tmcd track a video track references,
+// or of the first one when the video track has no tref/tmcd.
+func (info *MP4Info) timeCodeFor(video MP4Track) string {
+	ids := video.refs["tmcd"]
+	for _, track := range info.Tracks {
+		if track.Kind != StreamOther || (len(ids) > 0 && !slices.Contains(ids, track.ID)) {
+			continue
+		}
+		if tc := findField(track.Fields, "Time code of first frame"); tc != "" {
+			return tc
+		}
+	}
+	return ""
+}
+
+func (info *MP4Info) readQuickTimeChapters(r io.ReaderAt, size int64) []mp4Chapter {
+	for _, track := range info.Tracks {
+		for _, id := range track.refs["chap"] {
+			for _, text := range info.Tracks {
+				if text.ID != id || text.Kind != StreamText || len(text.stbl) == 0 || text.Timescale == 0 {
+					continue
+				}
+				if chapters := readMP4ChapterSamples(r, size, text); len(chapters) > 0 {
+					return chapters
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func readMP4ChapterSamples(r io.ReaderAt, size int64, track MP4Track) []mp4Chapter {
+	var out []mp4Chapter
+	for _, sample := range mp4SampleTable(track.stbl, maxMP4ChapterSamples) {
+		if sample.size < 2 || sample.offset+uint64(sample.size) > uint64(size) {
+			continue
+		}
+		buf := make([]byte, min(sample.size, maxMP4ChapterTextBytes))
+		if _, err := r.ReadAt(buf, int64(sample.offset)); err != nil && err != io.EOF {
+			continue
+		}
+		out = append(out, mp4Chapter{
+			startMs: int64(sample.time * 1000 / uint64(track.Timescale)),
+			title:   decodeMP4TextSample(buf),
+		})
+	}
+	return out
+}
+
+// decodeMP4TextSample returns the text of a QuickTime/3GPP text sample: a 16-bit length, then
+// UTF-8 text or, after a byte order mark, UTF-16. Style boxes after the text are ignored.
+func decodeMP4TextSample(buf []byte) string {
+	if len(buf) < 2 {
+		return ""
+	}
+	n := int(binary.BigEndian.Uint16(buf[0:2]))
+	text := buf[2:min(2+n, len(buf))]
+	if len(text) >= 2 && text[0] == 0xFE && text[1] == 0xFF {
+		units := make([]uint16, 0, len(text)/2)
+		for i := 2; i+1 < len(text); i += 2 {
+			units = append(units, binary.BigEndian.Uint16(text[i:i+2]))
+		}
+		return strings.TrimRight(string(utf16.Decode(units)), "\x00")
+	}
+	return strings.TrimRight(string(text), "\x00")
+}
+
+// applyMP4TimeCode fills a tmcd track's fields from its sample entry and first sample, the
+// frame number the time code starts at.
+func applyMP4TimeCode(r io.ReaderAt, size int64, track *MP4Track) {
+	stsd, _ := findMP4Box(track.stbl, "stsd")
+	entry, ok := parseMP4TimeCodeEntry(stsd)
+	if !ok {
+		return
+	}
+	fields := []Field{{Name: "Type", Value: "Time code"}}
+	if entry.timescale%entry.frameDuration == 0 {
+		fields = append(fields, Field{Name: "Frame rate", Value: formatFrameRate(float64(entry.timescale / entry.frameDuration))})
+	} else {
+		div := uint32(gcd(uint64(entry.timescale), uint64(entry.frameDuration)))
+		fields = append(fields, Field{Name: "Frame rate", Value: formatFrameRateRatio(entry.timescale/div, entry.frameDuration/div)})
+	}
+	if samples := mp4SampleTable(track.stbl, 1); len(samples) == 1 && samples[0].size >= 4 &&
+		entry.flags&mp4TimeCodeCounter == 0 && samples[0].offset+4 <= uint64(size) {
+		var buf [4]byte
+		if _, err := r.ReadAt(buf[:], int64(samples[0].offset)); err == nil || err == io.EOF {
+			fields = append(fields, Field{Name: "Time code of first frame", Value: formatMP4TimeCode(binary.BigEndian.Uint32(buf[:]), entry)})
+		}
+	}
+	track.Fields = append(fields, track.Fields...)
+	if track.JSON == nil {
+		track.JSON = map[string]string{}
+	}
+	track.JSON["TimeCode_DropFrame"] = formatYesNo(entry.flags&mp4TimeCodeDropFrame != 0)
+}
+
+// mp4TimeCodeStream reports a tmcd track as an Other stream. Its four-byte sample is not
+// media, so unlike other tracks it gets no bit rate or stream size.
+func mp4TimeCodeStream(track MP4Track) Stream {
+	fields := []Field{}
+	if track.ID > 0 {
+		fields = append(fields, Field{Name: "ID", Value: strconv.FormatUint(uint64(track.ID), 10)})
+	}
+	fields = append(fields, Field{Name: "Format", Value: track.Format})
+	json := map[string]string{}
+	for k, v := range track.JSON {
+		json[k] = v
+	}
+	duration := track.DurationSeconds
+	if track.EditDuration > 0 {
+		duration = track.EditDuration
+	}
+	if duration > 0 {
+		fields = addStreamDuration(fields, duration)
+		json["Duration"] = formatJSONSeconds(duration)
+	}
+	for _, field := range track.Fields {
+		fields = appendFieldUnique(fields, field)
+	}
+	if lang := formatLanguage(track.LanguageCode); lang != "" {
+		fields = append(fields, Field{Name: "Language", Value: lang})
+		json["Language"] = normalizeLanguageCode(track.LanguageCode)
+	}
+	return Stream{Kind: StreamOther, Fields: fields, JSON: json}
+}
+
+// parseMP4TimeCodeEntry reads the first tmcd entry of an stsd payload: reserved(4) flags(4)
+// timescale(4) frameDuration(4) numberOfFrames(1) after the sample entry header.
+func parseMP4TimeCodeEntry(stsd []byte) (mp4TimeCodeEntry, bool) {
+	if len(stsd) < 8+34 || string(stsd[12:16]) != "tmcd" {
+		return mp4TimeCodeEntry{}, false
+	}
+	entry := stsd[8:]
+	tc := mp4TimeCodeEntry{
+		flags:         binary.BigEndian.Uint32(entry[20:24]),
+		timescale:     binary.BigEndian.Uint32(entry[24:28]),
+		frameDuration: binary.BigEndian.Uint32(entry[28:32]),
+		frames:        uint32(entry[32]),
+	}
+	if tc.timescale == 0 || tc.frameDuration == 0 {
+		return mp4TimeCodeEntry{}, false
+	}
+	if tc.frames == 0 {
+		tc.frames = uint32(math.Round(float64(tc.timescale) / float64(tc.frameDuration)))
+	}
+	return tc, tc.frames > 0
+}
+
+// formatMP4TimeCode turns a tmcd frame number into HH:MM:SS:FF, using ";" and skipping the
+// dropped frame numbers (two per minute at 30 fps, except every tenth minute) for drop frame.
+func formatMP4TimeCode(frame uint32, entry mp4TimeCodeEntry) string {
+	fps := uint64(entry.frames)
+	n := uint64(frame)
+	sep := ":"
+	if entry.flags&mp4TimeCodeDropFrame != 0 && fps%30 == 0 {
+		sep = ";"
+		drop := fps / 15
+		perMinute := fps*60 - drop
+		perTenMinutes := fps*600 - 9*drop
+		tens, rest := n/perTenMinutes, n%perTenMinutes
+		n += 9 * drop * tens
+		if rest > drop {
+			n += drop * ((rest - drop) / perMinute)
+		}
+	}
+	hours := n / (fps * 3600)
+	if entry.flags&mp4TimeCode24Hour != 0 {
+		hours %= 24
+	}
+	return fmt.Sprintf("%02d:%02d:%02d%s%02d", hours, n/(fps*60)%60, n/fps%60, sep, n%fps)
+}
+
+// mp4SampleTable resolves up to limit samples of a stbl into file offsets, sizes and decode
+// times using stsz, stsc, stco/co64 and stts.
+func mp4SampleTable(stbl []byte, limit int) []mp4Sample {
+	stsz, okSize := findMP4Box(stbl, "stsz")
+	stsc, okChunk := findMP4Box(stbl, "stsc")
+	if !okSize || !okChunk || len(stsz) < 12 || len(stsc) < 8 {
+		return nil
+	}
+	var chunks []uint64
+	if stco, ok := findMP4Box(stbl, "stco"); ok && len(stco) >= 8 {
+		for i := 8; i+4 <= len(stco) && len(chunks) < limit; i += 4 {
+			chunks = append(chunks, uint64(binary.BigEndian.Uint32(stco[i:i+4])))
+		}
+	} else if co64, ok := findMP4Box(stbl, "co64"); ok && len(co64) >= 8 {
+		for i := 8; i+8 <= len(co64) && len(chunks) < limit; i += 8 {
+			chunks = append(chunks, binary.BigEndian.Uint64(co64[i:i+8]))
+		}
+	}
+
+	fixedSize := binary.BigEndian.Uint32(stsz[4:8])
+	count := min(int(binary.BigEndian.Uint32(stsz[8:12])), limit)
+	stscEntries := min(int(binary.BigEndian.Uint32(stsc[4:8])), (len(stsc)-8)/12)
+	if stscEntries == 0 {
+		return nil
+	}
+	stscAt := func(j int) (firstChunk, perChunk uint32) {
+		base := 8 + 12*j
+		return binary.BigEndian.Uint32(stsc[base : base+4]), binary.BigEndian.Uint32(stsc[base+4 : base+8])
+	}
+
+	samples := make([]mp4Sample, 0, count)
+	entry := 0
+	for c := 0; c < len(chunks) && len(samples) < count; c++ {
+		for entry+1 < stscEntries {
+			if next, _ := stscAt(entry + 1); uint32(c+1) < next {
+				break
+			}
+			entry++
+		}
+		_, perChunk := stscAt(entry)
+		offset := chunks[c]
+		for k := uint32(0); k < perChunk && len(samples) < count; k++ {
+			sampleSize := fixedSize
+			if sampleSize == 0 {
+				pos := 12 + 4*len(samples)
+				if pos+4 > len(stsz) {
+					return samples
+				}
+				sampleSize = binary.BigEndian.Uint32(stsz[pos : pos+4])
+			}
+			samples = append(samples, mp4Sample{offset: offset, size: sampleSize})
+			offset += uint64(sampleSize)
+		}
+	}
+
+	if stts, ok := findMP4Box(stbl, "stts"); ok && len(stts) >= 8 {
+		var t uint64
+		i := 0
+		for pos := 8; pos+8 <= len(stts) && i < len(samples); pos += 8 {
+			runCount := binary.BigEndian.Uint32(stts[pos : pos+4])
+			delta := uint64(binary.BigEndian.Uint32(stts[pos+4 : pos+8]))
+			for k := uint32(0); k < runCount && i < len(samples); k++ {
+				samples[i].time = t
+				t += delta
+				i++
+			}
+		}
+	}
+	return samples
+}
@@ -0,0 +1,181 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// buildQuickTimeTrak builds a trak with tkhd and an mdia whose stbl holds the given boxes, in order.
+func buildQuickTimeTrak(id uint32, handler string, timescale, duration uint32, stbl ...mp4ChildBox) []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[12:16], id)
+	mdhd := make([]byte, 24)
+	binary.BigEndian.PutUint32(mdhd[12:16], timescale)
+	binary.BigEndian.PutUint32(mdhd[16:20], duration)
+	hdlr := make([]byte, 24)
+	copy(hdlr[4:8], "mhlr")
+	copy(hdlr[8:12], handler)
+
+	var stblBuf bytes.Buffer
+	for _, box := range stbl {
+		writeMP4Box(&stblBuf, box.typ, box.payload)
+	}
+	var minf bytes.Buffer
+	writeMP4Box(&minf, "stbl", stblBuf.Bytes())
+	var mdia bytes.Buffer
+	writeMP4Box(&mdia, "mdhd", mdhd)
+	writeMP4Box(&mdia, "hdlr", hdlr)
+	writeMP4Box(&mdia, "minf", minf.Bytes())
+
+	var trak bytes.Buffer
+	writeMP4Box(&trak, "tkhd", tkhd)
+	writeMP4Box(&trak, "mdia", mdia.Bytes())
+	return trak.Bytes()
+}
+
+// mp4TableBox builds a full box payload of big-endian uint32 values after version/flags.
+func mp4TableBox(values ...uint32) []byte {
+	out := make([]byte, 4+4*len(values))
+	for i, v := range values {
+		binary.BigEndian.PutUint32(out[4+4*i:], v)
+	}
+	return out
+}
+
+// buildQuickTimeChapterFile builds a movie whose video track references a chapter text track
+// (Intro at 0 s, End at 4 s) and a drop frame tmcd track starting at 01:00:00;00.
+func buildQuickTimeChapterFile() []byte {
+	const dataStart = 16 + 8 // ftyp, then the mdat header
+	var samples bytes.Buffer
+	tcFrame := make([]byte, 4)
+	binary.BigEndian.PutUint32(tcFrame, 107892) // one hour at 29.97 drop frame
+	samples.Write(tcFrame)
+	samples.Write([]byte{0, 5, 'I', 'n', 't', 'r', 'o'})
+	samples.Write([]byte{0, 8, 0xFE, 0xFF, 0, 'E', 0, 'n', 0, 'd'})
+
+	tmcdEntry := make([]byte, 34)
+	binary.BigEndian.PutUint32(tmcdEntry[0:4], uint32(len(tmcdEntry)))
+	copy(tmcdEntry[4:8], "tmcd")
+	binary.BigEndian.PutUint32(tmcdEntry[20:24], mp4TimeCodeDropFrame)
+	binary.BigEndian.PutUint32(tmcdEntry[24:28], 30000)
+	binary.BigEndian.PutUint32(tmcdEntry[28:32], 1001)
+	tmcdEntry[32] = 30
+	tmcdStsd := append(mp4TableBox(1), tmcdEntry...)
+
+	textEntry := make([]byte, 16)
+	binary.BigEndian.PutUint32(textEntry[0:4], uint32(len(textEntry)))
+	copy(textEntry[4:8], "text")
+	textStsd := append(mp4TableBox(1), textEntry...)
+
+	// The codec test video track, prefixed with a tkhd and a tref to the other two.
+	var videoTrak bytes.Buffer
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[12:16], 1)
+	writeMP4Box(&videoTrak, "tkhd", tkhd)
+	var tref bytes.Buffer
+	writeMP4Box(&tref, "tmcd", mp4TableBox(3)[4:])
+	writeMP4Box(&tref, "chap", mp4TableBox(2)[4:])
+	writeMP4Box(&videoTrak, "tref", tref.Bytes())
+	videoTrak.Write(buildTrackWithStsd("vide", "avc1"))
+
+	text := buildQuickTimeTrak(2, "text", 1000, 10000,
+		mp4ChildBox{"stsd", textStsd},
+		mp4ChildBox{"stts", mp4TableBox(2, 1, 4000, 1, 6000)},
+		mp4ChildBox{"stsc", mp4TableBox(1, 1, 2, 1)},
+		mp4ChildBox{"stsz", mp4TableBox(0, 2, 7, 10)},
+		mp4ChildBox{"stco", mp4TableBox(1, dataStart+4)},
+	)
+	timecode := buildQuickTimeTrak(3, "tmcd", 30000, 300300,
+		mp4ChildBox{"stsd", tmcdStsd},
+		mp4ChildBox{"stts", mp4TableBox(1, 1, 300300)},
+		mp4ChildBox{"stsc", mp4TableBox(1, 1, 1, 1)},
+		mp4ChildBox{"stsz", mp4TableBox(4, 1)},
+		mp4ChildBox{"stco", mp4TableBox(1, dataStart)},
+	)
+
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], 10000)
+	var moov bytes.Buffer
+	writeMP4Box(&moov, "mvhd", mvhd)
+	writeMP4Box(&moov, "trak", videoTrak.Bytes())
+	writeMP4Box(&moov, "trak", text)
+	writeMP4Box(&moov, "trak", timecode)
+
+	var file bytes.Buffer
+	writeMP4Box(&file, "ftyp", []byte{'q', 't', ' ', ' ', 0, 0, 0, 0})
+	writeMP4Box(&file, "mdat", samples.Bytes())
+	writeMP4Box(&file, "moov", moov.Bytes())
+	return file.Bytes()
+}
+
+func TestAnalyzeQuickTimeChaptersAndTimeCode(t *testing.T) {
+	data := buildQuickTimeChapterFile()
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "chapters.mov", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	streams := map[StreamKind]Stream{}
+	for _, stream := range report.Streams {
+		streams[stream.Kind] = stream
+	}
+
+	other, ok := streams[StreamOther]
+	if !ok {
+		t.Fatalf("no Other stream in %+v", report.Streams)
+	}
+	for name, want := range map[string]string{
+		"ID":                       "3",
+		"Type":                     "Time code",
+		"Format":                   "QuickTime TC",
+		"Frame rate":               "29.970 (30000/1001) FPS",
+		"Time code of first frame": "01:00:00;00",
+	} {
+		if got := findField(other.Fields, name); got != want {
+			t.Fatalf("Other %s=%q, want %q", name, got, want)
+		}
+	}
+	if findField(other.Fields, "Bit rate") != "" || findField(other.Fields, "Stream size") != "" {
+		t.Fatalf("time code track should not report bit rate or size: %+v", other.Fields)
+	}
+	if got := other.JSON["TimeCode_DropFrame"]; got != "Yes" {
+		t.Fatalf("TimeCode_DropFrame=%q, want Yes", got)
+	}
+	if got := findField(streams[StreamVideo].Fields, "Time code of first frame"); got != "01:00:00;00" {
+		t.Fatalf("video time code=%q", got)
+	}
+	if _, ok := streams[StreamText]; !ok {
+		t.Fatalf("chapter text track should still be listed")
+	}
+
+	menu := streams[StreamMenu]
+	if got := findField(menu.Fields, formatMP4ChapterTimeText(0)); got != "Intro" {
+		t.Fatalf("chapter 1=%q, want Intro (menu %+v)", got, menu.Fields)
+	}
+	if got := findField(menu.Fields, formatMP4ChapterTimeText(4000)); got != "End" {
+		t.Fatalf("chapter 2=%q, want End (menu %+v)", got, menu.Fields)
+	}
+}
+
+func TestFormatMP4TimeCode(t *testing.T) {
+	df := mp4TimeCodeEntry{flags: mp4TimeCodeDropFrame, timescale: 30000, frameDuration: 1001, frames: 30}
+	ndf := mp4TimeCodeEntry{timescale: 24, frameDuration: 1, frames: 24}
+	cases := []struct {
+		frame uint32
+		entry mp4TimeCodeEntry
+		want  string
+	}{
+		{1799, df, "00:00:59;29"},
+		{1800, df, "00:01:00;02"},
+		{17982, df, "00:10:00;00"},
+		{107892, df, "01:00:00;00"},
+		{86400, ndf, "01:00:00:00"},
+		{86399, ndf, "00:59:59:23"},
+	}
+	for _, tc := range cases {
+		if got := formatMP4TimeCode(tc.frame, tc.entry); got != tc.want {
+			t.Fatalf("formatMP4TimeCode(%d)=%q, want %q", tc.frame, got, tc.want)
+		}
+	}
+}
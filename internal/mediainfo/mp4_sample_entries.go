@@ -0,0 +1,601 @@
+package mediainfo
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// mp4SampleEntryBox finds a child box of a sample entry. QuickTime sound descriptions (v1/v2)
+// and some muxers lay the entry header out differently, so fall back to a name search.
+func mp4SampleEntryBox(entry []byte, start int, name string) ([]byte, bool) {
+	if payload, ok := findMP4ChildBox(entry, start, name); ok {
+		return payload, true
+	}
+	return findMP4BoxByName(entry, name)
+}
+
+// parseVisualCodecConfig reports what a video sample entry's codec configuration box (or, for
+// codecs without one, its four-character code) says about profile, level, bit depth and chroma.
+// AVC is handled by parseVisualSampleEntry itself.
+func parseVisualCodecConfig(entry []byte, sampleType string) ([]Field, map[string]string) {
+	fields := []Field{}
+	jsonExtras := map[string]string{}
+	switch mapMP4SampleEntry(sampleType) {
+	case "HEVC":
+		if payload, ok := mp4SampleEntryBox(entry, mp4VisualSampleEntryHeaderSize, "hvcC"); ok {
+			if _, hevcFields, _, _ := parseHEVCConfig(payload); len(hevcFields) > 0 {
+				fields = append(fields, hevcFields...)
+				fields = append(fields, Field{Name: "Codec configuration box", Value: "hvcC"})
+			}
+		}
+		fields = append(fields, Field{Name: "Color space", Value: "YUV"})
+	case "AV1":
+		if payload, ok := mp4SampleEntryBox(entry, mp4VisualSampleEntryHeaderSize, "av1C"); ok {
+			fields = append(fields, parseAV1Config(payload)...)
+			fields = append(fields, Field{Name: "Codec configuration box", Value: "av1C"})
+		}
+	case "VP9":
+		if payload, ok := mp4SampleEntryBox(entry, mp4VisualSampleEntryHeaderSize, "vpcC"); ok {
+			vpFields, vpJSON := parseVPCodecConfig(payload)
+			fields = append(fields, vpFields...)
+			fields = append(fields, Field{Name: "Codec configuration box", Value: "vpcC"})
+			for k, v := range vpJSON {
+				jsonExtras[k] = v
+			}
+		}
+	case "ProRes":
+		if profile, ok := proResProfiles[sampleType]; ok {
+			fields = append(fields,
+				Field{Name: "Format profile", Value: profile.name},
+				Field{Name: "Color space", Value: "YUV"},
+				Field{Name: "Chroma subsampling", Value: profile.chroma},
+				Field{Name: "Bit depth", Value: formatBitDepth(profile.bitDepth)},
+			)
+		}
+		fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	case "VC-3":
+		fields = append(fields, parseAvidDNxConfig(entry)...)
+		fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	case "JPEG":
+		fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	case "YUV":
+		bitDepth := uint8(8)
+		if sampleType == "v210" {
+			bitDepth = 10
+		}
+		fields = append(fields,
+			Field{Name: "Color space", Value: "YUV"},
+			Field{Name: "Chroma subsampling", Value: "4:2:2"},
+			Field{Name: "Bit depth", Value: formatBitDepth(bitDepth)},
+			Field{Name: "Compression mode", Value: "Lossless"},
+		)
+	case "RGB":
+		// The sample entry depth is bits per pixel: 24 for RGB, 32 for RGB with alpha.
+		colorSpace := "RGB"
+		depth := uint16(0)
+		if len(entry) >= 84 {
+			depth = binary.BigEndian.Uint16(entry[82:84])
+		}
+		if depth == 32 {
+			colorSpace = "RGBA"
+		}
+		fields = append(fields, Field{Name: "Color space", Value: colorSpace})
+		if depth == 24 || depth == 32 {
+			fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(8)})
+		}
+		fields = append(fields, Field{Name: "Compression mode", Value: "Lossless"})
+	}
+	if len(jsonExtras) == 0 {
+		jsonExtras = nil
+	}
+	return fields, jsonExtras
+}
+
+// appendDolbyVisionSampleEntry adds the HDR format described by a dvcC/dvvC/dvwC box, which
+// Dolby Vision sample entries (and backward-compatible hvc1/avc1/av01 ones) carry next to
+// the base layer configuration.
+func appendDolbyVisionSampleEntry(fields []Field, entry []byte) []Field {
+	for _, tag := range []string{"dvcC", "dvvC", "dvwC"} {
+		payload, ok := mp4SampleEntryBox(entry, mp4VisualSampleEntryHeaderSize, tag)
+		if !ok {
+			continue
+		}
+		cfg, ok := parseDolbyVisionConfig(payload)
+		if !ok {
+			continue
+		}
+		fields = append(fields, Field{Name: "HDR format", Value: formatDolbyVisionHDR(cfg)})
+		if box := findField(fields, "Codec configuration box"); box != "" {
+			fields = setFieldValue(fields, "Codec configuration box", box+"+"+tag)
+		} else {
+			fields = append(fields, Field{Name: "Codec configuration box", Value: tag})
+		}
+		return fields
+	}
+	return fields
+}
+
+func parseAV1Config(payload []byte) []Field {
+	// av1C: marker/version, seq_profile(3) seq_level_idx_0(5), then tier, depth and chroma flags.
+	if len(payload) < 4 || payload[0]&0x80 == 0 {
+		return nil
+	}
+	profile := payload[1] >> 5
+	levelIdx := payload[1] & 0x1F
+	flags := payload[2]
+	highTier := flags&0x80 != 0
+	highBitDepth := flags&0x40 != 0
+	twelveBit := flags&0x20 != 0
+	monochrome := flags&0x10 != 0
+	subX := flags&0x08 != 0
+	subY := flags&0x04 != 0
+	position := flags & 0x03
+
+	fields := []Field{}
+	if name := av1ProfileName(profile); name != "" {
+		// seq_level_idx 31 means no level constraints.
+		if levelIdx < 31 {
+			name = fmt.Sprintf("%s@L%d.%d", name, 2+levelIdx>>2, levelIdx&0x03)
+		}
+		fields = append(fields, Field{Name: "Format profile", Value: name})
+	}
+	if highTier {
+		fields = append(fields, Field{Name: "Format tier", Value: "High"})
+	}
+	chroma := "4:4:4"
+	switch {
+	case monochrome:
+		chroma = "4:0:0"
+	case subX && subY:
+		chroma = "4:2:0"
+	case subX:
+		chroma = "4:2:2"
+	}
+	colorSpace := "YUV"
+	if monochrome {
+		colorSpace = "Y"
+	}
+	fields = append(fields,
+		Field{Name: "Color space", Value: colorSpace},
+		Field{Name: "Chroma subsampling", Value: chroma},
+	)
+	if chroma == "4:2:0" {
+		switch position {
+		case 1:
+			fields = append(fields, Field{Name: "Chroma subsampling position", Value: "Type 0"})
+		case 2:
+			fields = append(fields, Field{Name: "Chroma subsampling position", Value: "Type 2"})
+		}
+	}
+	bitDepth := uint8(8)
+	if highBitDepth {
+		bitDepth = 10
+		if twelveBit {
+			bitDepth = 12
+		}
+	}
+	fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(bitDepth)})
+	return fields
+}
+
+func av1ProfileName(profile byte) string {
+	switch profile {
+	case 0:
+		return "Main"
+	case 1:
+		return "High"
+	case 2:
+		return "Professional"
+	default:
+		return ""
+	}
+}
+
+func parseVPCodecConfig(payload []byte) ([]Field, map[string]string) {
+	// vpcC is a FullBox; version 1 is the published layout, version 0 an early draft that
+	// shares only profile, level and bit depth with it.
+	if len(payload) < 7 {
+		return nil, nil
+	}
+	version := payload[0]
+	p := payload[4:]
+	profile := p[0]
+	level := p[1]
+	bitDepth := p[2] >> 4
+
+	fields := []Field{}
+	name := strconv.Itoa(int(profile))
+	if level > 0 {
+		name += "@L" + strconv.Itoa(int(level/10))
+		if level%10 != 0 {
+			name += "." + strconv.Itoa(int(level%10))
+		}
+	}
+	fields = append(fields, Field{Name: "Format profile", Value: name})
+	if version != 1 || len(p) < 6 {
+		if bitDepth > 0 {
+			fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(bitDepth)})
+		}
+		return fields, nil
+	}
+
+	chroma := ""
+	switch (p[2] >> 1) & 0x07 {
+	case 0, 1:
+		chroma = "4:2:0"
+	case 2:
+		chroma = "4:2:2"
+	case 3:
+		chroma = "4:4:4"
+	}
+	fields = append(fields, Field{Name: "Color space", Value: "YUV"})
+	if chroma != "" {
+		fields = append(fields, Field{Name: "Chroma subsampling", Value: chroma})
+	}
+	if bitDepth > 0 {
+		fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(bitDepth)})
+	}
+
+	colorRange := "Limited"
+	if p[2]&0x01 != 0 {
+		colorRange = "Full"
+	}
+	primaries := matroskaColorPrimariesName(uint64(p[3]))
+	transfer := matroskaTransferName(uint64(p[4]))
+	matrix := matroskaMatrixName(uint64(p[5]))
+	fields = append(fields, Field{Name: "Color range", Value: colorRange})
+	if primaries != "" {
+		fields = append(fields, Field{Name: "Color primaries", Value: primaries})
+	}
+	if transfer != "" {
+		fields = append(fields, Field{Name: "Transfer characteristics", Value: transfer})
+	}
+	if matrix != "" {
+		fields = append(fields, Field{Name: "Matrix coefficients", Value: matrix})
+	}
+
+	colorSource := "Container"
+	jsonExtras := map[string]string{
+		"colour_description_present":        "Yes",
+		"colour_description_present_Source": colorSource,
+		"colour_range":                      colorRange,
+		"colour_range_Source":               colorSource,
+	}
+	if primaries != "" {
+		jsonExtras["colour_primaries"] = primaries
+		jsonExtras["colour_primaries_Source"] = colorSource
+	}
+	if transfer != "" {
+		jsonExtras["transfer_characteristics"] = transfer
+		jsonExtras["transfer_characteristics_Source"] = colorSource
+	}
+	if matrix != "" {
+		jsonExtras["matrix_coefficients"] = matrix
+		jsonExtras["matrix_coefficients_Source"] = colorSource
+	}
+	return fields, jsonExtras
+}
+
+type proResProfile struct {
+	name     string
+	chroma   string
+	bitDepth uint8
+}
+
+// ProRes has no configuration box; the four-character code is the profile.
+var proResProfiles = map[string]proResProfile{
+	"apco": {name: "422 Proxy", chroma: "4:2:2", bitDepth: 10},
+	"apcs": {name: "422 LT", chroma: "4:2:2", bitDepth: 10},
+	"apcn": {name: "422", chroma: "4:2:2", bitDepth: 10},
+	"apch": {name: "422 HQ", chroma: "4:2:2", bitDepth: 10},
+	"ap4h": {name: "4444", chroma: "4:4:4", bitDepth: 12},
+	"ap4x": {name: "4444 XQ", chroma: "4:4:4", bitDepth: 12},
+}
+
+// parseAvidDNxConfig reads the compression ID from the ARES box (and the range from ACLR) that
+// Avid and ffmpeg write into AVdn sample entries.
+func parseAvidDNxConfig(entry []byte) []Field {
+	fields := []Field{}
+	if payload, ok := mp4SampleEntryBox(entry, mp4VisualSampleEntryHeaderSize, "ARES"); ok && len(payload) >= 12 {
+		cid := binary.BigEndian.Uint32(payload[8:12])
+		commercial, profile := "DNxHD", ""
+		if cid >= 1270 {
+			commercial = "DNxHR"
+			profile = map[uint32]string{1270: "444", 1271: "HQX", 1272: "HQ", 1273: "SQ", 1274: "LB"}[cid]
+		}
+		fields = append(fields, Field{Name: "Commercial name", Value: commercial})
+		if profile != "" {
+			fields = append(fields, Field{Name: "Format profile", Value: profile})
+		}
+		chroma := "4:2:2"
+		colorSpace := "YUV"
+		switch cid {
+		case 1256:
+			chroma, colorSpace = "4:4:4", "RGB"
+		case 1270:
+			// DNxHR 444 may be RGB or YUV; the frame header says which.
+			chroma, colorSpace = "4:4:4", ""
+		}
+		if colorSpace != "" {
+			fields = append(fields, Field{Name: "Color space", Value: colorSpace})
+		}
+		fields = append(fields, Field{Name: "Chroma subsampling", Value: chroma})
+		switch cid {
+		case 1235, 1241, 1250, 1256:
+			fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(10)})
+		case 1270, 1271:
+			// 10 or 12 bits, signalled only in the frame header.
+		default:
+			fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(8)})
+		}
+	}
+	if payload, ok := mp4SampleEntryBox(entry, mp4VisualSampleEntryHeaderSize, "ACLR"); ok && len(payload) >= 12 {
+		switch binary.BigEndian.Uint32(payload[8:12]) {
+		case 1:
+			fields = append(fields, Field{Name: "Color range", Value: "Full"})
+		case 2:
+			fields = append(fields, Field{Name: "Color range", Value: "Limited"})
+		}
+	}
+	return fields
+}
+
+// mp4PCMFormat describes the sample layout of an uncompressed audio sample entry.
+type mp4PCMFormat struct {
+	float      bool
+	endianness string
+	sign       string
+	bitDepth   int
+}
+
+// parseMP4PCMFormat derives the PCM layout from the four-character code, the QuickTime sound
+// description, and the pcmC (ISO 23003-5) or enda (QuickTime) boxes.
+func parseMP4PCMFormat(entry []byte, sampleType string) mp4PCMFormat {
+	pcm := mp4PCMFormat{endianness: "Big", sign: "Signed"}
+	if len(entry) >= 28 {
+		pcm.bitDepth = int(binary.BigEndian.Uint16(entry[26:28]))
+	}
+	switch sampleType {
+	case "sowt":
+		pcm.endianness = "Little"
+	case "in24":
+		pcm.bitDepth = 24
+	case "in32":
+		pcm.bitDepth = 32
+	case "fl32":
+		pcm.float, pcm.bitDepth = true, 32
+	case "fl64":
+		pcm.float, pcm.bitDepth = true, 64
+	case "lpcm":
+		// Version 2 sound description: constBitsPerChannel and formatSpecificFlags.
+		if len(entry) >= 64 && binary.BigEndian.Uint16(entry[16:18]) == 2 {
+			pcm.bitDepth = int(binary.BigEndian.Uint32(entry[56:60]))
+			flags := binary.BigEndian.Uint32(entry[60:64])
+			pcm.float = flags&0x01 != 0
+			if flags&0x02 == 0 {
+				pcm.endianness = "Little"
+			}
+			if flags&0x04 == 0 {
+				pcm.sign = "Unsigned"
+			}
+		}
+	case "ipcm":
+		if payload, ok := mp4SampleEntryBox(entry, mp4AudioSampleEntryHeaderSize, "pcmC"); ok && len(payload) >= 6 {
+			if payload[4]&0x01 != 0 {
+				pcm.endianness = "Little"
+			}
+			pcm.bitDepth = int(payload[5])
+		}
+	}
+	if sampleType == "in24" || sampleType == "in32" || sampleType == "fl32" || sampleType == "fl64" {
+		if payload, ok := mp4SampleEntryBox(entry, mp4AudioSampleEntryHeaderSize, "enda"); ok && len(payload) >= 2 && binary.BigEndian.Uint16(payload[0:2]) == 1 {
+			pcm.endianness = "Little"
+		}
+	}
+	if pcm.float {
+		pcm.sign = ""
+	}
+	return pcm
+}
+
+// parseAudioCodecConfig reports what an audio sample entry's configuration box (or PCM layout)
+// says about the stream. AAC, AC-3 and E-AC-3 are handled by parseAudioSampleEntry itself.
+func parseAudioCodecConfig(entry []byte, sampleType string) ([]Field, map[string]string) {
+	fields := []Field{}
+	jsonExtras := map[string]string{}
+	switch mapMP4SampleEntry(sampleType) {
+	case "PCM":
+		pcm := parseMP4PCMFormat(entry, sampleType)
+		if pcm.float {
+			fields = append(fields, Field{Name: "Format profile", Value: "Float"})
+		}
+		if pcm.bitDepth > 8 {
+			fields = append(fields, Field{Name: "Format settings, Endianness", Value: pcm.endianness})
+		}
+		if pcm.sign != "" {
+			fields = append(fields, Field{Name: "Format settings, Sign", Value: pcm.sign})
+		}
+		if pcm.bitDepth > 0 && pcm.bitDepth <= math.MaxUint8 {
+			fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(uint8(pcm.bitDepth))})
+		}
+		fields = append(fields,
+			Field{Name: "Bit rate mode", Value: "Constant"},
+			Field{Name: "Compression mode", Value: "Lossless"},
+		)
+	case "AC-4":
+		if payload, ok := mp4SampleEntryBox(entry, mp4AudioSampleEntryHeaderSize, "dac4"); ok {
+			fields = append(fields, parseAC4Config(payload)...)
+		}
+		fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	case "MPEG-H 3D Audio":
+		// mhm1 may carry its configuration in-band only.
+		if payload, ok := mp4SampleEntryBox(entry, mp4AudioSampleEntryHeaderSize, "mhaC"); ok && len(payload) >= 2 {
+			if profile := mpegHProfileLevel(payload[1]); profile != "" {
+				fields = append(fields, Field{Name: "Format profile", Value: profile})
+			}
+		}
+		fields = append(fields, Field{Name: "Compression mode", Value: "Lossy"})
+	case "DTS":
+		dtsFields, dtsJSON := parseDTSSampleEntry(entry, sampleType)
+		fields = append(fields, dtsFields...)
+		for k, v := range dtsJSON {
+			jsonExtras[k] = v
+		}
+	}
+	if len(jsonExtras) == 0 {
+		jsonExtras = nil
+	}
+	return fields, jsonExtras
+}
+
+// ac4FrameRates48k is the AC-4 frame_rate_index table for 48 kHz family streams.
+var ac4FrameRates48k = []float64{
+	24000.0 / 1001, 24, 25, 30000.0 / 1001, 30, 48000.0 / 1001, 48, 50, 60000.0 / 1001, 60, 100, 120000.0 / 1001, 120, 48000.0 / 2048,
+}
+
+func parseAC4Config(payload []byte) []Field {
+	// ac4_dsi_v1 (ETSI TS 103 190-2 E.6).
+	br := newBitReader(payload)
+	dsiVersion := br.readBitsValue(3)
+	bitstreamVersion := br.readBitsValue(7)
+	fsIndex := br.readBitsValue(1)
+	frameRateIndex := br.readBitsValue(4)
+	presentations := br.readBitsValue(9)
+	if presentations == ^uint64(0) || dsiVersion != 1 {
+		return nil
+	}
+	fields := []Field{{Name: "Format version", Value: fmt.Sprintf("Version %d", bitstreamVersion)}}
+	if fsIndex == 1 && frameRateIndex < uint64(len(ac4FrameRates48k)) {
+		fields = append(fields, Field{Name: "Frame rate", Value: fmt.Sprintf("%.3f FPS", ac4FrameRates48k[frameRateIndex])})
+	} else if fsIndex == 0 && frameRateIndex == 13 {
+		fields = append(fields, Field{Name: "Frame rate", Value: fmt.Sprintf("%.3f FPS", 44100.0/2048)})
+	}
+
+	if bitstreamVersion > 1 && br.readBitsValue(1) == 1 {
+		br.readBitsValue(16) // short_program_id
+		if br.readBitsValue(1) == 1 {
+			for range 8 {
+				br.readBitsValue(16) // program_uuid
+			}
+		}
+	}
+	bitRateMode := br.readBitsValue(2)
+	bitRate := br.readBitsValue(32)
+	br.readBitsValue(32) // bit_rate_precision
+	if bitRate != ^uint64(0) && bitRate > 0 {
+		switch bitRateMode {
+		case 1:
+			fields = append(fields, Field{Name: "Bit rate mode", Value: "Constant"})
+		case 2, 3:
+			fields = append(fields, Field{Name: "Bit rate mode", Value: "Variable"})
+		}
+		fields = append(fields, Field{Name: "Bit rate", Value: formatBitrate(float64(bitRate))})
+	}
+	if br.bit != 0 {
+		br.bit = 0
+		br.pos++
+	}
+
+	// The first presentation's mdcompat is the decoder compatibility level it needs.
+	if presentations > 0 {
+		presentationVersion := br.readBitsValue(8)
+		if presBytes := br.readBitsValue(8); presBytes == 255 {
+			br.readBitsValue(16)
+		}
+		if presentationVersion <= 2 {
+			if config := br.readBitsValue(5); config != 0x06 && config != ^uint64(0) {
+				if mdcompat := br.readBitsValue(3); mdcompat != ^uint64(0) {
+					fields = append(fields, Field{Name: "Format level", Value: strconv.FormatUint(mdcompat, 10)})
+				}
+			}
+		}
+	}
+	return fields
+}
+
+func mpegHProfileLevel(indication byte) string {
+	profiles := []string{"Main", "High", "Low Complexity", "Baseline"}
+	if indication == 0 || indication > 0x14 {
+		return ""
+	}
+	idx := int(indication - 1)
+	return fmt.Sprintf("%s@L%d", profiles[idx/5], idx%5+1)
+}
+
+// dtsSampleEntries maps the DTS sample entry types to the profile and commercial name they imply.
+var dtsSampleEntries = map[string]struct {
+	profile    string
+	commercial string
+	lossless   bool
+}{
+	"dtsc": {},
+	"dtsh": {commercial: "DTS-HD"},
+	"dtsl": {profile: "MA", commercial: "DTS-HD Master Audio", lossless: true},
+	"dtse": {profile: "Express", commercial: "DTS Express"},
+	"dtsx": {profile: "X", commercial: "DTS:X"},
+}
+
+func parseDTSSampleEntry(entry []byte, sampleType string) ([]Field, map[string]string) {
+	info := dtsSampleEntries[sampleType]
+	fields := []Field{}
+	if info.profile != "" {
+		fields = append(fields, Field{Name: "Format profile", Value: info.profile})
+	}
+	if info.commercial != "" {
+		fields = append(fields, Field{Name: "Commercial name", Value: info.commercial})
+	}
+	mode := "Lossy"
+	if info.lossless {
+		mode = "Lossless"
+	}
+	fields = append(fields, Field{Name: "Compression mode", Value: mode})
+
+	// ddts: sampling frequency, max and average bit rate, PCM sample depth.
+	payload, ok := mp4SampleEntryBox(entry, mp4AudioSampleEntryHeaderSize, "ddts")
+	if !ok || len(payload) < 13 {
+		return fields, nil
+	}
+	jsonExtras := map[string]string{}
+	if rate := binary.BigEndian.Uint32(payload[0:4]); rate > 0 {
+		fields = append(fields, Field{Name: "Sampling rate", Value: formatSampleRate(float64(rate))})
+	}
+	maxRate := binary.BigEndian.Uint32(payload[4:8])
+	avgRate := binary.BigEndian.Uint32(payload[8:12])
+	if avgRate > 0 {
+		mode := "Variable"
+		if maxRate == avgRate {
+			mode = "Constant"
+		}
+		fields = append(fields,
+			Field{Name: "Bit rate mode", Value: mode},
+			Field{Name: "Bit rate", Value: formatBitrate(float64(avgRate))},
+		)
+		jsonExtras["BitRate"] = strconv.FormatUint(uint64(avgRate), 10)
+		if maxRate > avgRate {
+			fields = append(fields, Field{Name: "Maximum bit rate", Value: formatBitrate(float64(maxRate))})
+			jsonExtras["BitRate_Maximum"] = strconv.FormatUint(uint64(maxRate), 10)
+		}
+	}
+	if depth := payload[12]; depth > 0 {
+		fields = append(fields, Field{Name: "Bit depth", Value: formatBitDepth(depth)})
+	}
+	return fields, jsonExtras
+}
+
+// mp4AudioSampleEntryLayout returns the channel count and sample rate of an audio sample entry,
+// reading QuickTime version 2 sound descriptions (lpcm and friends) from their extended fields.
+func mp4AudioSampleEntryLayout(entry []byte) (uint64, float64) {
+	if len(entry) < 36 {
+		return 0, 0
+	}
+	if binary.BigEndian.Uint16(entry[16:18]) == 2 && len(entry) >= 52 {
+		rate := math.Float64frombits(binary.BigEndian.Uint64(entry[40:48]))
+		if math.IsNaN(rate) || math.IsInf(rate, 0) || rate < 0 {
+			rate = 0
+		}
+		return uint64(binary.BigEndian.Uint32(entry[48:52])), rate
+	}
+	return uint64(binary.BigEndian.Uint16(entry[24:26])), float64(binary.BigEndian.Uint32(entry[32:36])) / 65536
+}
@@ -0,0 +1,309 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+type mp4ChildBox struct {
+	typ     string
+	payload []byte
+}
+
+// buildSampleEntryStsd wraps a sample entry (fixed header plus child boxes) in an stsd payload.
+func buildSampleEntryStsd(sampleType string, header []byte, children ...mp4ChildBox) []byte {
+	var entry bytes.Buffer
+	entry.Write(header)
+	for _, child := range children {
+		writeMP4Box(&entry, child.typ, child.payload)
+	}
+	raw := entry.Bytes()
+	binary.BigEndian.PutUint32(raw[0:4], uint32(len(raw)))
+	copy(raw[4:8], sampleType)
+
+	stsd := make([]byte, 8, 8+len(raw))
+	binary.BigEndian.PutUint32(stsd[4:8], 1)
+	return append(stsd, raw...)
+}
+
+func visualSampleEntryHeader(width, height uint16) []byte {
+	header := make([]byte, 86)
+	binary.BigEndian.PutUint16(header[32:34], width)
+	binary.BigEndian.PutUint16(header[34:36], height)
+	binary.BigEndian.PutUint16(header[82:84], 24)
+	return header
+}
+
+func audioSampleEntryHeader(channels, bits uint16, rate uint32) []byte {
+	header := make([]byte, 36)
+	binary.BigEndian.PutUint16(header[24:26], channels)
+	binary.BigEndian.PutUint16(header[26:28], bits)
+	binary.BigEndian.PutUint32(header[32:36], rate<<16)
+	return header
+}
+
+// packBits packs (value, width) pairs MSB first, padding the last byte with zeros.
+func packBits(pairs ...uint64) []byte {
+	var out []byte
+	var acc uint64
+	var n uint
+	for i := 0; i+1 < len(pairs); i += 2 {
+		for bit := int(pairs[i+1]) - 1; bit >= 0; bit-- {
+			acc = acc<<1 | (pairs[i]>>uint(bit))&1
+			n++
+			if n == 8 {
+				out = append(out, byte(acc))
+				acc, n = 0, 0
+			}
+		}
+	}
+	if n > 0 {
+		out = append(out, byte(acc<<(8-n)))
+	}
+	return out
+}
+
+func TestParseStsdVideoSampleEntries(t *testing.T) {
+	hvcC := make([]byte, 23)
+	hvcC[1] = 2    // Main 10
+	hvcC[12] = 153 // level 5.1
+	hvcC[16] = 0xFD
+	hvcC[17] = 0xFA // 10-bit luma
+	hvcC[21] = 0x03
+	// dvcC 1.0: profile 5, level 6, RPU + BL, no compatible base layer.
+	dvcC := append([]byte{1, 0}, packBits(5, 7, 6, 6, 1, 1, 0, 1, 1, 1, 0, 4, 0, 28)...)
+	aresCID := func(cid uint32) []byte {
+		payload := make([]byte, 24)
+		copy(payload[0:8], "ARES0001")
+		binary.BigEndian.PutUint32(payload[8:12], cid)
+		return payload
+	}
+
+	cases := []struct {
+		name     string
+		sample   string
+		children []mp4ChildBox
+		format   string
+		want     map[string]string
+	}{
+		{
+			name:     "av1",
+			sample:   "av01",
+			children: []mp4ChildBox{{"av1C", []byte{0x81, 0x08, 0x4C, 0x00}}},
+			format:   "AV1",
+			want: map[string]string{
+				"Format/Info":             "AOMedia Video 1",
+				"Format profile":          "Main@L4.0",
+				"Chroma subsampling":      "4:2:0",
+				"Bit depth":               "10 bits",
+				"Codec configuration box": "av1C",
+			},
+		},
+		{
+			name:     "vp9",
+			sample:   "vp09",
+			children: []mp4ChildBox{{"vpcC", []byte{1, 0, 0, 0, 2, 31, 0xA4, 9, 16, 9, 0, 0}}},
+			format:   "VP9",
+			want: map[string]string{
+				"Format profile":           "2@L3.1",
+				"Chroma subsampling":       "4:2:2",
+				"Bit depth":                "10 bits",
+				"Color range":              "Limited",
+				"Color primaries":          "BT.2020",
+				"Transfer characteristics": "PQ",
+				"Matrix coefficients":      "BT.2020 non-constant",
+			},
+		},
+		{
+			name:     "dolby vision hevc",
+			sample:   "dvh1",
+			children: []mp4ChildBox{{"hvcC", hvcC}, {"dvcC", dvcC}},
+			format:   "HEVC",
+			want: map[string]string{
+				"Format profile":          "Main 10@L5.1",
+				"Chroma subsampling":      "4:2:0",
+				"Bit depth":               "10 bits",
+				"HDR format":              "Dolby Vision, Version 1.0, Profile 5, dvhe.05.06, BL+RPU, no metadata compression",
+				"Codec configuration box": "hvcC+dvcC",
+			},
+		},
+		{
+			name:   "prores",
+			sample: "apch",
+			format: "ProRes",
+			want: map[string]string{
+				"Format profile":     "422 HQ",
+				"Chroma subsampling": "4:2:2",
+				"Bit depth":          "10 bits",
+			},
+		},
+		{
+			name:     "dnxhr",
+			sample:   "AVdn",
+			children: []mp4ChildBox{{"ARES", aresCID(1272)}},
+			format:   "VC-3",
+			want: map[string]string{
+				"Commercial name":    "DNxHR",
+				"Format profile":     "HQ",
+				"Chroma subsampling": "4:2:2",
+				"Bit depth":          "8 bits",
+			},
+		},
+		{
+			name:   "v210",
+			sample: "v210",
+			format: "YUV",
+			want: map[string]string{
+				"Chroma subsampling": "4:2:2",
+				"Bit depth":          "10 bits",
+				"Compression mode":   "Lossless",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			stsd := buildSampleEntryStsd(tc.sample, visualSampleEntryHeader(1920, 1080), tc.children...)
+			info, ok := parseStsdForSample(stsd)
+			if !ok {
+				t.Fatalf("parseStsdForSample failed")
+			}
+			if info.Format != tc.format {
+				t.Fatalf("Format=%q, want %q", info.Format, tc.format)
+			}
+			if info.Width != 1920 || info.Height != 1080 {
+				t.Fatalf("size=%dx%d", info.Width, info.Height)
+			}
+			if got := findField(info.Fields, "Codec ID"); got != tc.sample {
+				t.Fatalf("Codec ID=%q, want %q", got, tc.sample)
+			}
+			for name, want := range tc.want {
+				if got := findField(info.Fields, name); got != want {
+					t.Fatalf("%s=%q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseStsdAudioSampleEntries(t *testing.T) {
+	// ac4_dsi_v1: bitstream version 2, 48 kHz at 25 fps, one presentation at 64 kb/s CBR,
+	// then a v1 presentation with mdcompat 3.
+	dac4 := packBits(1, 3, 2, 7, 1, 1, 2, 4, 1, 9, 0, 1, 1, 2, 64000, 32, 0, 32, 0, 5, 1, 8, 2, 8, 0, 5, 3, 3, 0, 8)
+	ddts := make([]byte, 20)
+	binary.BigEndian.PutUint32(ddts[0:4], 48000)
+	binary.BigEndian.PutUint32(ddts[4:8], 3000000)
+	binary.BigEndian.PutUint32(ddts[8:12], 1509000)
+	ddts[12] = 24
+
+	// QuickTime v2 sound description for 6 channels of 24-bit little-endian signed lpcm.
+	lpcm := make([]byte, 72)
+	binary.BigEndian.PutUint16(lpcm[16:18], 2)
+	binary.BigEndian.PutUint16(lpcm[24:26], 3)
+	binary.BigEndian.PutUint32(lpcm[32:36], 0x00010000)
+	binary.BigEndian.PutUint64(lpcm[40:48], math.Float64bits(48000))
+	binary.BigEndian.PutUint32(lpcm[48:52], 6)
+	binary.BigEndian.PutUint32(lpcm[56:60], 24)
+	binary.BigEndian.PutUint32(lpcm[60:64], 0x04|0x08)
+
+	cases := []struct {
+		name     string
+		sample   string
+		header   []byte
+		children []mp4ChildBox
+		format   string
+		want     map[string]string
+	}{
+		{
+			name:     "ac4",
+			sample:   "ac-4",
+			header:   audioSampleEntryHeader(2, 16, 48000),
+			children: []mp4ChildBox{{"dac4", dac4}},
+			format:   "AC-4",
+			want: map[string]string{
+				"Format/Info":    "Audio Coding 4",
+				"Format version": "Version 2",
+				"Format level":   "3",
+				"Frame rate":     "25.000 FPS",
+				"Bit rate mode":  "Constant",
+				"Bit rate":       "64 kb/s",
+			},
+		},
+		{
+			name:   "sowt",
+			sample: "sowt",
+			header: audioSampleEntryHeader(2, 16, 44100),
+			format: "PCM",
+			want: map[string]string{
+				"Format settings, Endianness": "Little",
+				"Format settings, Sign":       "Signed",
+				"Bit depth":                   "16 bits",
+			},
+		},
+		{
+			name:   "lpcm",
+			sample: "lpcm",
+			header: lpcm,
+			format: "PCM",
+			want: map[string]string{
+				"Channel(s)":                  "6 channels",
+				"Sampling rate":               "48.0 kHz",
+				"Bit depth":                   "24 bits",
+				"Format settings, Endianness": "Little",
+				"Format settings, Sign":       "Signed",
+			},
+		},
+		{
+			name:     "ipcm",
+			sample:   "ipcm",
+			header:   audioSampleEntryHeader(2, 16, 96000),
+			children: []mp4ChildBox{{"pcmC", []byte{0, 0, 0, 0, 1, 32}}},
+			format:   "PCM",
+			want: map[string]string{
+				"Bit depth":                   "32 bits",
+				"Format settings, Endianness": "Little",
+			},
+		},
+		{
+			name:     "dts-hd ma",
+			sample:   "dtsl",
+			header:   audioSampleEntryHeader(6, 24, 48000),
+			children: []mp4ChildBox{{"ddts", ddts}},
+			format:   "DTS",
+			want: map[string]string{
+				"Format profile":   "MA",
+				"Commercial name":  "DTS-HD Master Audio",
+				"Compression mode": "Lossless",
+				"Bit depth":        "24 bits",
+				"Bit rate mode":    "Variable",
+			},
+		},
+		{
+			name:     "mpeg-h",
+			sample:   "mha1",
+			header:   audioSampleEntryHeader(2, 16, 48000),
+			children: []mp4ChildBox{{"mhaC", []byte{1, 0x0D, 6, 0, 0}}},
+			format:   "MPEG-H 3D Audio",
+			want: map[string]string{
+				"Format profile": "Low Complexity@L3",
+			},
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			info, ok := parseStsdForSample(buildSampleEntryStsd(tc.sample, tc.header, tc.children...))
+			if !ok {
+				t.Fatalf("parseStsdForSample failed")
+			}
+			if info.Format != tc.format {
+				t.Fatalf("Format=%q, want %q", info.Format, tc.format)
+			}
+			for name, want := range tc.want {
+				if got := findField(info.Fields, name); got != want {
+					t.Fatalf("%s=%q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,12 @@
 package mediainfo
 
-import "encoding/binary"
+import (
+	"bytes"
+	"encoding/binary"
+	"slices"
+	"strconv"
+	"strings"
+)
 
 func parseMP4WritingApp(udta []byte) string {
 	meta, ok := findMP4Box(udta, "meta")
@@ -59,3 +65,285 @@ func findMP4Box(buf []byte, boxType string) ([]byte, bool) {
 	}
 	return nil, false
 }
+
+// mp4IlstKeys maps iTunes-style ilst text atoms onto General fields.
+var mp4IlstKeys = map[string]string{
+	"\xa9nam": "Title",
+	"\xa9ART": "Performer",
+	"aART":    "Album_Performer",
+	"\xa9alb": "Album",
+	"\xa9day": "Recorded_Date",
+	"\xa9gen": "Genre",
+	"\xa9wrt": "Composer",
+	"\xa9cmt": "Comment",
+	"\xa9lyr": "Lyrics",
+	"\xa9grp": "Grouping",
+	"cprt":    "Copyright",
+	"tvsh":    "Collection",
+	"tvnn":    "ServiceName",
+}
+
+// mp4TagFields gives the text label of each General field the ilst tags fill, in display order.
+var mp4TagFields = []struct{ key, label string }{
+	{"Title", "Title"},
+	{"Collection", "Collection"},
+	{"Album", "Album"},
+	{"Album_Performer", "Album/Performer"},
+	{"Part_Position", "Part/Position"},
+	{"Part_Position_Total", "Part/Total"},
+	{"Track_Position", "Track name/Position"},
+	{"Track_Position_Total", "Track name/Total"},
+	{"Grouping", "Grouping"},
+	{"Performer", "Performer"},
+	{"Composer", "Composer"},
+	{"ServiceName", "Service name"},
+	{"Genre", "Genre"},
+	{"ContentType", "Content type"},
+	{"LawRating", "Law rating"},
+	{"Recorded_Date", "Recorded date"},
+	{"Copyright", "Copyright"},
+	{"Cover", "Cover"},
+	{"Cover_Mime", "Cover MIME"},
+	{"Lyrics", "Lyrics"},
+	{"Comment", "Comment"},
+}
+
+// mp4TagGeneralFields returns the ilst tags as General fields.
+func mp4TagGeneralFields(tags map[string]string) []Field {
+	var fields []Field
+	for _, tag := range mp4TagFields {
+		if value := tags[tag.key]; value != "" {
+			fields = append(fields, Field{Name: tag.label, Value: value})
+		}
+	}
+	return fields
+}
+
+// mp4IlstExtraKeys are ilst text atoms without a standard General field; they go to extra.
+var mp4IlstExtraKeys = map[string]string{
+	"ldes": "LongDescription",
+	"tven": "EpisodeID",
+	"sonm": "SortTitle",
+	"soar": "SortPerformer",
+	"soal": "SortAlbum",
+}
+
+// mp4StikNames are the media kinds of the stik atom.
+var mp4StikNames = map[uint64]string{
+	0:  "Movie",
+	1:  "Normal",
+	2:  "Audiobook",
+	5:  "Whacked Bookmark",
+	6:  "Music Video",
+	9:  "Movie",
+	10: "TV Show",
+	11: "Booklet",
+	14: "Ringtone",
+	21: "Podcast",
+	23: "iTunes U",
+}
+
+type mp4IlstValue struct {
+	dataType uint32
+	data     []byte
+}
+
+// parseMP4ItunesTags maps the ilst atoms of a meta box onto General JSON fields, returning the
+// fields MediaInfo has no name for separately so they can go to extra.
+func parseMP4ItunesTags(meta []byte) (map[string]string, []jsonKV) {
+	// ISO meta is a FullBox; the QuickTime flavour starts directly with its hdlr.
+	if len(meta) >= 8 && string(meta[4:8]) != "hdlr" {
+		meta = meta[4:]
+	}
+	ilst, ok := findMP4Box(meta, "ilst")
+	if !ok {
+		return nil, nil
+	}
+
+	tags := map[string]string{}
+	var extra []jsonKV
+	var covers []string
+	for pos := 0; pos+8 <= len(ilst); {
+		size := int(binary.BigEndian.Uint32(ilst[pos : pos+4]))
+		if size < 8 || pos+size > len(ilst) {
+			break
+		}
+		typ := string(ilst[pos+4 : pos+8])
+		item := ilst[pos+8 : pos+size]
+		pos += size
+
+		values := mp4IlstValues(item)
+		if len(values) == 0 {
+			continue
+		}
+		first := values[0]
+		switch typ {
+		case "trkn", "disk":
+			// Binary: reserved(16), position(16), total(16).
+			if len(first.data) < 6 {
+				continue
+			}
+			key := "Track_Position"
+			if typ == "disk" {
+				key = "Part_Position"
+			}
+			if position := binary.BigEndian.Uint16(first.data[2:4]); position > 0 {
+				tags[key] = strconv.Itoa(int(position))
+			}
+			if total := binary.BigEndian.Uint16(first.data[4:6]); total > 0 {
+				tags[key+"_Total"] = strconv.Itoa(int(total))
+			}
+		case "stik":
+			if kind, ok := mp4IlstInt(first); ok {
+				if name := mp4StikNames[kind]; name != "" {
+					tags["ContentType"] = name
+				}
+			}
+		case "rtng":
+			if rating, ok := mp4IlstInt(first); ok {
+				switch rating {
+				case 1, 4:
+					tags["LawRating"] = "Explicit"
+				case 2:
+					tags["LawRating"] = "Clean"
+				}
+			}
+		case "tvsn", "tves":
+			if number, ok := mp4IlstInt(first); ok && number > 0 {
+				key := "Season"
+				if typ == "tves" {
+					key = "Episode"
+				}
+				extra = append(extra, jsonKV{Key: key, Val: strconv.FormatUint(number, 10)})
+			}
+		case "hdvd":
+			if hd, ok := mp4IlstInt(first); ok {
+				if name := map[uint64]string{0: "No", 1: "720p", 2: "1080p", 3: "2160p"}[hd]; name != "" {
+					extra = append(extra, jsonKV{Key: "HDVideo", Val: name})
+				}
+			}
+		case "covr":
+			for _, value := range values {
+				covers = append(covers, mp4CoverMIME(value))
+			}
+		case "----":
+			// Freeform: mean (reverse-DNS owner) and name select the field, e.g.
+			// com.apple.iTunes:iTunSMPB.
+			name, ok := findMP4Box(item, "name")
+			if !ok || len(name) <= 4 {
+				continue
+			}
+			key := sanitizeMP4FreeformKey(string(name[4:]))
+			if text := mp4IlstText(first); key != "" && text != "" {
+				extra = append(extra, jsonKV{Key: key, Val: text})
+			}
+		default:
+			text := mp4IlstText(first)
+			if text == "" {
+				continue
+			}
+			if key := mp4IlstKeys[typ]; key != "" {
+				tags[key] = text
+			} else if key := mp4IlstExtraKeys[typ]; key != "" {
+				extra = append(extra, jsonKV{Key: key, Val: text})
+			}
+		}
+	}
+	if len(covers) > 0 {
+		tags["Cover"] = "Yes"
+		tags["Cover_Mime"] = strings.Join(covers, " / ")
+		extra = append(extra, jsonKV{Key: "Cover_Count", Val: strconv.Itoa(len(covers))})
+	}
+	if len(tags) == 0 {
+		tags = nil
+	}
+	return tags, extra
+}
+
+// mp4IlstValues returns the data boxes of an ilst item: a type indicator and locale, then the value.
+func mp4IlstValues(item []byte) []mp4IlstValue {
+	var values []mp4IlstValue
+	for pos := 0; pos+8 <= len(item); {
+		size := int(binary.BigEndian.Uint32(item[pos : pos+4]))
+		if size < 8 || pos+size > len(item) {
+			break
+		}
+		if string(item[pos+4:pos+8]) == "data" && size >= 16 {
+			payload := item[pos+8 : pos+size]
+			values = append(values, mp4IlstValue{
+				dataType: binary.BigEndian.Uint32(payload[0:4]) & 0x00FFFFFF,
+				data:     payload[8:],
+			})
+		}
+		pos += size
+	}
+	return values
+}
+
+func mp4IlstText(value mp4IlstValue) string {
+	// Type 1 is UTF-8; older writers leave the type at 0 (implicit) for text atoms too.
+	if value.dataType != 1 && value.dataType != 0 {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimRight(string(value.data), "\x00"))
+}
+
+func mp4IlstInt(value mp4IlstValue) (uint64, bool) {
+	switch len(value.data) {
+	case 1:
+		return uint64(value.data[0]), true
+	case 2:
+		return uint64(binary.BigEndian.Uint16(value.data)), true
+	case 4:
+		return uint64(binary.BigEndian.Uint32(value.data)), true
+	case 8:
+		return binary.BigEndian.Uint64(value.data), true
+	default:
+		return 0, false
+	}
+}
+
+func mp4CoverMIME(value mp4IlstValue) string {
+	switch {
+	case value.dataType == 13 || bytes.HasPrefix(value.data, []byte{0xFF, 0xD8}):
+		return "image/jpeg"
+	case value.dataType == 14 || bytes.HasPrefix(value.data, []byte("\x89PNG")):
+		return "image/png"
+	case value.dataType == 27 || bytes.HasPrefix(value.data, []byte("BM")):
+		return "image/bmp"
+	default:
+		return "application/octet-stream"
+	}
+}
+
+// sanitizeMP4FreeformKey keeps freeform atom names usable as JSON keys.
+func sanitizeMP4FreeformKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-', r == '.':
+			return r
+		case r == ' ':
+			return '_'
+		default:
+			return -1
+		}
+	}, strings.TrimSpace(name))
+}
+
+// addItunesTags merges the ilst tags of a udta or moov meta box; the first writer of a field wins.
+func (info *MP4Info) addItunesTags(meta []byte) {
+	tags, extra := parseMP4ItunesTags(meta)
+	for key, value := range tags {
+		if info.Tags == nil {
+			info.Tags = map[string]string{}
+		}
+		if info.Tags[key] == "" {
+			info.Tags[key] = value
+		}
+	}
+	for _, field := range extra {
+		if !slices.ContainsFunc(info.TagExtra, func(existing jsonKV) bool { return existing.Key == field.Key }) {
+			info.TagExtra = append(info.TagExtra, field)
+		}
+	}
+}
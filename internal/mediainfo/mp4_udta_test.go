@@ -2,6 +2,10 @@ package mediainfo
 
 import (
 	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -32,3 +36,144 @@ func makeMP4DataBox(value string) []byte {
 	writeMP4Box(&out, "data", b.Bytes())
 	return out.Bytes()
 }
+
+// makeMP4TypedDataBox builds an ilst data box with an explicit type indicator.
+func makeMP4TypedDataBox(dataType uint32, value []byte) []byte {
+	payload := make([]byte, 8, 8+len(value))
+	binary.BigEndian.PutUint32(payload[0:4], dataType)
+	payload = append(payload, value...)
+	var out bytes.Buffer
+	writeMP4Box(&out, "data", payload)
+	return out.Bytes()
+}
+
+func buildMP4Ilst() []byte {
+	var ilst bytes.Buffer
+	writeMP4Box(&ilst, "\xa9nam", makeMP4TypedDataBox(1, []byte("Chapter One")))
+	writeMP4Box(&ilst, "\xa9ART", makeMP4TypedDataBox(1, []byte("Narrator")))
+	writeMP4Box(&ilst, "aART", makeMP4TypedDataBox(1, []byte("Author")))
+	writeMP4Box(&ilst, "\xa9alb", makeMP4TypedDataBox(1, []byte("The Book")))
+	writeMP4Box(&ilst, "\xa9day", makeMP4TypedDataBox(1, []byte("2024")))
+	writeMP4Box(&ilst, "trkn", makeMP4TypedDataBox(0, []byte{0, 0, 0, 3, 0, 12, 0, 0}))
+	writeMP4Box(&ilst, "disk", makeMP4TypedDataBox(0, []byte{0, 0, 0, 1, 0, 2}))
+	writeMP4Box(&ilst, "stik", makeMP4TypedDataBox(21, []byte{2}))
+	writeMP4Box(&ilst, "tvsn", makeMP4TypedDataBox(21, []byte{0, 0, 0, 4}))
+	writeMP4Box(&ilst, "hdvd", makeMP4TypedDataBox(21, []byte{2}))
+	writeMP4Box(&ilst, "ldes", makeMP4TypedDataBox(1, []byte("A \"long\" description")))
+
+	var covr bytes.Buffer
+	covr.Write(makeMP4TypedDataBox(13, []byte{0xFF, 0xD8, 0xFF}))
+	covr.Write(makeMP4TypedDataBox(14, []byte("\x89PNG")))
+	writeMP4Box(&ilst, "covr", covr.Bytes())
+
+	var freeform bytes.Buffer
+	writeMP4Box(&freeform, "mean", append([]byte{0, 0, 0, 0}, "com.apple.iTunes"...))
+	writeMP4Box(&freeform, "name", append([]byte{0, 0, 0, 0}, "iTunSMPB"...))
+	freeform.Write(makeMP4TypedDataBox(1, []byte(" 00000000 00000840")))
+	writeMP4Box(&ilst, "----", freeform.Bytes())
+
+	var meta bytes.Buffer
+	meta.Write(make([]byte, 4)) // version/flags
+	writeMP4Box(&meta, "ilst", ilst.Bytes())
+	return meta.Bytes()
+}
+
+func TestParseMP4ItunesTags(t *testing.T) {
+	tags, extra := parseMP4ItunesTags(buildMP4Ilst())
+	for key, want := range map[string]string{
+		"Title":                "Chapter One",
+		"Performer":            "Narrator",
+		"Album_Performer":      "Author",
+		"Album":                "The Book",
+		"Recorded_Date":        "2024",
+		"Track_Position":       "3",
+		"Track_Position_Total": "12",
+		"Part_Position":        "1",
+		"Part_Position_Total":  "2",
+		"ContentType":          "Audiobook",
+		"Cover":                "Yes",
+		"Cover_Mime":           "image/jpeg / image/png",
+	} {
+		if got := tags[key]; got != want {
+			t.Fatalf("%s=%q, want %q", key, got, want)
+		}
+	}
+	want := []jsonKV{
+		{Key: "Season", Val: "4"},
+		{Key: "HDVideo", Val: "1080p"},
+		{Key: "LongDescription", Val: "A \"long\" description"},
+		{Key: "iTunSMPB", Val: "00000000 00000840"},
+		{Key: "Cover_Count", Val: "2"},
+	}
+	if !slices.Equal(extra, want) {
+		t.Fatalf("extra=%v, want %v", extra, want)
+	}
+}
+
+// buildMP4TaggedFile builds an audiobook with no tracks whose udta meta holds buildMP4Ilst.
+func buildMP4TaggedFile() []byte {
+	var udta bytes.Buffer
+	writeMP4Box(&udta, "meta", buildMP4Ilst())
+	var moov bytes.Buffer
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], 5000)
+	writeMP4Box(&moov, "mvhd", mvhd)
+	writeMP4Box(&moov, "udta", udta.Bytes())
+	var file bytes.Buffer
+	writeMP4Box(&file, "ftyp", []byte{'M', '4', 'B', ' ', 0, 0, 0, 0})
+	writeMP4Box(&file, "moov", moov.Bytes())
+	return file.Bytes()
+}
+
+func TestAnalyzeMP4ItunesTags(t *testing.T) {
+	data := buildMP4TaggedFile()
+	report, err := AnalyzeReader(bytes.NewReader(data), int64(len(data)), "book.m4b", defaultAnalyzeOptions())
+	if err != nil {
+		t.Fatalf("analyze reader: %v", err)
+	}
+	for name, want := range map[string]string{
+		"Title":               "Chapter One",
+		"Album":               "The Book",
+		"Album/Performer":     "Author",
+		"Performer":           "Narrator",
+		"Track name/Position": "3",
+		"Track name/Total":    "12",
+		"Part/Position":       "1",
+		"Content type":        "Audiobook",
+		"Recorded date":       "2024",
+		"Cover":               "Yes",
+	} {
+		if got := findField(report.General.Fields, name); got != want {
+			t.Fatalf("%s=%q, want %q", name, got, want)
+		}
+	}
+	if text := RenderText([]Report{report}); !strings.Contains(text, "\nAlbum                                    : The Book\n") {
+		t.Fatalf("text output has no Album:\n%s", text)
+	}
+	tmpl, err := ParseInformTemplate("General;%Album/String% #%Track_Position%")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := RenderInform([]Report{report}, tmpl); got != "The Book #3" {
+		t.Fatalf("inform=%q", got)
+	}
+
+	var keys []string
+	for _, field := range buildJSONGeneralFields(report) {
+		keys = append(keys, field.Key)
+	}
+	title, album, cover, extraAt := slices.Index(keys, "Title"), slices.Index(keys, "Album"), slices.Index(keys, "Cover"), slices.Index(keys, "extra")
+	if title < 0 || album < title || cover < album || extraAt < cover {
+		t.Fatalf("General JSON key order: %v", keys)
+	}
+
+	extra := report.General.JSONRaw["extra"]
+	if !strings.Contains(extra, `"LongDescription":"A \"long\" description"`) || !strings.Contains(extra, `"Cover_Count":"2"`) {
+		t.Fatalf("extra=%s", extra)
+	}
+	var decoded map[string]string
+	if err := json.Unmarshal([]byte(extra), &decoded); err != nil {
+		t.Fatalf("extra is not valid JSON: %v (%s)", err, extra)
+	}
+}
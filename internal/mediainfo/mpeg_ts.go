@@ -486,6 +486,9 @@ func parseMPEGTSWithPacketSize(file io.ReadSeeker, size int64, packetSize int64,
 		var packetIndex int64
 		carry := 0
 		for {
+			if readerCanceled(file) {
+				break
+			}
 			n, err := reader.Read(buf[carry:])
 			if n == 0 && err != nil {
 				break
@@ -4474,6 +4477,9 @@ func scanTSForH264WithPacketSize(file io.ReadSeeker, pid uint16, size int64, pac
 	}
 	carry := 0
 	for maxPackets == 0 || readPackets < maxPackets {
+		if readerCanceled(file) {
+			break
+		}
 		n, err := reader.Read(buf[carry:])
 		if n == 0 && err != nil {
 			break
@@ -5,6 +5,7 @@ var streamKindOrder = map[StreamKind]int{
 	StreamVideo:   1,
 	StreamAudio:   2,
 	StreamText:    3,
-	StreamImage:   4,
-	StreamMenu:    5,
+	StreamOther:   4,
+	StreamImage:   5,
+	StreamMenu:    6,
 }
@@ -1,6 +1,332 @@
 package mediainfo
 
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	ebuCoreNS             = "urn:ebu:metadata-schema:ebucore"
+	ebuCoreSchemaLocation = "https://www.ebu.ch/metadata/schemas/EBUCore/20171009/ebucore.xsd"
+	ebuCoreVersion        = "1.8"
+)
+
+// RenderEBUCore renders the reports as an EBUCore 1.8 document, one ebucore:format per file.
 func RenderEBUCore(reports []Report) string {
-	_ = reports
-	return "<ebuCoreMain></ebuCoreMain>"
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString("<!-- Generated by " + AppName + " " + FormatVersion(AppVersion) + " -->\n")
+	buildEBUCore(reports).writeXML(&buf, 0)
+	return buf.String()
+}
+
+// RenderEBUCoreJSON renders the same EBUCore tree as JSON (attributes as "@name", text as "#value").
+func RenderEBUCoreJSON(reports []Report) string {
+	var buf bytes.Buffer
+	buf.WriteString("{\n  \"ebucore:ebuCoreMain\": ")
+	buildEBUCore(reports).writeJSON(&buf, 1)
+	buf.WriteString("\n}\n")
+	return buf.String()
+}
+
+func buildEBUCore(reports []Report) *xmlNode {
+	root := newXMLNode("ebucore:ebuCoreMain",
+		xmlAttr{name: "xmlns:dc", value: "http://purl.org/dc/elements/1.1/"},
+		xmlAttr{name: "xmlns:ebucore", value: ebuCoreNS},
+		xmlAttr{name: "xmlns:xsi", value: "http://www.w3.org/2001/XMLSchema-instance"},
+		xmlAttr{name: "xsi:schemaLocation", value: ebuCoreNS + " " + ebuCoreSchemaLocation},
+		xmlAttr{name: "version", value: ebuCoreVersion},
+	)
+	core := root.add(newXMLNode("ebucore:coreMetadata"))
+	// coreMetadata is a sequence: every title precedes every format.
+	for _, report := range reports {
+		general := buildJSONGeneralFields(report)
+		if title := jsonFieldValue(general, "Title"); title != "" {
+			core.add(newXMLNode("ebucore:title")).addText("dc:title", title)
+		}
+	}
+	for _, report := range reports {
+		core.add(buildEBUCoreFormat(report))
+	}
+	return root
+}
+
+func buildEBUCoreFormat(report Report) *xmlNode {
+	format := newXMLNode("ebucore:format")
+	general := buildJSONGeneralFields(report)
+	containerFormat := findField(report.General.Fields, "Format")
+
+	byKind := map[StreamKind][]*xmlNode{}
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		fields := buildJSONStreamFields(stream, order, 0, containerFormat)
+		var node *xmlNode
+		switch stream.Kind {
+		case StreamImage:
+			node = buildEBUCoreImageFormat(fields)
+		case StreamVideo:
+			node = buildEBUCoreVideoFormat(fields)
+		case StreamAudio:
+			node = buildEBUCoreAudioFormat(fields)
+		case StreamText:
+			node = buildEBUCoreDataFormat(fields)
+		}
+		if node != nil {
+			byKind[stream.Kind] = append(byKind[stream.Kind], node)
+		}
+	})
+	// Schema order: imageFormat, videoFormat, audioFormat, containerFormat, dataFormat.
+	for _, kind := range []StreamKind{StreamImage, StreamVideo, StreamAudio} {
+		for _, node := range byKind[kind] {
+			format.add(node)
+		}
+	}
+	if name := jsonFieldValue(general, "Format"); name != "" {
+		container := format.add(newXMLNode("ebucore:containerFormat").attr("containerFormatName", name))
+		container.add(newXMLNode("ebucore:containerEncoding").attr("formatLabel", name))
+		addEBUCoreCodec(container, jsonFieldValue(general, "CodecID"))
+		addEBUCoreString(container, "FormatProfile", jsonFieldValue(general, "Format_Profile"))
+		addEBUCoreString(container, "FormatVersion", jsonFieldValue(general, "Format_Version"))
+		addEBUCoreString(container, "WritingApplication", jsonFieldValue(general, "Encoded_Application"))
+		addEBUCoreString(container, "WritingLibrary", jsonFieldValue(general, "Encoded_Library"))
+	}
+	for _, node := range byKind[StreamText] {
+		format.add(node)
+	}
+
+	if seconds, err := strconv.ParseFloat(jsonFieldValue(general, "Duration"), 64); err == nil && seconds > 0 {
+		format.add(newXMLNode("ebucore:duration")).addText("ebucore:normalPlayTime", ebuCoreDuration(seconds))
+	}
+	format.addText("ebucore:fileSize", jsonFieldValue(general, "FileSize"))
+	if report.Ref != "" {
+		format.addText("ebucore:fileName", filepath.Base(report.Ref))
+		format.addText("ebucore:locator", report.Ref)
+	}
+	addEBUCoreInteger(format, "OverallBitRate", "bps", jsonFieldValue(general, "OverallBitRate"))
+	if date, clock, ok := ebuCoreDate(jsonFieldValue(general, "File_Created_Date")); ok {
+		format.add(newXMLNode("ebucore:dateCreated").attr("startDate", date).attr("startTime", clock))
+	}
+	if date, clock, ok := ebuCoreDate(jsonFieldValue(general, "File_Modified_Date")); ok {
+		format.add(newXMLNode("ebucore:dateModified").attr("startDate", date).attr("startTime", clock))
+	}
+	return format
+}
+
+func buildEBUCoreImageFormat(fields []jsonKV) *xmlNode {
+	node := newXMLNode("ebucore:imageFormat").attr("imageFormatName", jsonFieldValue(fields, "Format"))
+	node.addText("ebucore:width", jsonFieldValue(fields, "Width"), xmlAttr{name: "unit", value: "pixel"})
+	node.addText("ebucore:height", jsonFieldValue(fields, "Height"), xmlAttr{name: "unit", value: "pixel"})
+	addEBUCoreString(node, "ColorSpace", jsonFieldValue(fields, "ColorSpace"))
+	addEBUCoreString(node, "ChromaSubsampling", jsonFieldValue(fields, "ChromaSubsampling"))
+	addEBUCoreInteger(node, "BitDepth", "bit", jsonFieldValue(fields, "BitDepth"))
+	addEBUCoreInteger(node, "StreamSize", "byte", jsonFieldValue(fields, "StreamSize"))
+	return node
+}
+
+func buildEBUCoreVideoFormat(fields []jsonKV) *xmlNode {
+	node := newXMLNode("ebucore:videoFormat").attr("videoFormatName", jsonFieldValue(fields, "Format"))
+	node.addText("ebucore:width", jsonFieldValue(fields, "Width"), xmlAttr{name: "unit", value: "pixel"})
+	node.addText("ebucore:height", jsonFieldValue(fields, "Height"), xmlAttr{name: "unit", value: "pixel"})
+	if rate := ebuCoreFrameRate(fields); rate != nil {
+		node.add(rate)
+	}
+	if num, den, ok := ebuCoreAspectRatio(jsonFieldValue(fields, "DisplayAspectRatio")); ok {
+		ratio := node.add(newXMLNode("ebucore:aspectRatio").attr("typeLabel", "display"))
+		ratio.addText("ebucore:factorNumerator", strconv.Itoa(num))
+		ratio.addText("ebucore:factorDenominator", strconv.Itoa(den))
+	}
+	if profile := ebuCoreProfile(fields); profile != "" {
+		node.add(newXMLNode("ebucore:videoEncoding").attr("typeLabel", profile))
+	}
+	addEBUCoreCodec(node, jsonFieldValue(fields, "CodecID"))
+	addEBUCoreBitRate(node, fields)
+	switch strings.ToLower(jsonFieldValue(fields, "ScanType")) {
+	case "progressive":
+		node.addText("ebucore:scanningFormat", "progressive")
+	case "interlaced", "mbaff":
+		node.addText("ebucore:scanningFormat", "interlaced")
+	}
+	switch jsonFieldValue(fields, "ScanOrder") {
+	case "TFF":
+		node.addText("ebucore:scanningOrder", "top")
+	case "BFF":
+		node.addText("ebucore:scanningOrder", "bottom")
+	}
+	if id := jsonFieldValue(fields, "ID"); id != "" {
+		node.add(newXMLNode("ebucore:videoTrack").attr("trackId", id).attr("trackName", jsonFieldValue(fields, "Title")))
+	}
+	addEBUCoreString(node, "ChromaSubsampling", jsonFieldValue(fields, "ChromaSubsampling"))
+	addEBUCoreString(node, "ColorSpace", jsonFieldValue(fields, "ColorSpace"))
+	addEBUCoreString(node, "Standard", jsonFieldValue(fields, "Standard"))
+	addEBUCoreString(node, "WritingLibrary", jsonFieldValue(fields, "Encoded_Library"))
+	addEBUCoreInteger(node, "BitDepth", "bit", jsonFieldValue(fields, "BitDepth"))
+	addEBUCoreInteger(node, "FrameCount", "", jsonFieldValue(fields, "FrameCount"))
+	addEBUCoreInteger(node, "StreamSize", "byte", jsonFieldValue(fields, "StreamSize"))
+	addEBUCoreBoolean(node, "CABAC", jsonFieldValue(fields, "Format_Settings_CABAC"))
+	return node
+}
+
+func buildEBUCoreAudioFormat(fields []jsonKV) *xmlNode {
+	node := newXMLNode("ebucore:audioFormat").attr("audioFormatName", jsonFieldValue(fields, "Format"))
+	if profile := ebuCoreProfile(fields); profile != "" {
+		node.add(newXMLNode("ebucore:audioEncoding").attr("typeLabel", profile))
+	}
+	addEBUCoreCodec(node, jsonFieldValue(fields, "CodecID"))
+	if layout := jsonFieldValue(fields, "ChannelLayout"); layout != "" {
+		node.add(newXMLNode("ebucore:audioTrackConfiguration").attr("typeLabel", layout))
+	}
+	node.addText("ebucore:samplingRate", jsonFieldValue(fields, "SamplingRate"))
+	node.addText("ebucore:sampleSize", jsonFieldValue(fields, "BitDepth"))
+	addEBUCoreBitRate(node, fields)
+	if id := jsonFieldValue(fields, "ID"); id != "" {
+		node.add(newXMLNode("ebucore:audioTrack").
+			attr("trackId", id).
+			attr("trackName", jsonFieldValue(fields, "Title")).
+			attr("trackLanguage", jsonFieldValue(fields, "Language")))
+	}
+	node.addText("ebucore:channels", jsonFieldValue(fields, "Channels"))
+	addEBUCoreString(node, "ChannelPositions", jsonFieldValue(fields, "ChannelPositions"))
+	addEBUCoreString(node, "Endianness", jsonFieldValue(fields, "Format_Settings_Endianness"))
+	addEBUCoreString(node, "Sign", jsonFieldValue(fields, "Format_Settings_Sign"))
+	addEBUCoreString(node, "WritingLibrary", jsonFieldValue(fields, "Encoded_Library"))
+	addEBUCoreInteger(node, "StreamSize", "byte", jsonFieldValue(fields, "StreamSize"))
+	return node
+}
+
+func buildEBUCoreDataFormat(fields []jsonKV) *xmlNode {
+	name := jsonFieldValue(fields, "Format")
+	node := newXMLNode("ebucore:dataFormat").attr("dataFormatName", name)
+	element, attrName := "ebucore:subtitlingFormat", "subtitlingFormatName"
+	if strings.HasPrefix(name, "EIA-") || strings.HasPrefix(name, "CEA-") {
+		element, attrName = "ebucore:captioningFormat", "captioningFormatName"
+	}
+	node.add(newXMLNode(element).
+		attr(attrName, name).
+		attr("trackId", jsonFieldValue(fields, "ID")).
+		attr("trackName", jsonFieldValue(fields, "Title")).
+		attr("language", jsonFieldValue(fields, "Language")))
+	addEBUCoreCodec(node, jsonFieldValue(fields, "CodecID"))
+	addEBUCoreInteger(node, "StreamSize", "byte", jsonFieldValue(fields, "StreamSize"))
+	return node
+}
+
+func addEBUCoreCodec(node *xmlNode, codecID string) {
+	if codecID == "" {
+		return
+	}
+	node.add(newXMLNode("ebucore:codec")).add(newXMLNode("ebucore:codecIdentifier")).addText("dc:identifier", codecID)
+}
+
+func addEBUCoreBitRate(node *xmlNode, fields []jsonKV) {
+	node.addText("ebucore:bitRate", jsonFieldValue(fields, "BitRate"))
+	node.addText("ebucore:bitRateMax", jsonFieldValue(fields, "BitRate_Maximum"))
+	switch jsonFieldValue(fields, "BitRate_Mode") {
+	case "CBR":
+		node.addText("ebucore:bitRateMode", "constant")
+	case "VBR":
+		node.addText("ebucore:bitRateMode", "variable")
+	}
+}
+
+func addEBUCoreString(node *xmlNode, label, value string) {
+	node.addText("ebucore:technicalAttributeString", value, xmlAttr{name: "typeLabel", value: label})
+}
+
+func addEBUCoreInteger(node *xmlNode, label, unit, value string) {
+	if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+		return
+	}
+	child := node.addText("ebucore:technicalAttributeInteger", value, xmlAttr{name: "typeLabel", value: label})
+	child.attr("unit", unit)
+}
+
+func addEBUCoreBoolean(node *xmlNode, label, value string) {
+	switch strings.ToLower(value) {
+	case "yes":
+		value = "true"
+	case "no":
+		value = "false"
+	default:
+		return
+	}
+	node.addText("ebucore:technicalAttributeBoolean", value, xmlAttr{name: "typeLabel", value: label})
+}
+
+func ebuCoreProfile(fields []jsonKV) string {
+	profile := jsonFieldValue(fields, "Format_Profile")
+	if level := jsonFieldValue(fields, "Format_Level"); profile != "" && level != "" {
+		// Numeric levels read "High@L4.1"; named ones (MPEG-2) read "Main@Main".
+		if level[0] >= '0' && level[0] <= '9' {
+			level = "L" + level
+		}
+		profile += "@" + level
+	}
+	if profile == "" {
+		profile = jsonFieldValue(fields, "Format_AdditionalFeatures")
+	}
+	return profile
+}
+
+// ebuCoreFrameRate renders frameRate as a rationalType: the rounded rate with the exact ratio
+// in factorNumerator/factorDenominator.
+func ebuCoreFrameRate(fields []jsonKV) *xmlNode {
+	fps, err := strconv.ParseFloat(jsonFieldValue(fields, "FrameRate"), 64)
+	if err != nil || fps <= 0 {
+		return nil
+	}
+	node := &xmlNode{name: "ebucore:frameRate", text: strconv.Itoa(int(math.Round(fps)))}
+	num := jsonFieldValue(fields, "FrameRate_Num")
+	den := jsonFieldValue(fields, "FrameRate_Den")
+	if num == "" || den == "" {
+		n, d := rationalizeFrameRate(fps)
+		num, den = strconv.Itoa(n), strconv.Itoa(d)
+	}
+	if den != "1" {
+		node.attr("factorNumerator", num).attr("factorDenominator", den)
+	}
+	return node
+}
+
+func ebuCoreAspectRatio(value string) (int, int, bool) {
+	ratio, err := strconv.ParseFloat(value, 64)
+	if err != nil || ratio <= 0 {
+		return 0, 0, false
+	}
+	for _, known := range [][2]int{{4, 3}, {16, 9}, {1, 1}, {5, 4}, {3, 2}, {16, 10}, {21, 9}} {
+		if math.Abs(ratio-float64(known[0])/float64(known[1])) < 0.001 {
+			return known[0], known[1], true
+		}
+	}
+	num := uint64(math.Round(ratio * 1000))
+	div := gcd(num, 1000)
+	return int(num / div), int(1000 / div), true
+}
+
+// ebuCoreDuration formats seconds as an xs:duration ("PT1H2M3.456S").
+func ebuCoreDuration(seconds float64) string {
+	totalMs := int64(math.Round(seconds * 1000))
+	hours := totalMs / 3600000
+	minutes := (totalMs / 60000) % 60
+	secs := float64(totalMs%60000) / 1000
+	var b strings.Builder
+	b.WriteString("PT")
+	if hours > 0 {
+		b.WriteString(strconv.FormatInt(hours, 10) + "H")
+	}
+	if minutes > 0 {
+		b.WriteString(strconv.FormatInt(minutes, 10) + "M")
+	}
+	b.WriteString(strconv.FormatFloat(secs, 'f', -1, 64) + "S")
+	return b.String()
+}
+
+// ebuCoreDate splits MediaInfo's "2006-01-02 15:04:05 UTC" into xs:date and xs:time.
+func ebuCoreDate(value string) (string, string, bool) {
+	date, clock, ok := strings.Cut(strings.TrimSuffix(value, " UTC"), " ")
+	if !ok || len(date) != len("2006-01-02") || len(clock) != len("15:04:05") {
+		return "", "", false
+	}
+	return date, clock + "Z", true
 }
@@ -0,0 +1,371 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// ebuCoreContentModels is a hand-copied subset of the EBUCore 1.8 schema (ebucore.xsd,
+// 2017-10-09): the sequences of the elements the renderer emits. Children must be drawn from
+// the list and appear in its order; technical attributes follow the technicalAttributes group
+// order. It does not cover types, cardinality or anything the renderer does not emit;
+// TestRenderEBUCoreXSD does the full check against the vendored schema.
+var ebuCoreContentModels = map[string][]string{
+	"ebuCoreMain":  {"coreMetadata"},
+	"coreMetadata": {"title", "alternativeTitle", "creator", "subject", "description", "format"},
+	"title":        {"dc:title"},
+	"format": {
+		"medium", "imageFormat", "videoFormat", "audioFormat", "containerFormat", "dataFormat", "signingFormat",
+		"start", "end", "duration", "fileSize", "fileName", "mimeType", "locator", "hash",
+		"technicalAttributeString", "technicalAttributeInteger", "dateCreated", "dateModified",
+	},
+	"imageFormat": {
+		"orientation", "width", "height", "imageEncoding",
+		"technicalAttributeString", "technicalAttributeInteger", "technicalAttributeBoolean",
+	},
+	"videoFormat": {
+		"width", "height", "lines", "frameRate", "aspectRatio", "videoEncoding", "codec", "bitRate", "bitRateMax",
+		"bitRateMode", "scanningFormat", "scanningOrder", "noiseFilter", "videoTrack", "flag_3D",
+		"technicalAttributeString", "technicalAttributeInteger", "technicalAttributeBoolean",
+	},
+	"audioFormat": {
+		"audioEncoding", "codec", "audioTrackConfiguration", "samplingRate", "sampleSize", "sampleType",
+		"bitRate", "bitRateMax", "bitRateMode", "audioTrack", "channels",
+		"technicalAttributeString", "technicalAttributeInteger", "technicalAttributeBoolean",
+	},
+	"containerFormat": {
+		"containerEncoding", "codec", "technicalAttributeString", "technicalAttributeInteger", "technicalAttributeBoolean",
+	},
+	"dataFormat": {
+		"captioningFormat", "ancillaryDataFormat", "subtitlingFormat", "codec",
+		"technicalAttributeString", "technicalAttributeInteger", "technicalAttributeBoolean",
+	},
+	"aspectRatio":     {"factorNumerator", "factorDenominator"},
+	"codec":           {"codecIdentifier", "name", "vendor", "version", "family"},
+	"codecIdentifier": {"dc:identifier"},
+	"duration":        {"normalPlayTime", "editUnitNumber", "timecode", "time"},
+}
+
+// ebuCoreRequiredAttrs lists attributes the schema marks use="required" or that the typeLabel
+// convention depends on.
+var ebuCoreRequiredAttrs = map[string][]string{
+	"ebuCoreMain":               {"version"},
+	"technicalAttributeString":  {"typeLabel"},
+	"technicalAttributeInteger": {"typeLabel"},
+	"technicalAttributeBoolean": {"typeLabel"},
+}
+
+var (
+	xsDurationPattern = regexp.MustCompile(`^PT(\d+H)?(\d+M)?\d+(\.\d+)?S$`)
+	xsTimePattern     = regexp.MustCompile(`^\d{2}:\d{2}:\d{2}Z$`)
+	xsDatePattern     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+)
+
+type xmlTestElement struct {
+	name     string
+	attrs    map[string]string
+	text     string
+	children []*xmlTestElement
+}
+
+// parseXMLTree decodes doc into a tree, naming elements by the prefix namespaces assigns to
+// their namespace URI ("" for none) and failing on any other namespace.
+func parseXMLTree(t *testing.T, doc string, namespaces map[string]string) *xmlTestElement {
+	t.Helper()
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	var stack []*xmlTestElement
+	var root *xmlTestElement
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("xml: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			prefix, ok := namespaces[tok.Name.Space]
+			if !ok {
+				t.Fatalf("element %s in unexpected namespace %q", tok.Name.Local, tok.Name.Space)
+			}
+			name := tok.Name.Local
+			if prefix != "" {
+				name = prefix + ":" + name
+			}
+			elem := &xmlTestElement{name: name, attrs: map[string]string{}}
+			for _, attr := range tok.Attr {
+				elem.attrs[attr.Name.Local] = attr.Value
+			}
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1]
+				parent.children = append(parent.children, elem)
+			} else {
+				root = elem
+			}
+			stack = append(stack, elem)
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text += strings.TrimSpace(string(tok))
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	if root == nil {
+		t.Fatalf("empty document")
+	}
+	return root
+}
+
+func validateEBUCoreElement(t *testing.T, elem *xmlTestElement) {
+	t.Helper()
+	for _, attr := range ebuCoreRequiredAttrs[elem.name] {
+		if elem.attrs[attr] == "" {
+			t.Fatalf("%s is missing required attribute %s", elem.name, attr)
+		}
+	}
+	switch elem.name {
+	case "normalPlayTime":
+		if !xsDurationPattern.MatchString(elem.text) {
+			t.Fatalf("normalPlayTime=%q is not an xs:duration", elem.text)
+		}
+	case "fileSize", "samplingRate", "sampleSize", "bitRate", "bitRateMax", "channels", "width", "height",
+		"frameRate", "factorNumerator", "factorDenominator", "technicalAttributeInteger":
+		if _, err := strconv.ParseUint(elem.text, 10, 64); err != nil {
+			t.Fatalf("%s=%q is not a non-negative integer", elem.name, elem.text)
+		}
+	case "technicalAttributeBoolean":
+		if elem.text != "true" && elem.text != "false" {
+			t.Fatalf("technicalAttributeBoolean=%q is not an xs:boolean", elem.text)
+		}
+	case "bitRateMode":
+		if elem.text != "constant" && elem.text != "variable" {
+			t.Fatalf("bitRateMode=%q", elem.text)
+		}
+	case "dateCreated", "dateModified":
+		if !xsDatePattern.MatchString(elem.attrs["startDate"]) || !xsTimePattern.MatchString(elem.attrs["startTime"]) {
+			t.Fatalf("%s has invalid date/time %v", elem.name, elem.attrs)
+		}
+	}
+	model, hasModel := ebuCoreContentModels[elem.name]
+	if !hasModel {
+		if len(elem.children) > 0 {
+			t.Fatalf("%s has unexpected children", elem.name)
+		}
+		return
+	}
+	checkXMLSequence(t, elem, model)
+	for _, child := range elem.children {
+		validateEBUCoreElement(t, child)
+	}
+}
+
+// checkXMLSequence fails unless every child of elem is listed in model, in model order.
+func checkXMLSequence(t *testing.T, elem *xmlTestElement, model []string) {
+	t.Helper()
+	last := -1
+	for _, child := range elem.children {
+		pos := slices.Index(model, child.name)
+		if pos < 0 {
+			t.Fatalf("%s is not allowed in %s", child.name, elem.name)
+		}
+		if pos < last {
+			t.Fatalf("%s is out of sequence order in %s", child.name, elem.name)
+		}
+		last = pos
+	}
+}
+
+// ebuCoreTestSamples are the samples the EBUCore tests render.
+var ebuCoreTestSamples = []string{"sample.mp4", "sample.mkv", "sample.ts", "sample.vob", "sample.flac", "sample.wav"}
+
+func TestRenderEBUCoreContentModels(t *testing.T) {
+	for _, sample := range ebuCoreTestSamples {
+		t.Run(sample, func(t *testing.T) {
+			report, err := AnalyzeFile(filepath.Join("samples", sample))
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			root := parseXMLTree(t, RenderEBUCore([]Report{report}), map[string]string{
+				ebuCoreNS:                          "",
+				"http://purl.org/dc/elements/1.1/": "dc",
+			})
+			if root.name != "ebuCoreMain" || root.attrs["version"] != ebuCoreVersion {
+				t.Fatalf("root=%s version=%q", root.name, root.attrs["version"])
+			}
+			validateEBUCoreElement(t, root)
+		})
+	}
+}
+
+// TestRenderEBUCoreXSD validates the XML against the published EBUCore 1.8 schema, vendored
+// under testdata/ebucore by its fetch.sh. Only a missing xmllint skips it.
+func TestRenderEBUCoreXSD(t *testing.T) {
+	schemas := filepath.Join("internal", "mediainfo", "testdata", "ebucore")
+	xsd := filepath.Join(schemas, "ebucore.xsd")
+	if _, err := os.Stat(xsd); err != nil {
+		t.Fatalf("EBUCore schema missing (run %s): %v", filepath.Join(schemas, "fetch.sh"), err)
+	}
+	xmllint, err := exec.LookPath("xmllint")
+	if err != nil {
+		t.Skip("xmllint not installed")
+	}
+	dir := t.TempDir()
+	for _, sample := range ebuCoreTestSamples {
+		t.Run(sample, func(t *testing.T) {
+			report, err := AnalyzeFile(filepath.Join("samples", sample))
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			doc := filepath.Join(dir, sample+".xml")
+			if err := os.WriteFile(doc, []byte(RenderEBUCore([]Report{report})), 0o600); err != nil {
+				t.Fatalf("write: %v", err)
+			}
+			if out, err := exec.Command(xmllint, "--noout", "--nonet", "--schema", xsd, doc).CombinedOutput(); err != nil {
+				t.Fatalf("xmllint: %v\n%s", err, out)
+			}
+		})
+	}
+}
+
+// ebuCoreXMLShape decodes the EBUCore XML into the shape RenderEBUCoreJSON gives it: "@name"
+// attributes, "#value" text and every child name mapped to the list of those children.
+func ebuCoreXMLShape(t *testing.T, doc string) map[string]any {
+	t.Helper()
+	qualified := func(name xml.Name) string {
+		if name.Space == "" {
+			return name.Local
+		}
+		return name.Space + ":" + name.Local
+	}
+	dec := xml.NewDecoder(strings.NewReader(doc))
+	type frame struct {
+		name string
+		node map[string]any
+	}
+	var stack []frame
+	var root map[string]any
+	for {
+		tok, err := dec.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("xml: %v", err)
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			node := map[string]any{}
+			for _, attr := range tok.Attr {
+				node["@"+qualified(attr.Name)] = attr.Value
+			}
+			name := qualified(tok.Name)
+			if len(stack) > 0 {
+				parent := stack[len(stack)-1].node
+				list, _ := parent[name].([]any)
+				parent[name] = append(list, node)
+			} else {
+				root = map[string]any{name: node}
+			}
+			stack = append(stack, frame{name: name, node: node})
+		case xml.CharData:
+			if text := strings.TrimSpace(string(tok)); text != "" && len(stack) > 0 {
+				top := stack[len(stack)-1].node
+				value, _ := top["#value"].(string)
+				top["#value"] = value + text
+			}
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return root
+}
+
+func TestRenderEBUCoreJSONMatchesXML(t *testing.T) {
+	for _, sample := range ebuCoreTestSamples {
+		t.Run(sample, func(t *testing.T) {
+			report, err := AnalyzeFile(filepath.Join("samples", sample))
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+			var got map[string]any
+			if err := json.Unmarshal([]byte(RenderEBUCoreJSON([]Report{report})), &got); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			want := ebuCoreXMLShape(t, RenderEBUCore([]Report{report}))
+			if !reflect.DeepEqual(got, want) {
+				gotJSON, _ := json.Marshal(got)
+				wantJSON, _ := json.Marshal(want)
+				t.Fatalf("EBUCore JSON does not match the XML:\n json: %s\n  xml: %s", gotJSON, wantJSON)
+			}
+		})
+	}
+}
+
+func TestRenderEBUCoreMapping(t *testing.T) {
+	report, err := AnalyzeFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	out := RenderEBUCore([]Report{report})
+	for _, want := range []string{
+		`<ebucore:videoFormat videoFormatName="AVC">`,
+		`<ebucore:width unit="pixel">640</ebucore:width>`,
+		`<ebucore:frameRate factorNumerator="30000" factorDenominator="1001">30</ebucore:frameRate>`,
+		`<ebucore:videoEncoding typeLabel="High@L3"/>`,
+		`<ebucore:audioFormat audioFormatName="AAC">`,
+		`<ebucore:samplingRate>48000</ebucore:samplingRate>`,
+		`<ebucore:containerFormat containerFormatName="Matroska">`,
+		`<ebucore:normalPlayTime>PT4.021S</ebucore:normalPlayTime>`,
+		`<ebucore:fileSize>275012</ebucore:fileSize>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("EBUCore output is missing %s", want)
+		}
+	}
+}
+
+func TestRenderEBUCoreJSON(t *testing.T) {
+	report, err := AnalyzeFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	var doc map[string]map[string]any
+	if err := json.Unmarshal([]byte(RenderEBUCoreJSON([]Report{report})), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	main := doc["ebucore:ebuCoreMain"]
+	if main["@version"] != ebuCoreVersion {
+		t.Fatalf("@version=%v", main["@version"])
+	}
+	core := main["ebucore:coreMetadata"].([]any)[0].(map[string]any)
+	format := core["ebucore:format"].([]any)[0].(map[string]any)
+	video := format["ebucore:videoFormat"].([]any)[0].(map[string]any)
+	if video["@videoFormatName"] != "AVC" {
+		t.Fatalf("@videoFormatName=%v", video["@videoFormatName"])
+	}
+	width := video["ebucore:width"].([]any)[0].(map[string]any)
+	if width["#value"] != "640" || width["@unit"] != "pixel" {
+		t.Fatalf("width=%v", width)
+	}
+}
+
+func TestEBUCoreDuration(t *testing.T) {
+	cases := map[float64]string{4.021: "PT4.021S", 3723.5: "PT1H2M3.5S", 60: "PT1M0S"}
+	for seconds, want := range cases {
+		if got := ebuCoreDuration(seconds); got != want {
+			t.Fatalf("ebuCoreDuration(%v)=%q, want %q", seconds, got, want)
+		}
+	}
+}
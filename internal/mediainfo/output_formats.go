@@ -0,0 +1,93 @@
+package mediainfo
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OutputFormats lists the --Output names RenderOutput accepts (case-insensitive).
+var OutputFormats = []string{
+	"TEXT", "JSON", "XML", "OLDXML", "HTML", "CSV",
+	"EBUCORE", "EBUCORE_JSON", "PBCORE", "PBCORE2", "GRAPH_SVG", "GRAPH_DOT",
+}
+
+// IsOutputFormat reports whether name is one of OutputFormats.
+func IsOutputFormat(name string) bool {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	for _, format := range OutputFormats {
+		if format == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderOutput renders reports in the named output format; "" selects TEXT. tr applies to the
+// TEXT and HTML formats only and may be nil.
+func RenderOutput(format string, reports []Report, tr *Translation) (string, error) {
+	switch strings.ToUpper(strings.TrimSpace(format)) {
+	case "", "TEXT":
+		return RenderTextTranslated(reports, tr), nil
+	case "JSON":
+		return RenderJSON(reports), nil
+	case "XML":
+		return RenderXML(reports), nil
+	case "OLDXML":
+		return RenderOLDXML(reports), nil
+	case "HTML":
+		return RenderHTMLTranslated(reports, tr), nil
+	case "CSV":
+		return RenderCSV(reports), nil
+	case "EBUCORE":
+		return RenderEBUCore(reports), nil
+	case "EBUCORE_JSON":
+		return RenderEBUCoreJSON(reports), nil
+	case "PBCORE":
+		return RenderPBCore(reports), nil
+	case "PBCORE2":
+		return RenderPBCore2(reports), nil
+	case "GRAPH_SVG":
+		return RenderGraphSVG(reports), nil
+	case "GRAPH_DOT":
+		return RenderGraphDOT(reports), nil
+	}
+	return "", fmt.Errorf("output format not implemented: %s", format)
+}
+
+// OutputContentType returns the MIME type of an output format.
+func OutputContentType(format string) string {
+	switch strings.ToUpper(strings.TrimSpace(format)) {
+	case "JSON", "EBUCORE_JSON":
+		return "application/json"
+	case "XML", "OLDXML", "EBUCORE", "PBCORE", "PBCORE2":
+		return "application/xml"
+	case "HTML":
+		return "text/html; charset=utf-8"
+	case "CSV":
+		return "text/csv; charset=utf-8"
+	case "GRAPH_SVG":
+		return "image/svg+xml"
+	case "GRAPH_DOT":
+		return "text/vnd.graphviz; charset=utf-8"
+	}
+	return "text/plain; charset=utf-8"
+}
+
+// OutputExtension returns the file extension, with its dot, for saving an output format.
+func OutputExtension(format string) string {
+	switch strings.ToUpper(strings.TrimSpace(format)) {
+	case "JSON", "EBUCORE_JSON":
+		return ".json"
+	case "XML", "OLDXML", "EBUCORE", "PBCORE", "PBCORE2":
+		return ".xml"
+	case "HTML":
+		return ".html"
+	case "CSV":
+		return ".csv"
+	case "GRAPH_SVG":
+		return ".svg"
+	case "GRAPH_DOT":
+		return ".dot"
+	}
+	return ".txt"
+}
@@ -1,11 +1,400 @@
 package mediainfo
 
-func RenderGraphSVG(reports []Report) string {
-	_ = reports
-	return "<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// graphNode is one box of the stream-relationship graph: the container, a program, an
+// elementary stream, or a sub-stream carried inside another stream.
+type graphNode struct {
+	id       string
+	class    string
+	lines    []string
+	embedded bool
+	children []*graphNode
+
+	// Layout, filled in by layoutGraph.
+	x, y, width, height float64
+}
+
+var graphFillColors = map[string]string{
+	"general": "#dbe6f4",
+	"program": "#ece0f5",
+	"menu":    "#ece0f5",
+	"video":   "#dcefdc",
+	"audio":   "#fbeccc",
+	"text":    "#eeeeee",
+	"image":   "#d9f0ef",
+	"sub":     "#f6dcdc",
+}
+
+// buildStreamGraph links container -> programs (PMT programs in TS/BDAV) -> elementary streams,
+// and hangs embedded sub-streams (captions muxed in video, E-AC-3 JOC objects, Dolby Vision
+// layers) under the stream that carries them.
+func buildStreamGraph(report Report, prefix string) *graphNode {
+	count := 0
+	newNode := func(class string, lines ...string) *graphNode {
+		node := &graphNode{id: fmt.Sprintf("%sn%d", prefix, count), class: class}
+		count++
+		for _, line := range lines {
+			if line = strings.TrimSpace(line); line != "" {
+				node.lines = append(node.lines, line)
+			}
+		}
+		return node
+	}
+
+	name := "General"
+	if report.Ref != "" {
+		name = filepath.Base(report.Ref)
+	}
+	root := newNode("general", name,
+		joinNonEmpty(" ", findField(report.General.Fields, "Format"), findField(report.General.Fields, "Format version")),
+		findField(report.General.Fields, "Duration"))
+
+	type placed struct {
+		stream Stream
+		node   *graphNode
+	}
+	programs := map[string]*graphNode{}
+	byTitle := map[string]*graphNode{}
+	var streams []placed
+	forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, _ int) {
+		title := streamTitle(stream.Kind, index, total)
+		menuID := graphMenuID(stream)
+		if stream.Kind == StreamMenu {
+			if menuID == "" {
+				root.children = append(root.children, newNode("menu", title, findField(stream.Fields, "Format")))
+				return
+			}
+			if programs[menuID] == nil {
+				program := newNode("program", "Program "+menuID,
+					findField(stream.Fields, "Service name"),
+					graphIDLine(findField(stream.Fields, "ID")))
+				programs[menuID] = program
+				root.children = append(root.children, program)
+			}
+			return
+		}
+		node := newNode(strings.ToLower(string(stream.Kind)), graphStreamLines(stream, title)...)
+		node.children = append(node.children, graphSubStreams(stream, newNode)...)
+		byTitle[title] = node
+		// "Muxed in Video #1" names the carrier by its numbered title even when it is alone.
+		byTitle[fmt.Sprintf("%s #%d", stream.Kind, index)] = node
+		streams = append(streams, placed{stream: stream, node: node})
+	})
+
+	for _, entry := range streams {
+		parent := root
+		if carrier, ok := strings.CutPrefix(findField(entry.stream.Fields, "Muxing mode, more info"), "Muxed in "); ok && byTitle[carrier] != nil {
+			parent = byTitle[carrier]
+			entry.node.embedded = true
+		} else if program := programs[graphMenuID(entry.stream)]; program != nil {
+			parent = program
+		}
+		parent.children = append(parent.children, entry.node)
+	}
+	return root
+}
+
+func graphMenuID(stream Stream) string {
+	if value := extractLeadingNumber(findField(stream.Fields, "Menu ID")); value != "" {
+		return value
+	}
+	return stream.JSON["MenuID"]
+}
+
+func graphIDLine(id string) string {
+	if id == "" {
+		return ""
+	}
+	return "ID " + id
+}
+
+func graphStreamLines(stream Stream, title string) []string {
+	format := joinNonEmpty(" ", findField(stream.Fields, "Format"), findField(stream.Fields, "Format profile"))
+	detail := ""
+	switch stream.Kind {
+	case StreamVideo, StreamImage:
+		width := extractLeadingNumber(strings.ReplaceAll(findField(stream.Fields, "Width"), " ", ""))
+		height := extractLeadingNumber(strings.ReplaceAll(findField(stream.Fields, "Height"), " ", ""))
+		if width != "" && height != "" {
+			detail = width + "x" + height
+		}
+		if fps := extractLeadingNumber(findField(stream.Fields, "Frame rate")); fps != "" {
+			detail = joinNonEmpty(" @ ", detail, fps+" fps")
+		}
+	case StreamAudio:
+		detail = joinNonEmpty(", ", findField(stream.Fields, "Channel(s)"), findField(stream.Fields, "Sampling rate"))
+	case StreamText:
+		detail = findField(stream.Fields, "Language")
+	}
+	return []string{title, graphIDLine(findField(stream.Fields, "ID")), format, detail}
+}
+
+// graphSubStreams returns the sub-streams a stream carries inside its own bitstream.
+func graphSubStreams(stream Stream, newNode func(class string, lines ...string) *graphNode) []*graphNode {
+	var subs []*graphNode
+	switch stream.Kind {
+	case StreamVideo:
+		hdr := findField(stream.Fields, "HDR format")
+		if !strings.Contains(hdr, "Dolby Vision") {
+			break
+		}
+		var profile, layers string
+		for part := range strings.SplitSeq(hdr, ",") {
+			part = strings.TrimSpace(part)
+			switch {
+			case strings.HasPrefix(part, "Profile "):
+				profile = part
+			case strings.Contains(part, "BL") || strings.Contains(part, "EL") || strings.Contains(part, "RPU"):
+				layers = part
+			}
+		}
+		dv := newNode("sub", "Dolby Vision", profile)
+		dv.embedded = true
+		for layer := range strings.SplitSeq(layers, "+") {
+			if layer = strings.TrimSpace(layer); layer != "" {
+				child := newNode("sub", graphDolbyVisionLayer(layer))
+				child.embedded = true
+				dv.children = append(dv.children, child)
+			}
+		}
+		subs = append(subs, dv)
+	case StreamAudio:
+		objects := findField(stream.Fields, "Number of dynamic objects")
+		hasJOC := objects != "" || stream.JSON["Format_AdditionalFeatures"] == "JOC" ||
+			strings.Contains(findField(stream.Fields, "Format"), "JOC")
+		if !hasJOC {
+			break
+		}
+		detail := ""
+		if objects != "" {
+			detail = objects + " dynamic objects"
+		}
+		complexity := ""
+		if value := findField(stream.Fields, "Complexity index"); value != "" {
+			complexity = "Complexity index " + value
+		}
+		joc := newNode("sub", "JOC (Dolby Atmos)", detail, complexity)
+		joc.embedded = true
+		subs = append(subs, joc)
+	}
+	return subs
+}
+
+func graphDolbyVisionLayer(layer string) string {
+	switch layer {
+	case "BL":
+		return "Base layer (BL)"
+	case "EL":
+		return "Enhancement layer (EL)"
+	case "RPU":
+		return "Reference processing unit (RPU)"
+	}
+	return layer
+}
+
+func joinNonEmpty(sep string, values ...string) string {
+	parts := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			parts = append(parts, value)
+		}
+	}
+	return strings.Join(parts, sep)
 }
 
+// RenderGraphDOT renders the stream-relationship graph as a Graphviz digraph, one cluster per file.
 func RenderGraphDOT(reports []Report) string {
-	_ = reports
-	return "digraph mediainfo {}"
+	var buf bytes.Buffer
+	buf.WriteString("digraph mediainfo {\n")
+	buf.WriteString("    rankdir=LR;\n")
+	buf.WriteString("    node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\", fontsize=10];\n")
+	buf.WriteString("    edge [color=\"#555555\"];\n")
+	for i, report := range reports {
+		root := buildStreamGraph(report, fmt.Sprintf("f%d_", i))
+		fmt.Fprintf(&buf, "    subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&buf, "        label=%s;\n", dotQuote(report.Ref))
+		writeDOTNodes(&buf, root)
+		writeDOTEdges(&buf, root)
+		buf.WriteString("    }\n")
+	}
+	buf.WriteString("}\n")
+	return buf.String()
+}
+
+func writeDOTNodes(buf *bytes.Buffer, node *graphNode) {
+	fmt.Fprintf(buf, "        %s [label=%s, fillcolor=%s];\n", node.id, dotQuote(strings.Join(node.lines, "\n")), dotQuote(graphFillColors[node.class]))
+	for _, child := range node.children {
+		writeDOTNodes(buf, child)
+	}
+}
+
+func writeDOTEdges(buf *bytes.Buffer, node *graphNode) {
+	for _, child := range node.children {
+		if child.embedded {
+			fmt.Fprintf(buf, "        %s -> %s [style=dashed];\n", node.id, child.id)
+		} else {
+			fmt.Fprintf(buf, "        %s -> %s;\n", node.id, child.id)
+		}
+		writeDOTEdges(buf, child)
+	}
+}
+
+// dotQuote returns a double-quoted DOT string; newlines become centered line breaks.
+func dotQuote(value string) string {
+	value = strings.ReplaceAll(value, "\\", "\\\\")
+	value = strings.ReplaceAll(value, "\"", "\\\"")
+	value = strings.ReplaceAll(value, "\n", "\\n")
+	return "\"" + value + "\""
+}
+
+const (
+	graphCharWidth  = 7.0
+	graphLineHeight = 15.0
+	graphPadX       = 10.0
+	graphPadY       = 8.0
+	graphColumnGap  = 60.0
+	graphRowGap     = 14.0
+	graphMargin     = 20.0
+	graphTitleSpace = 28.0
+)
+
+// RenderGraphSVG renders the stream-relationship graph as a standalone SVG, laid out left to
+// right as a tree (one column per depth) without relying on Graphviz.
+func RenderGraphSVG(reports []Report) string {
+	type laidOut struct {
+		title string
+		root  *graphNode
+		top   float64
+	}
+	var graphs []laidOut
+	top, width := graphMargin, 0.0
+	for i, report := range reports {
+		root := buildStreamGraph(report, fmt.Sprintf("f%d_", i))
+		graphWidth, graphHeight := layoutGraph(root, graphMargin, top+graphTitleSpace)
+		graphs = append(graphs, laidOut{title: report.Ref, root: root, top: top})
+		width = max(width, graphWidth)
+		top += graphTitleSpace + graphHeight + graphMargin
+	}
+	width += 2 * graphMargin
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<svg xmlns=\"http://www.w3.org/2000/svg\" width=\"%s\" height=\"%s\" viewBox=\"0 0 %s %s\">\n",
+		svgNumber(width), svgNumber(top), svgNumber(width), svgNumber(top))
+	buf.WriteString("<defs><marker id=\"arrow\" viewBox=\"0 0 10 10\" refX=\"10\" refY=\"5\" markerWidth=\"6\" markerHeight=\"6\" orient=\"auto\"><path d=\"M 0 0 L 10 5 L 0 10 z\" fill=\"#555555\"/></marker></defs>\n")
+	buf.WriteString("<rect width=\"100%\" height=\"100%\" fill=\"#ffffff\"/>\n")
+	for _, graph := range graphs {
+		fmt.Fprintf(&buf, "<text x=\"%s\" y=\"%s\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"13\" font-weight=\"bold\">%s</text>\n",
+			svgNumber(graphMargin), svgNumber(graph.top+16), xmlEscape(graph.title))
+		writeSVGEdges(&buf, graph.root)
+		writeSVGNodes(&buf, graph.root)
+	}
+	buf.WriteString("</svg>\n")
+	return buf.String()
+}
+
+// layoutGraph assigns box sizes and positions: x by depth column, y so that leaves stack
+// downward and each parent is centered on its children. It returns the graph's width and height.
+func layoutGraph(root *graphNode, left, top float64) (float64, float64) {
+	var columns []float64
+	var measure func(node *graphNode, depth int)
+	measure = func(node *graphNode, depth int) {
+		longest := 0
+		for _, line := range node.lines {
+			longest = max(longest, len([]rune(line)))
+		}
+		node.width = float64(longest)*graphCharWidth + 2*graphPadX
+		node.height = float64(len(node.lines))*graphLineHeight + 2*graphPadY
+		if depth == len(columns) {
+			columns = append(columns, 0)
+		}
+		columns[depth] = max(columns[depth], node.width)
+		for _, child := range node.children {
+			measure(child, depth+1)
+		}
+	}
+	measure(root, 0)
+	offsets := make([]float64, len(columns))
+	x := left
+	for i, columnWidth := range columns {
+		offsets[i] = x
+		x += columnWidth + graphColumnGap
+	}
+
+	cursor := top
+	var place func(node *graphNode, depth int)
+	place = func(node *graphNode, depth int) {
+		node.x = offsets[depth]
+		start := cursor
+		if len(node.children) == 0 {
+			node.y = cursor
+			cursor += node.height + graphRowGap
+			return
+		}
+		for _, child := range node.children {
+			place(child, depth+1)
+		}
+		first, last := node.children[0], node.children[len(node.children)-1]
+		center := (first.y + first.height/2 + last.y + last.height/2) / 2
+		node.y = center - node.height/2
+		if node.y < start {
+			// The parent is taller than its subtree: push the subtree down under it.
+			shift := start - node.y
+			shiftGraph(node, shift)
+			cursor += shift
+		}
+		cursor = max(cursor, node.y+node.height+graphRowGap)
+	}
+	place(root, 0)
+	return x - graphColumnGap - left, cursor - graphRowGap - top
+}
+
+func shiftGraph(node *graphNode, dy float64) {
+	node.y += dy
+	for _, child := range node.children {
+		shiftGraph(child, dy)
+	}
+}
+
+func writeSVGEdges(buf *bytes.Buffer, node *graphNode) {
+	for _, child := range node.children {
+		x1, y1 := node.x+node.width, node.y+node.height/2
+		x2, y2 := child.x, child.y+child.height/2
+		mid := (x1 + x2) / 2
+		dash := ""
+		if child.embedded {
+			dash = " stroke-dasharray=\"5,3\""
+		}
+		fmt.Fprintf(buf, "<path d=\"M %s %s C %s %s, %s %s, %s %s\" fill=\"none\" stroke=\"#555555\"%s marker-end=\"url(#arrow)\"/>\n",
+			svgNumber(x1), svgNumber(y1), svgNumber(mid), svgNumber(y1), svgNumber(mid), svgNumber(y2), svgNumber(x2), svgNumber(y2), dash)
+		writeSVGEdges(buf, child)
+	}
+}
+
+func writeSVGNodes(buf *bytes.Buffer, node *graphNode) {
+	fmt.Fprintf(buf, "<g id=\"%s\">\n", node.id)
+	fmt.Fprintf(buf, "<rect x=\"%s\" y=\"%s\" width=\"%s\" height=\"%s\" rx=\"6\" fill=\"%s\" stroke=\"#333333\"/>\n",
+		svgNumber(node.x), svgNumber(node.y), svgNumber(node.width), svgNumber(node.height), graphFillColors[node.class])
+	for i, line := range node.lines {
+		weight := ""
+		if i == 0 {
+			weight = " font-weight=\"bold\""
+		}
+		fmt.Fprintf(buf, "<text x=\"%s\" y=\"%s\" font-family=\"Helvetica, Arial, sans-serif\" font-size=\"11\"%s>%s</text>\n",
+			svgNumber(node.x+graphPadX), svgNumber(node.y+graphPadY+float64(i+1)*graphLineHeight-4), weight, xmlEscape(line))
+	}
+	buf.WriteString("</g>\n")
+	for _, child := range node.children {
+		writeSVGNodes(buf, child)
+	}
+}
+
+func svgNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
 }
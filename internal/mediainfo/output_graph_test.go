@@ -0,0 +1,152 @@
+package mediainfo
+
+import (
+	"encoding/xml"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func graphTestReport() Report {
+	return Report{
+		Ref: "capture.ts",
+		General: Stream{Kind: StreamGeneral, Fields: []Field{
+			{Name: "Format", Value: "MPEG-TS"},
+		}},
+		Streams: []Stream{
+			{Kind: StreamVideo, Fields: []Field{
+				{Name: "ID", Value: "256 (0x100)"},
+				{Name: "Menu ID", Value: "1 (0x1)"},
+				{Name: "Format", Value: "HEVC"},
+				{Name: "HDR format", Value: "Dolby Vision, Version 1.0, Profile 7.6, dvhe.07.06, BL+EL+RPU, no metadata compression"},
+			}},
+			{Kind: StreamAudio, Fields: []Field{
+				{Name: "ID", Value: "257 (0x101)"},
+				{Name: "Menu ID", Value: "1 (0x1)"},
+				{Name: "Format", Value: "E-AC-3 JOC"},
+				{Name: "Number of dynamic objects", Value: "15"},
+			}},
+			{Kind: StreamAudio, Fields: []Field{
+				{Name: "ID", Value: "513 (0x201)"},
+				{Name: "Menu ID", Value: "2 (0x2)"},
+				{Name: "Format", Value: "AC-3"},
+			}},
+			{Kind: StreamText, Fields: []Field{
+				{Name: "ID", Value: "256-608"},
+				{Name: "Menu ID", Value: "1 (0x1)"},
+				{Name: "Format", Value: "EIA-608"},
+				{Name: "Muxing mode, more info", Value: "Muxed in Video #1"},
+			}},
+			{Kind: StreamMenu, Fields: []Field{
+				{Name: "ID", Value: "4096 (0x1000)"},
+				{Name: "Menu ID", Value: "1 (0x1)"},
+				{Name: "Service name", Value: "News \"HD\""},
+			}},
+			{Kind: StreamMenu, Fields: []Field{
+				{Name: "ID", Value: "4097 (0x1001)"},
+				{Name: "Menu ID", Value: "2 (0x2)"},
+			}},
+		},
+	}
+}
+
+func TestBuildStreamGraph(t *testing.T) {
+	root := buildStreamGraph(graphTestReport(), "")
+	if len(root.children) != 2 {
+		t.Fatalf("container has %d children, want 2 programs", len(root.children))
+	}
+	program1, program2 := root.children[0], root.children[1]
+	if program1.lines[0] != "Program 1" || len(program1.children) != 2 {
+		t.Fatalf("program 1=%v with %d streams, want video and audio", program1.lines, len(program1.children))
+	}
+	if program2.lines[0] != "Program 2" || len(program2.children) != 1 {
+		t.Fatalf("program 2=%v with %d streams, want one audio", program2.lines, len(program2.children))
+	}
+
+	video := program1.children[0]
+	var names []string
+	for _, child := range video.children {
+		names = append(names, child.lines[0])
+		if !child.embedded {
+			t.Fatalf("%s under video is not marked embedded", child.lines[0])
+		}
+	}
+	if strings.Join(names, ",") != "Dolby Vision,Text" {
+		t.Fatalf("video sub-streams=%v, want Dolby Vision and the caption", names)
+	}
+	if layers := video.children[0].children; len(layers) != 3 || layers[2].lines[0] != "Reference processing unit (RPU)" {
+		t.Fatalf("Dolby Vision layers=%v", layers)
+	}
+	audio := program1.children[1]
+	if len(audio.children) != 1 || audio.children[0].lines[1] != "15 dynamic objects" {
+		t.Fatalf("E-AC-3 JOC sub-stream missing: %+v", audio.children)
+	}
+}
+
+func TestRenderGraphDOT(t *testing.T) {
+	out := RenderGraphDOT([]Report{graphTestReport()})
+	if strings.Count(out, "{") != strings.Count(out, "}") {
+		t.Fatalf("unbalanced braces:\n%s", out)
+	}
+	if !strings.Contains(out, `News \"HD\"`) {
+		t.Fatalf("label quotes are not escaped:\n%s", out)
+	}
+	declared := map[string]bool{}
+	for _, match := range regexp.MustCompile(`(?m)^\s+(f0_n\d+) \[label=`).FindAllStringSubmatch(out, -1) {
+		declared[match[1]] = true
+	}
+	edges := regexp.MustCompile(`(?m)^\s+(f0_n\d+) -> (f0_n\d+)`).FindAllStringSubmatch(out, -1)
+	if len(edges) != len(declared)-1 {
+		t.Fatalf("%d edges for %d nodes; a tree needs nodes-1", len(edges), len(declared))
+	}
+	for _, edge := range edges {
+		if !declared[edge[1]] || !declared[edge[2]] {
+			t.Fatalf("edge %s -> %s references an undeclared node", edge[1], edge[2])
+		}
+	}
+	if !strings.Contains(out, "[style=dashed]") {
+		t.Fatalf("embedded sub-streams should use dashed edges")
+	}
+}
+
+func TestRenderGraphSVGLayout(t *testing.T) {
+	out := RenderGraphSVG([]Report{graphTestReport(), graphTestReport()})
+	dec := xml.NewDecoder(strings.NewReader(out))
+	rects := 0
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("invalid SVG: %v", err)
+		}
+		if start, ok := tok.(xml.StartElement); ok && start.Name.Local == "rect" {
+			rects++
+		}
+	}
+	// One background rect plus 12 boxes per graph: container, 2 programs, 4 streams, DV + 3 layers, JOC.
+	if rects != 1+2*12 {
+		t.Fatalf("rect count=%d, want %d", rects, 1+2*12)
+	}
+
+	root := buildStreamGraph(graphTestReport(), "")
+	layoutGraph(root, 0, 0)
+	var boxes []*graphNode
+	var collect func(node *graphNode)
+	collect = func(node *graphNode) {
+		boxes = append(boxes, node)
+		for _, child := range node.children {
+			collect(child)
+		}
+	}
+	collect(root)
+	for i, a := range boxes {
+		for _, b := range boxes[i+1:] {
+			if a.x < b.x+b.width && b.x < a.x+a.width && a.y < b.y+b.height && b.y < a.y+a.height {
+				t.Fatalf("boxes %v and %v overlap", a.lines, b.lines)
+			}
+		}
+	}
+}
@@ -3,16 +3,23 @@ package mediainfo
 import (
 	"bytes"
 	"html"
+	"strings"
 )
 
 func RenderHTML(reports []Report) string {
+	return RenderHTMLTranslated(reports, nil)
+}
+
+// RenderHTMLTranslated renders HTML output with labels and values passed through tr.
+func RenderHTMLTranslated(reports []Report, tr *Translation) string {
 	var buf bytes.Buffer
 	buf.WriteString("<html><head><meta charset=\"utf-8\"/></head><body>")
 	for _, report := range reports {
 		buf.WriteString("<table>")
-		buf.WriteString(renderHTMLStream("General", report.General))
+		buf.WriteString(renderHTMLStream(tr.Translate("General"), report.General, tr))
 		for _, entry := range enumerateStreams(report.Streams) {
-			buf.WriteString(renderHTMLStream(entry.Title, entry.Stream))
+			title := tr.Translate(string(entry.Stream.Kind)) + strings.TrimPrefix(entry.Title, string(entry.Stream.Kind))
+			buf.WriteString(renderHTMLStream(title, entry.Stream, tr))
 		}
 		buf.WriteString("</table>")
 	}
@@ -20,8 +27,9 @@ func RenderHTML(reports []Report) string {
 	return buf.String()
 }
 
-func renderHTMLStream(title string, stream Stream) string {
-	fields := orderFieldsForJSON(stream.Kind, stream.Fields)
+func renderHTMLStream(title string, stream Stream, tr *Translation) string {
+	stream.Fields = orderFieldsForJSON(stream.Kind, stream.Fields)
+	fields := tr.stream(stream).Fields
 	var buf bytes.Buffer
 	buf.WriteString("<tr><th colspan=\"2\">")
 	buf.WriteString(html.EscapeString(title))
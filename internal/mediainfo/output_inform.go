@@ -0,0 +1,353 @@
+package mediainfo
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// informKinds is the order stream sections are rendered in, matching MediaInfo.
+var informKinds = []StreamKind{StreamGeneral, StreamVideo, StreamAudio, StreamText, StreamOther, StreamImage, StreamMenu}
+
+// InformTemplate is a parsed MediaInfo --Inform/--Output template ("Video;%Width%x%Height%\n").
+type InformTemplate struct {
+	sections map[string]string
+}
+
+// ParseInformTemplate parses one "Section;content" entry per line. Sections are stream kinds
+// (General, Video, Audio, Text, Other, Image, Menu), their _Begin/_Middle/_End separators, and
+// Page_Begin/Page_Middle/Page_End (around and between files) and File_Begin/File_End.
+func ParseInformTemplate(text string) (InformTemplate, error) {
+	tmpl := InformTemplate{sections: map[string]string{}}
+	text = strings.TrimPrefix(text, "\ufeff")
+	for line := range strings.SplitSeq(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		name, body, ok := strings.Cut(line, ";")
+		if !ok {
+			return InformTemplate{}, fmt.Errorf("invalid template line (want Section;content): %s", line)
+		}
+		section, ok := normalizeInformSection(strings.TrimSpace(name))
+		if !ok {
+			return InformTemplate{}, fmt.Errorf("unknown template section: %s", name)
+		}
+		tmpl.sections[section] = body
+	}
+	if len(tmpl.sections) == 0 {
+		return InformTemplate{}, fmt.Errorf("empty output template")
+	}
+	return tmpl, nil
+}
+
+func normalizeInformSection(name string) (string, bool) {
+	base, suffix, _ := strings.Cut(name, "_")
+	switch {
+	case strings.EqualFold(base, "Page") || strings.EqualFold(base, "File"):
+		allowed := []string{"Begin", "End"}
+		if strings.EqualFold(base, "Page") {
+			allowed = []string{"Begin", "Middle", "End"}
+		}
+		for _, value := range allowed {
+			if strings.EqualFold(suffix, value) {
+				return capitalizeASCII(base) + "_" + value, true
+			}
+		}
+		return "", false
+	}
+	for _, kind := range informKinds {
+		if !strings.EqualFold(base, string(kind)) {
+			continue
+		}
+		if suffix == "" {
+			return string(kind), true
+		}
+		for _, allowed := range []string{"Begin", "Middle", "End"} {
+			if strings.EqualFold(suffix, allowed) {
+				return string(kind) + "_" + allowed, true
+			}
+		}
+	}
+	return "", false
+}
+
+func capitalizeASCII(value string) string {
+	if value == "" {
+		return value
+	}
+	return strings.ToUpper(value[:1]) + strings.ToLower(value[1:])
+}
+
+// RenderInform renders reports through tmpl the way MediaInfo's --Inform does.
+func RenderInform(reports []Report, tmpl InformTemplate) string {
+	var buf bytes.Buffer
+	noValues := informValues{}
+	buf.WriteString(tmpl.expand("Page_Begin", noValues))
+	for i, report := range reports {
+		if i > 0 {
+			buf.WriteString(tmpl.expand("Page_Middle", noValues))
+		}
+		general := newInformGeneralValues(report)
+		buf.WriteString(tmpl.expand("File_Begin", general))
+
+		byKind := map[StreamKind][]informValues{StreamGeneral: {general}}
+		containerFormat := findField(report.General.Fields, "Format")
+		sorted := orderTracks(report.Streams)
+		forEachStreamWithKindIndex(sorted, func(stream Stream, index, total, order int) {
+			typeOrder := 0
+			if total > 1 {
+				typeOrder = index
+			}
+			values := newInformValues(stream.Kind, stream.Fields, buildJSONStreamFields(stream, order, typeOrder, containerFormat))
+			values.raw["StreamKindID"] = strconv.Itoa(index - 1)
+			values.raw["StreamKindPos"] = strconv.Itoa(typeOrder)
+			values.raw["StreamCount"] = strconv.Itoa(total)
+			byKind[stream.Kind] = append(byKind[stream.Kind], values)
+		})
+
+		for _, kind := range informKinds {
+			streams := byKind[kind]
+			if len(streams) == 0 || !tmpl.hasKind(kind) {
+				continue
+			}
+			buf.WriteString(tmpl.expand(string(kind)+"_Begin", noValues))
+			for j, values := range streams {
+				if j > 0 {
+					buf.WriteString(tmpl.expand(string(kind)+"_Middle", noValues))
+				}
+				buf.WriteString(tmpl.expand(string(kind), values))
+			}
+			buf.WriteString(tmpl.expand(string(kind)+"_End", noValues))
+		}
+		buf.WriteString(tmpl.expand("File_End", general))
+	}
+	buf.WriteString(tmpl.expand("Page_End", noValues))
+	return buf.String()
+}
+
+func (t InformTemplate) hasKind(kind StreamKind) bool {
+	for _, suffix := range []string{"", "_Begin", "_Middle", "_End"} {
+		if _, ok := t.sections[string(kind)+suffix]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (t InformTemplate) expand(section string, values informValues) string {
+	body, ok := t.sections[section]
+	if !ok {
+		return ""
+	}
+	out, _, _ := expandInform(body, values)
+	return out
+}
+
+// informValues resolves %Field% (raw JSON values) and %Field/String% (text values).
+type informValues struct {
+	raw      map[string]string
+	str      map[string]string
+	duration float64
+	fps      float64
+}
+
+func newInformGeneralValues(report Report) informValues {
+	values := newInformValues(StreamGeneral, report.General.Fields, buildJSONGeneralFields(report))
+	ref := report.Ref
+	if ref == "" {
+		ref = findField(report.General.Fields, "Complete name")
+	}
+	if ref != "" {
+		base := filepath.Base(ref)
+		ext := filepath.Ext(base)
+		values.raw["CompleteName"] = ref
+		values.raw["FolderName"] = filepath.Dir(ref)
+		values.raw["FileNameExtension"] = base
+		values.raw["FileName"] = strings.TrimSuffix(base, ext)
+		values.raw["FileExtension"] = strings.TrimPrefix(ext, ".")
+	}
+	values.raw["StreamKindID"] = "0"
+	values.raw["StreamCount"] = "1"
+	return values
+}
+
+func newInformValues(kind StreamKind, fields []Field, jsonFields []jsonKV) informValues {
+	values := informValues{raw: map[string]string{}, str: map[string]string{}}
+	for _, field := range jsonFields {
+		if field.Key == "extra" && field.Raw {
+			if parsed, err := parseOrderedJSON(field.Val); err == nil && parsed.kind == orderedObject {
+				for _, kv := range parsed.obj {
+					if kv.val.kind == orderedString && values.raw[kv.key] == "" {
+						values.raw[kv.key] = kv.val.str
+					}
+				}
+			}
+			continue
+		}
+		if field.Raw {
+			continue
+		}
+		values.raw[strings.TrimPrefix(field.Key, "@")] = field.Val
+	}
+	// Map every text field to the JSON key it produces so %Key/String% finds its display value.
+	for i, key := range textFieldJSONKeys(kind, fields) {
+		if _, exists := values.str[key]; key != "" && !exists {
+			values.str[key] = fields[i].Value
+		}
+	}
+	values.duration, _ = strconv.ParseFloat(values.raw["Duration"], 64)
+	values.fps, _ = strconv.ParseFloat(values.raw["FrameRate"], 64)
+	return values
+}
+
+func (v informValues) lookup(name string) string {
+	base, variant, hasVariant := strings.Cut(name, "/")
+	if !hasVariant {
+		if value := v.raw[base]; value != "" {
+			// Durations and delays are milliseconds in templates, seconds in JSON.
+			return fullRawValue(base, value)
+		}
+		return v.str[base]
+	}
+	if base == "Duration" && v.duration > 0 {
+		switch variant {
+		case "String1":
+			return formatDurationString1(v.duration)
+		case "String2":
+			return formatDurationString2(v.duration)
+		case "String3":
+			return formatDurationString3(v.duration)
+		case "String4":
+			return formatDurationString4(v.duration, v.fps)
+		case "String5":
+			return formatDurationString5(v.duration, v.fps)
+		}
+	}
+	if strings.HasPrefix(variant, "String") {
+		if value := v.str[base]; value != "" {
+			return value
+		}
+		return v.raw[base]
+	}
+	return ""
+}
+
+// expandInform evaluates a template body: escapes (\n, \r, \t, \\, \,), %Field% substitutions,
+// $if(cond,then[,else]) and [...] blocks that are dropped when any field inside is empty.
+// It also returns how many fields were referenced and how many of them were empty.
+func expandInform(body string, values informValues) (string, int, int) {
+	var out strings.Builder
+	seen, empty := 0, 0
+	for i := 0; i < len(body); i++ {
+		c := body[i]
+		switch {
+		case c == '\\' && i+1 < len(body):
+			i++
+			switch body[i] {
+			case 'n':
+				out.WriteByte('\n')
+			case 'r':
+				out.WriteByte('\r')
+			case 't':
+				out.WriteByte('\t')
+			default:
+				out.WriteByte(body[i])
+			}
+		case c == '%':
+			end := strings.IndexByte(body[i+1:], '%')
+			if end < 0 {
+				out.WriteString(body[i:])
+				return out.String(), seen, empty
+			}
+			value := values.lookup(body[i+1 : i+1+end])
+			seen++
+			if value == "" {
+				empty++
+			}
+			out.WriteString(value)
+			i += end + 1
+		case c == '$' && strings.HasPrefix(body[i:], "$if("):
+			args, next, ok := splitInformArgs(body, i+len("$if("))
+			if !ok {
+				out.WriteString(body[i:])
+				return out.String(), seen, empty
+			}
+			cond, _, _ := expandInform(args[0], values)
+			branch := ""
+			if strings.TrimSpace(cond) != "" && len(args) > 1 {
+				branch = args[1]
+			} else if strings.TrimSpace(cond) == "" && len(args) > 2 {
+				branch = args[2]
+			}
+			expanded, s, e := expandInform(branch, values)
+			seen += s
+			empty += e
+			out.WriteString(expanded)
+			i = next
+		case c == '[':
+			end := matchingInformBracket(body, i)
+			if end < 0 {
+				out.WriteByte(c)
+				continue
+			}
+			expanded, s, e := expandInform(body[i+1:end], values)
+			if s > 0 && e == 0 {
+				out.WriteString(expanded)
+			}
+			i = end
+		default:
+			out.WriteByte(c)
+		}
+	}
+	return out.String(), seen, empty
+}
+
+// splitInformArgs splits the comma-separated arguments of a $if( call starting at start,
+// honoring escapes and nested parentheses/brackets. It returns the index of the closing ')'.
+func splitInformArgs(body string, start int) ([]string, int, bool) {
+	var args []string
+	depth := 0
+	argStart := start
+	for i := start; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '(', '[':
+			depth++
+		case ']':
+			depth--
+		case ')':
+			if depth == 0 {
+				return append(args, body[argStart:i]), i, true
+			}
+			depth--
+		case ',':
+			if depth == 0 {
+				args = append(args, body[argStart:i])
+				argStart = i + 1
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+func matchingInformBracket(body string, start int) int {
+	depth := 0
+	for i := start; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
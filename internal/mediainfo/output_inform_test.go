@@ -0,0 +1,126 @@
+package mediainfo
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+func informTestReport() Report {
+	return Report{
+		Ref: "/media/Show/episode.mkv",
+		General: Stream{
+			Kind: StreamGeneral,
+			Fields: []Field{
+				{Name: "Complete name", Value: "/media/Show/episode.mkv"},
+				{Name: "Format", Value: "Matroska"},
+				{Name: "Duration", Value: "1 h 2 min 3 s"},
+			},
+			JSON: map[string]string{"Duration": "3723.456"},
+		},
+		Streams: []Stream{
+			{Kind: StreamVideo, Fields: []Field{
+				{Name: "Format", Value: "AVC"},
+				{Name: "Width", Value: "1 920 pixels"},
+				{Name: "Height", Value: "1 080 pixels"},
+				{Name: "Frame rate", Value: "23.976 (24000/1001) FPS"},
+			}},
+			{Kind: StreamAudio, Fields: []Field{{Name: "Format", Value: "AAC"}, {Name: "Language", Value: "English"}}},
+			{Kind: StreamAudio, Fields: []Field{{Name: "Format", Value: "AC-3"}}},
+		},
+		virtual: true,
+	}
+}
+
+func TestRenderInformTemplate(t *testing.T) {
+	cases := []struct {
+		name     string
+		template string
+		want     string
+	}{
+		{
+			name:     "video fields",
+			template: `Video;%Width%x%Height% %FrameRate%\n`,
+			want:     "1920x1080 23.976\n",
+		},
+		{
+			name:     "string variants",
+			template: `General;%FileName%.%FileExtension% %Duration/String3% \[%Duration/String%\]`,
+			want:     "episode.mkv 01:02:03.456 [1 h 2 min 3 s]",
+		},
+		{
+			name:     "raw duration in ms",
+			template: `General;%Duration%`,
+			want:     "3723456",
+		},
+		{
+			name:     "width string",
+			template: `Video;%Width/String%`,
+			want:     "1 920 pixels",
+		},
+		{
+			name:     "if and brackets",
+			template: "Audio_Begin;(\nAudio_Middle;,\nAudio;%Format%$if(%Language%, \\(%Language%\\))[ %Title%]\nAudio_End;)\\n",
+			want:     "(AAC (English),AC-3)\n",
+		},
+		{
+			name:     "if else",
+			template: `Audio;$if(%Language%,%Language%,und);`,
+			want:     "English;und;",
+		},
+		{
+			name:     "page and file sections",
+			template: "Page_Begin;<\nFile_Begin;%FileNameExtension%:\nGeneral;%Format%\nFile_End;|\nPage_End;>",
+			want:     "<episode.mkv:Matroska|>",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			tmpl, err := ParseInformTemplate(tc.template)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if got := RenderInform([]Report{informTestReport()}, tmpl); got != tc.want {
+				t.Fatalf("got %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestRenderInformPageMiddle(t *testing.T) {
+	tmpl, err := ParseInformTemplate("Page_Middle;\\n\nGeneral;%Format%")
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if got := RenderInform([]Report{informTestReport(), informTestReport()}, tmpl); got != "Matroska\nMatroska" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestParseInformTemplateErrors(t *testing.T) {
+	for _, text := range []string{"", "Bogus;%Width%", "no section here"} {
+		if _, err := ParseInformTemplate(text); err == nil {
+			t.Fatalf("ParseInformTemplate(%q) succeeded, want error", text)
+		}
+	}
+}
+
+func TestRenderInformSample(t *testing.T) {
+	report, err := AnalyzeFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	tmpl, err := ParseInformTemplate(`General;%Format% %FileSize%`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	info, err := os.Stat(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("stat: %v", err)
+	}
+	want := "Matroska " + strconv.FormatInt(info.Size(), 10)
+	if got := RenderInform([]Report{report}, tmpl); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
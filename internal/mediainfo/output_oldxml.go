@@ -0,0 +1,68 @@
+package mediainfo
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// oldXMLVersion is the schema generation legacy OLDXML consumers key on, not our own version.
+const oldXMLVersion = "0.7"
+
+// RenderOLDXML renders the MediaInfo 0.7.x XML layout: one <File> per report and one <track> per
+// stream, with element names derived from the text field names and the text display values.
+func RenderOLDXML(reports []Report) string {
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString(fmt.Sprintf("<Mediainfo version=\"%s\">\n", oldXMLVersion))
+	for _, report := range reports {
+		buf.WriteString("<File>\n")
+		writeOLDXMLTrack(&buf, report.General, 0)
+		forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, _ int) {
+			streamID := 0
+			if total > 1 {
+				streamID = index
+			}
+			writeOLDXMLTrack(&buf, stream, streamID)
+		})
+		buf.WriteString("</File>\n")
+	}
+	buf.WriteString("</Mediainfo>\n")
+	return buf.String()
+}
+
+func writeOLDXMLTrack(buf *bytes.Buffer, stream Stream, streamID int) {
+	buf.WriteString(fmt.Sprintf("<track type=\"%s\"", xmlEscapeAttr(string(stream.Kind))))
+	if streamID > 0 {
+		buf.WriteString(fmt.Sprintf(" streamid=\"%d\"", streamID))
+	}
+	buf.WriteString(">\n")
+	for _, field := range stream.Fields {
+		name := oldXMLFieldName(field.Name)
+		buf.WriteString(fmt.Sprintf("<%s>%s</%s>\n", name, xmlEscape(field.Value), name))
+	}
+	buf.WriteString("</track>\n")
+}
+
+// oldXMLFieldName mirrors MediaInfo 0.7: every character outside [A-Za-z0-9_] becomes "_"
+// ("Channel(s)" -> "Channel_s_", "Format settings, CABAC" -> "Format_settings__CABAC"), and
+// names that would start with a digit, such as chapter timestamps, get a leading "_".
+func oldXMLFieldName(name string) string {
+	var b strings.Builder
+	b.Grow(len(name) + 1)
+	if name != "" && name[0] >= '0' && name[0] <= '9' {
+		b.WriteByte('_')
+	}
+	for _, r := range name {
+		switch {
+		case r >= 'A' && r <= 'Z', r >= 'a' && r <= 'z', r >= '0' && r <= '9', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	if b.Len() == 0 {
+		return "Field"
+	}
+	return b.String()
+}
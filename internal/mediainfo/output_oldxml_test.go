@@ -0,0 +1,77 @@
+package mediainfo
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestOLDXMLFieldName(t *testing.T) {
+	cases := map[string]string{
+		"Complete name":          "Complete_name",
+		"Overall bit rate":       "Overall_bit_rate",
+		"Channel(s)":             "Channel_s_",
+		"Format settings, CABAC": "Format_settings__CABAC",
+		"Bits/(Pixel*Frame)":     "Bits__Pixel_Frame_",
+		"00:01:30.000":           "_00_01_30_000",
+	}
+	for in, want := range cases {
+		if got := oldXMLFieldName(in); got != want {
+			t.Fatalf("oldXMLFieldName(%q)=%q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestRenderOLDXML(t *testing.T) {
+	report := Report{
+		Ref: "movie.mkv",
+		General: Stream{Kind: StreamGeneral, Fields: []Field{
+			{Name: "Complete name", Value: "movie.mkv"},
+			{Name: "Overall bit rate", Value: "5 000 kb/s"},
+		}},
+		Streams: []Stream{
+			{Kind: StreamVideo, Fields: []Field{{Name: "Format", Value: "AVC"}}},
+			{Kind: StreamAudio, Fields: []Field{{Name: "Channel(s)", Value: "6 channels"}}},
+			{Kind: StreamAudio, Fields: []Field{{Name: "Title", Value: "Commentary & notes"}}},
+			{Kind: StreamMenu, Fields: []Field{{Name: "00:00:00.000", Value: "Chapter 1"}}},
+		},
+	}
+	out := RenderOLDXML([]Report{report})
+
+	var doc struct {
+		XMLName xml.Name `xml:"Mediainfo"`
+		Version string   `xml:"version,attr"`
+		Files   []struct {
+			Tracks []struct {
+				Type     string `xml:"type,attr"`
+				StreamID string `xml:"streamid,attr"`
+				Inner    string `xml:",innerxml"`
+			} `xml:"track"`
+		} `xml:"File"`
+	}
+	if err := xml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("invalid XML: %v\n%s", err, out)
+	}
+	if doc.Version != oldXMLVersion || len(doc.Files) != 1 || len(doc.Files[0].Tracks) != 5 {
+		t.Fatalf("unexpected layout:\n%s", out)
+	}
+	tracks := doc.Files[0].Tracks
+	var ids []string
+	for _, track := range tracks {
+		ids = append(ids, track.Type+":"+track.StreamID)
+	}
+	if got := strings.Join(ids, ","); got != "General:,Video:,Audio:1,Audio:2,Menu:" {
+		t.Fatalf("tracks=%s", got)
+	}
+	for _, want := range []string{
+		"<Complete_name>movie.mkv</Complete_name>",
+		"<Overall_bit_rate>5 000 kb/s</Overall_bit_rate>",
+		"<Channel_s_>6 channels</Channel_s_>",
+		"<Title>Commentary &amp; notes</Title>",
+		"<_00_00_00_000>Chapter 1</_00_00_00_000>",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %s in:\n%s", want, out)
+		}
+	}
+}
@@ -1,6 +1,319 @@
 package mediainfo
 
+import (
+	"bytes"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	pbcoreNS        = "http://www.pbcore.org/PBCore/PBCoreNamespace.html"
+	pbcore1Schema   = "https://www.pbcore.org/xsd/pbcore-1.3.xsd"
+	pbcore2Schema   = "https://raw.githubusercontent.com/WGBH/PBCore_2.1/master/pbcore-2.1.xsd"
+	pbcoreTrackIDBy = "ID (Mediainfo)"
+)
+
+// RenderPBCore renders a PBCore 1.x description document with one pbcoreInstantiation per file.
 func RenderPBCore(reports []Report) string {
-	_ = reports
-	return "<pbcoreDescriptionDocument></pbcoreDescriptionDocument>"
+	root := newPBCoreRoot("PBCoreDescriptionDocument", pbcore1Schema)
+	addPBCoreDescription(root, reports)
+	for _, report := range reports {
+		root.add(buildPBCore1Instantiation(report))
+	}
+	return renderPBCoreXML(root)
+}
+
+// RenderPBCore2 renders a PBCore 2.1 pbcoreInstantiationDocument for a single file, or a
+// pbcoreDescriptionDocument holding one pbcoreInstantiation per file otherwise.
+func RenderPBCore2(reports []Report) string {
+	if len(reports) == 1 {
+		root := newPBCoreRoot("pbcoreInstantiationDocument", pbcore2Schema)
+		addPBCore2Instantiation(root, reports[0])
+		return renderPBCoreXML(root)
+	}
+	root := newPBCoreRoot("pbcoreDescriptionDocument", pbcore2Schema)
+	addPBCoreDescription(root, reports)
+	for _, report := range reports {
+		addPBCore2Instantiation(root.add(newXMLNode("pbcoreInstantiation")), report)
+	}
+	return renderPBCoreXML(root)
+}
+
+func newPBCoreRoot(name, schema string) *xmlNode {
+	return newXMLNode(name,
+		xmlAttr{name: "xmlns", value: pbcoreNS},
+		xmlAttr{name: "xmlns:xsi", value: "http://www.w3.org/2001/XMLSchema-instance"},
+		xmlAttr{name: "xsi:schemaLocation", value: pbcoreNS + " " + schema},
+	)
+}
+
+func renderPBCoreXML(root *xmlNode) string {
+	var buf bytes.Buffer
+	buf.WriteString("<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n")
+	buf.WriteString("<!-- Generated by " + AppName + " " + FormatVersion(AppVersion) + " -->\n")
+	root.writeXML(&buf, 0)
+	return buf.String()
+}
+
+// addPBCoreDescription adds the identifier, title and description a description document
+// requires, taken from the first file.
+func addPBCoreDescription(root *xmlNode, reports []Report) {
+	name, title := "", ""
+	if len(reports) > 0 {
+		name = filepath.Base(reports[0].Ref)
+		title = jsonFieldValue(buildJSONGeneralFields(reports[0]), "Title")
+	}
+	if title == "" {
+		title = name
+	}
+	if root.name == "PBCoreDescriptionDocument" {
+		identifier := root.add(newXMLNode("pbcoreIdentifier"))
+		identifier.add(&xmlNode{name: "identifier", text: name})
+		identifier.add(&xmlNode{name: "identifierSource", text: "File Name"})
+		root.add(newXMLNode("pbcoreTitle")).add(&xmlNode{name: "title", text: title})
+		root.add(newXMLNode("pbcoreDescription")).add(&xmlNode{name: "description", text: title})
+		return
+	}
+	root.add(&xmlNode{name: "pbcoreIdentifier", text: name, attrs: []xmlAttr{{name: "source", value: "File Name"}}})
+	root.add(&xmlNode{name: "pbcoreTitle", text: title})
+	root.add(&xmlNode{name: "pbcoreDescription", text: title})
+}
+
+// pbcoreSummary holds the per-file values both PBCore versions map, in raw (JSON) units.
+type pbcoreSummary struct {
+	name      string
+	location  string
+	mediaType string
+	mimeType  string
+	fileSize  string
+	duration  string
+	dataRate  string
+	created   string
+	modified  string
+	tracks    []pbcoreTrack
+}
+
+type pbcoreTrack struct {
+	kind         string
+	id           string
+	standard     string
+	encoding     string
+	codecID      string
+	profile      string
+	dataRate     string
+	frameRate    string
+	samplingRate string
+	bitDepth     string
+	frameSize    string
+	aspectRatio  string
+	duration     string
+	language     string
+	channels     string
+}
+
+func summarizePBCore(report Report) pbcoreSummary {
+	general := buildJSONGeneralFields(report)
+	summary := pbcoreSummary{
+		name:     filepath.Base(report.Ref),
+		location: report.Ref,
+		fileSize: jsonFieldValue(general, "FileSize"),
+		dataRate: jsonFieldValue(general, "OverallBitRate"),
+		created:  pbcoreDate(jsonFieldValue(general, "File_Created_Date")),
+		modified: pbcoreDate(jsonFieldValue(general, "File_Modified_Date")),
+	}
+	if seconds, err := strconv.ParseFloat(jsonFieldValue(general, "Duration"), 64); err == nil {
+		summary.duration = formatDurationString3(seconds)
+	}
+
+	containerFormat := findField(report.General.Fields, "Format")
+	kinds := map[StreamKind]bool{}
+	forEachStreamWithKindIndex(orderTracks(report.Streams), func(stream Stream, _, _, order int) {
+		if stream.Kind == StreamMenu {
+			return
+		}
+		kinds[stream.Kind] = true
+		fields := buildJSONStreamFields(stream, order, 0, containerFormat)
+		track := pbcoreTrack{
+			kind:     string(stream.Kind),
+			id:       jsonFieldValue(fields, "ID"),
+			standard: jsonFieldValue(fields, "Standard"),
+			encoding: jsonFieldValue(fields, "Format"),
+			codecID:  jsonFieldValue(fields, "CodecID"),
+			profile:  ebuCoreProfile(fields),
+			dataRate: jsonFieldValue(fields, "BitRate"),
+			bitDepth: jsonFieldValue(fields, "BitDepth"),
+			language: languageCode3(jsonFieldValue(fields, "Language")),
+			channels: jsonFieldValue(fields, "Channels"),
+		}
+		if stream.Kind == StreamText {
+			track.kind = "Text"
+		}
+		if stream.Kind == StreamVideo || stream.Kind == StreamImage {
+			track.frameRate = jsonFieldValue(fields, "FrameRate")
+			width, height := jsonFieldValue(fields, "Width"), jsonFieldValue(fields, "Height")
+			if width != "" && height != "" {
+				track.frameSize = width + "x" + height
+			}
+			track.aspectRatio = findField(stream.Fields, "Display aspect ratio")
+		}
+		if hz, err := strconv.ParseFloat(jsonFieldValue(fields, "SamplingRate"), 64); err == nil && hz > 0 {
+			track.samplingRate = strconv.FormatFloat(hz/1000, 'f', -1, 64)
+		}
+		if seconds, err := strconv.ParseFloat(jsonFieldValue(fields, "Duration"), 64); err == nil {
+			track.duration = formatDurationString3(seconds)
+		}
+		summary.tracks = append(summary.tracks, track)
+	})
+
+	switch {
+	case kinds[StreamVideo]:
+		summary.mediaType = "Moving Image"
+	case kinds[StreamAudio]:
+		summary.mediaType = "Sound"
+	case kinds[StreamImage]:
+		summary.mediaType = "Static Image"
+	case kinds[StreamText]:
+		summary.mediaType = "Text"
+	}
+	summary.mimeType = pbcoreMIMEType(jsonFieldValue(general, "Format"), kinds[StreamVideo])
+	if summary.mimeType == "" {
+		// formatDigital/instantiationDigital is required for digital files.
+		summary.mimeType = "application/octet-stream"
+	}
+	return summary
+}
+
+func buildPBCore1Instantiation(report Report) *xmlNode {
+	summary := summarizePBCore(report)
+	inst := newXMLNode("pbcoreInstantiation")
+	formatID := inst.add(newXMLNode("pbcoreFormatID"))
+	formatID.add(&xmlNode{name: "formatIdentifier", text: summary.name})
+	formatID.add(&xmlNode{name: "formatIdentifierSource", text: "File Name"})
+	inst.addText("dateCreated", summary.created)
+	inst.addText("formatDigital", summary.mimeType)
+	inst.add(&xmlNode{name: "formatLocation", text: summary.location})
+	inst.addText("formatMediaType", summary.mediaType)
+	inst.addText("formatFileSize", summary.fileSize)
+	inst.addText("formatDuration", summary.duration)
+	inst.addText("formatDataRate", summary.dataRate)
+	inst.addText("formatTracks", strconv.Itoa(len(summary.tracks)))
+	for _, track := range summary.tracks {
+		essence := inst.add(newXMLNode("pbcoreEssenceTrack"))
+		essence.addText("essenceTrackType", track.kind)
+		if essence.addText("essenceTrackIdentifier", track.id) != nil {
+			essence.addText("essenceTrackIdentifierSource", pbcoreTrackIDBy)
+		}
+		essence.addText("essenceTrackStandard", track.standard)
+		essence.addText("essenceTrackEncoding", track.encoding)
+		essence.addText("essenceTrackDataRate", track.dataRate)
+		essence.addText("essenceTrackFrameRate", track.frameRate)
+		essence.addText("essenceTrackSamplingRate", track.samplingRate)
+		essence.addText("essenceTrackBitDepth", track.bitDepth)
+		essence.addText("essenceTrackFrameSize", track.frameSize)
+		essence.addText("essenceTrackAspectRatio", track.aspectRatio)
+		essence.addText("essenceTrackDuration", track.duration)
+		essence.addText("essenceTrackLanguage", track.language)
+	}
+	return inst
+}
+
+func addPBCore2Instantiation(inst *xmlNode, report Report) {
+	summary := summarizePBCore(report)
+	inst.add(&xmlNode{name: "instantiationIdentifier", text: summary.name, attrs: []xmlAttr{{name: "source", value: "File Name"}}})
+	inst.addText("instantiationDate", summary.created, xmlAttr{name: "dateType", value: "created"})
+	inst.addText("instantiationDate", summary.modified, xmlAttr{name: "dateType", value: "file modification"})
+	inst.addText("instantiationDigital", summary.mimeType)
+	inst.add(&xmlNode{name: "instantiationLocation", text: summary.location})
+	inst.addText("instantiationMediaType", summary.mediaType)
+	inst.addText("instantiationFileSize", summary.fileSize, xmlAttr{name: "unitsOfMeasure", value: "byte"})
+	inst.addText("instantiationDuration", summary.duration)
+	inst.addText("instantiationDataRate", summary.dataRate, xmlAttr{name: "unitsOfMeasure", value: "bit/second"})
+	inst.addText("instantiationTracks", strconv.Itoa(len(summary.tracks)))
+	for _, track := range summary.tracks {
+		essence := inst.add(newXMLNode("instantiationEssenceTrack"))
+		essence.addText("essenceTrackType", track.kind)
+		essence.addText("essenceTrackIdentifier", track.id, xmlAttr{name: "source", value: pbcoreTrackIDBy})
+		essence.addText("essenceTrackStandard", track.standard)
+		if encoding := essence.addText("essenceTrackEncoding", track.encoding); encoding != nil {
+			if track.codecID != "" {
+				encoding.attr("source", "codecid").attr("ref", track.codecID)
+			}
+			encoding.attr("annotation", track.profile)
+		}
+		essence.addText("essenceTrackDataRate", track.dataRate, xmlAttr{name: "unitsOfMeasure", value: "bit/second"})
+		essence.addText("essenceTrackFrameRate", track.frameRate, xmlAttr{name: "unitsOfMeasure", value: "fps"})
+		essence.addText("essenceTrackSamplingRate", track.samplingRate, xmlAttr{name: "unitsOfMeasure", value: "kHz"})
+		essence.addText("essenceTrackBitDepth", track.bitDepth)
+		essence.addText("essenceTrackFrameSize", track.frameSize, xmlAttr{name: "unitsOfMeasure", value: "pixel"})
+		essence.addText("essenceTrackAspectRatio", track.aspectRatio)
+		essence.addText("essenceTrackDuration", track.duration)
+		essence.addText("essenceTrackLanguage", track.language)
+		if track.channels != "" {
+			essence.addText("essenceTrackAnnotation", track.channels, xmlAttr{name: "annotationType", value: "Channel(s)"})
+		}
+	}
+}
+
+// pbcoreMIMEType maps a container format to the Internet media type PBCore expects in
+// formatDigital/instantiationDigital.
+func pbcoreMIMEType(format string, hasVideo bool) string {
+	pick := func(video, audio string) string {
+		if hasVideo {
+			return video
+		}
+		return audio
+	}
+	switch format {
+	case "MPEG-4":
+		return pick("video/mp4", "audio/mp4")
+	case "QuickTime":
+		return "video/quicktime"
+	case "Matroska":
+		return pick("video/x-matroska", "audio/x-matroska")
+	case "WebM":
+		return pick("video/webm", "audio/webm")
+	case "MPEG-TS", "BDAV":
+		return "video/MP2T"
+	case "MPEG-PS", "DVD Video":
+		return "video/MP2P"
+	case "MPEG Video":
+		return "video/mpeg"
+	case "AVI":
+		return "video/vnd.avi"
+	case "Ogg":
+		return pick("video/ogg", "audio/ogg")
+	case "FLAC":
+		return "audio/flac"
+	case "MPEG Audio":
+		return "audio/mpeg"
+	case "Wave":
+		return "audio/wav"
+	case "AIFF":
+		return "audio/aiff"
+	case "JPEG":
+		return "image/jpeg"
+	case "PNG":
+		return "image/png"
+	}
+	return ""
+}
+
+// pbcoreDate converts MediaInfo's "2006-01-02 15:04:05 UTC" to an ISO 8601 timestamp.
+func pbcoreDate(value string) string {
+	date, clock, ok := ebuCoreDate(value)
+	if !ok {
+		return ""
+	}
+	return date + "T" + clock
+}
+
+// languageCode3 returns the ISO 639-2/T code for a two-letter language code, keeping any region
+// subtag off; codes without a known mapping are returned unchanged.
+func languageCode3(code string) string {
+	base, _, _ := strings.Cut(normalizeLanguageCode(code), "-")
+	if three, ok := languageMap2To3[base]; ok {
+		return three
+	}
+	return code
 }
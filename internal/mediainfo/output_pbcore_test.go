@@ -0,0 +1,132 @@
+package mediainfo
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// Element sequences from the PBCore 1.3 and 2.1 schemas for the parts the renderers emit.
+var (
+	pbcore1InstantiationModel = []string{
+		"pbcoreFormatID", "dateCreated", "dateIssued", "formatPhysical", "formatDigital", "formatLocation",
+		"formatMediaType", "formatGenerations", "formatFileSize", "formatTimeStart", "formatDuration",
+		"formatDataRate", "formatColors", "formatTracks", "formatChannelConfiguration", "language",
+		"alternativeModes", "pbcoreEssenceTrack",
+	}
+	pbcore1EssenceModel = []string{
+		"essenceTrackType", "essenceTrackIdentifier", "essenceTrackIdentifierSource", "essenceTrackStandard",
+		"essenceTrackEncoding", "essenceTrackDataRate", "essenceTrackFrameRate", "essenceTrackPlaybackSpeed",
+		"essenceTrackSamplingRate", "essenceTrackBitDepth", "essenceTrackFrameSize", "essenceTrackAspectRatio",
+		"essenceTrackTimeStart", "essenceTrackDuration", "essenceTrackLanguage", "essenceTrackAnnotation",
+	}
+	pbcore2InstantiationModel = []string{
+		"instantiationIdentifier", "instantiationDate", "instantiationDimensions", "instantiationPhysical",
+		"instantiationDigital", "instantiationStandard", "instantiationLocation", "instantiationMediaType",
+		"instantiationGenerations", "instantiationFileSize", "instantiationTimeStart", "instantiationDuration",
+		"instantiationDataRate", "instantiationColors", "instantiationTracks", "instantiationChannelConfiguration",
+		"instantiationLanguage", "instantiationAlternativeModes", "instantiationEssenceTrack",
+		"instantiationRelation", "instantiationRights", "instantiationAnnotation",
+	}
+	pbcore2EssenceModel = []string{
+		"essenceTrackType", "essenceTrackIdentifier", "essenceTrackStandard", "essenceTrackEncoding",
+		"essenceTrackDataRate", "essenceTrackFrameRate", "essenceTrackPlaybackSpeed", "essenceTrackSamplingRate",
+		"essenceTrackBitDepth", "essenceTrackFrameSize", "essenceTrackAspectRatio", "essenceTrackTimeStart",
+		"essenceTrackDuration", "essenceTrackLanguage", "essenceTrackAnnotation", "essenceTrackExtension",
+	}
+)
+
+func childNamed(elem *xmlTestElement, name string) *xmlTestElement {
+	for _, child := range elem.children {
+		if child.name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+func TestRenderPBCoreVersions(t *testing.T) {
+	namespaces := map[string]string{pbcoreNS: ""}
+	for _, sample := range []string{"sample.mkv", "sample.ts", "sample.mp3", "sample.vob"} {
+		t.Run(sample, func(t *testing.T) {
+			report, err := AnalyzeFile(filepath.Join("samples", sample))
+			if err != nil {
+				t.Fatalf("analyze: %v", err)
+			}
+
+			v1 := parseXMLTree(t, RenderPBCore([]Report{report}), namespaces)
+			if v1.name != "PBCoreDescriptionDocument" {
+				t.Fatalf("1.x root=%s", v1.name)
+			}
+			inst := childNamed(v1, "pbcoreInstantiation")
+			if inst == nil {
+				t.Fatalf("1.x output has no pbcoreInstantiation")
+			}
+			checkXMLSequence(t, inst, pbcore1InstantiationModel)
+			for _, child := range inst.children {
+				if child.name == "pbcoreEssenceTrack" {
+					checkXMLSequence(t, child, pbcore1EssenceModel)
+				}
+			}
+
+			v2 := parseXMLTree(t, RenderPBCore2([]Report{report}), namespaces)
+			if v2.name != "pbcoreInstantiationDocument" {
+				t.Fatalf("2.x root=%s", v2.name)
+			}
+			checkXMLSequence(t, v2, pbcore2InstantiationModel)
+			tracks := 0
+			for _, child := range v2.children {
+				if child.name == "instantiationEssenceTrack" {
+					checkXMLSequence(t, child, pbcore2EssenceModel)
+					tracks++
+				}
+			}
+			if tracks == 0 || childNamed(v2, "instantiationTracks").text != strconv.Itoa(tracks) {
+				t.Fatalf("instantiationTracks does not match %d essence tracks", tracks)
+			}
+		})
+	}
+}
+
+func TestRenderPBCore2Mapping(t *testing.T) {
+	report, err := AnalyzeFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	out := RenderPBCore2([]Report{report})
+	for _, want := range []string{
+		`<instantiationDigital>video/x-matroska</instantiationDigital>`,
+		`<instantiationFileSize unitsOfMeasure="byte">275012</instantiationFileSize>`,
+		`<instantiationDuration>00:00:04.021</instantiationDuration>`,
+		`<essenceTrackEncoding source="codecid" ref="V_MPEG4/ISO/AVC" annotation="High@L3">AVC</essenceTrackEncoding>`,
+		`<essenceTrackFrameRate unitsOfMeasure="fps">29.970</essenceTrackFrameRate>`,
+		`<essenceTrackSamplingRate unitsOfMeasure="kHz">48</essenceTrackSamplingRate>`,
+		`<essenceTrackBitDepth>8</essenceTrackBitDepth>`,
+		`<essenceTrackFrameSize unitsOfMeasure="pixel">640x360</essenceTrackFrameSize>`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("PBCore 2 output is missing %s", want)
+		}
+	}
+
+	multi := parseXMLTree(t, RenderPBCore2([]Report{report, report}), map[string]string{pbcoreNS: ""})
+	if multi.name != "pbcoreDescriptionDocument" || strings.Count(RenderPBCore2([]Report{report, report}), "<pbcoreInstantiation>") != 2 {
+		t.Fatalf("multi-file PBCore 2 root=%s", multi.name)
+	}
+}
+
+func TestLanguageCode3(t *testing.T) {
+	// zh, cs and fr have distinct bibliographic codes (chi, cze, fre); the terminology ones win.
+	cases := map[string]string{"en": "eng", "fr": "fra", "de-AT": "deu", "zh": "zho", "cs": "ces", "ger": "deu", "xx": "xx", "": ""}
+	for code, want := range cases {
+		if got := languageCode3(code); got != want {
+			t.Fatalf("languageCode3(%q)=%q, want %q", code, got, want)
+		}
+	}
+	for two, three := range languageMap2To3 {
+		if languageMap3To2[three] != two {
+			t.Fatalf("languageMap2To3[%q]=%q does not map back", two, three)
+		}
+	}
+}
@@ -4,19 +4,24 @@ import (
 	"bytes"
 	"fmt"
 	"strings"
+	"unicode/utf8"
 )
 
 func RenderText(reports []Report) string {
+	return RenderTextTranslated(reports, nil)
+}
+
+// RenderTextTranslated renders text output with labels and values passed through tr.
+func RenderTextTranslated(reports []Report, tr *Translation) string {
 	var buf bytes.Buffer
 	for i, report := range reports {
 		if i > 0 {
 			buf.WriteString("\n")
 		}
-		writeStream(&buf, string(report.General.Kind), report.General)
+		writeStream(&buf, tr.title(report.General.Kind, 1, 1), tr.stream(report.General))
 		forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, _ int) {
 			buf.WriteString("\n")
-			title := streamTitle(stream.Kind, index, total)
-			writeStream(&buf, title, stream)
+			writeStream(&buf, tr.title(stream.Kind, index, total), tr.stream(stream))
 		})
 		buf.WriteString("\n")
 		buf.WriteString(reportByLine())
@@ -42,10 +47,11 @@ func writeStream(buf *bytes.Buffer, title string, stream Stream) {
 }
 
 func padRight(value string, width int) string {
-	if len(value) >= width {
+	length := utf8.RuneCountInString(value)
+	if length >= width {
 		return value
 	}
-	return value + strings.Repeat(" ", width-len(value))
+	return value + strings.Repeat(" ", width-length)
 }
 
 func streamTitle(kind StreamKind, index, total int) string {
@@ -0,0 +1,250 @@
+package mediainfo
+
+import (
+	"bytes"
+	"math"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// fullFieldLabels names the internal fields that have no text counterpart, as MediaInfo's -f does.
+// Anything missing here is shown under its internal name.
+var fullFieldLabels = map[string]string{
+	"VideoCount":               "Count of video streams",
+	"AudioCount":               "Count of audio streams",
+	"TextCount":                "Count of text streams",
+	"OtherCount":               "Count of other streams",
+	"ImageCount":               "Count of image streams",
+	"MenuCount":                "Count of menu streams",
+	"FileExtension":            "File extension",
+	"FileSize":                 "File size",
+	"File_Created_Date":        "File creation date",
+	"File_Created_Date_Local":  "File creation date (local)",
+	"File_Modified_Date":       "File last modification date",
+	"File_Modified_Date_Local": "File last modification date (local)",
+	"StreamSize":               "Stream size",
+	"StreamSize_Proportion":    "Proportion of this stream",
+	"FrameCount":               "Frame count",
+	"SamplingCount":            "Samples count",
+	"SamplesPerFrame":          "Samples per frame",
+	"PixelAspectRatio":         "Pixel aspect ratio",
+	"Delay":                    "Delay",
+	"Delay_Source":             "Delay, origin",
+	"Video_Delay":              "Delay relative to video",
+	"OverallBitRate":           "Overall bit rate",
+	"OverallBitRate_Mode":      "Overall bit rate mode",
+	"BitRate":                  "Bit rate",
+	"BitRate_Mode":             "Bit rate mode",
+	"BitRate_Maximum":          "Maximum bit rate",
+	"BitRate_Nominal":          "Nominal bit rate",
+	"Duration":                 "Duration",
+	"Encoded_Application":      "Writing application",
+	"Encoded_Library":          "Writing library",
+}
+
+// fullDurationKeys are the fields JSON carries in seconds; the full view shows them in
+// milliseconds followed by their string variants.
+var fullDurationKeys = map[string]bool{
+	"Duration":                  true,
+	"Source_Duration":           true,
+	"Source_Duration_LastFrame": true,
+	"Duration_Start":            true,
+	"Duration_End":              true,
+	"Delay":                     true,
+	"Delay_Original":            true,
+	"Video_Delay":               true,
+}
+
+// RenderTextFull renders the complete field set like MediaInfo's -f/--Full text view: every
+// raw value followed by its display variants, including fields only JSON normally carries.
+func RenderTextFull(reports []Report) string {
+	return RenderTextFullTranslated(reports, nil)
+}
+
+// RenderTextFullTranslated is RenderTextFull with labels and values passed through tr.
+func RenderTextFullTranslated(reports []Report, tr *Translation) string {
+	var buf bytes.Buffer
+	for i, report := range reports {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		general := report.General
+		general.Fields = fullStreamFields(StreamGeneral, report.General.Fields, buildJSONGeneralFields(report), 1, 1)
+		general.Fields = append(fullGeneralFileFields(report), general.Fields...)
+		writeStream(&buf, tr.title(StreamGeneral, 1, 1), tr.stream(general))
+		containerFormat := findField(report.General.Fields, "Format")
+		forEachStreamWithKindIndex(report.Streams, func(stream Stream, index, total, order int) {
+			typeOrder := 0
+			if total > 1 {
+				typeOrder = index
+			}
+			jsonFields := buildJSONStreamFields(stream, order, typeOrder, containerFormat)
+			full := stream
+			full.Fields = fullStreamFields(stream.Kind, stream.Fields, jsonFields, index, total)
+			buf.WriteString("\n")
+			writeStream(&buf, tr.title(stream.Kind, index, total), tr.stream(full))
+		})
+		buf.WriteString("\n")
+		buf.WriteString(reportByLine())
+		buf.WriteString("\n")
+	}
+	output := strings.TrimRight(buf.String(), "\n")
+	return output + "\n\n"
+}
+
+func fullGeneralFileFields(report Report) []Field {
+	ref := report.Ref
+	if ref == "" {
+		ref = findField(report.General.Fields, "Complete name")
+	}
+	if ref == "" {
+		return nil
+	}
+	base := filepath.Base(ref)
+	ext := filepath.Ext(base)
+	return []Field{
+		{Name: "Complete name", Value: ref},
+		{Name: "Folder name", Value: filepath.Dir(ref)},
+		{Name: "File name extension", Value: base},
+		{Name: "File name", Value: strings.TrimSuffix(base, ext)},
+	}
+}
+
+func fullStreamFields(kind StreamKind, fields []Field, jsonFields []jsonKV, index, total int) []Field {
+	out := []Field{
+		{Name: "Count of stream of this kind", Value: strconv.Itoa(total)},
+		{Name: "Kind of stream", Value: string(kind)},
+		{Name: "Kind of stream", Value: string(kind)},
+		{Name: "Stream identifier", Value: strconv.Itoa(index - 1)},
+	}
+	if total > 1 {
+		out = append(out, Field{Name: "Stream identifier", Value: strconv.Itoa(index)})
+	}
+
+	keys := textFieldJSONKeys(kind, fields)
+	textByKey := map[string]int{}
+	for i, key := range keys {
+		if key == "" {
+			continue
+		}
+		if _, exists := textByKey[key]; !exists {
+			textByKey[key] = i
+		}
+	}
+	used := make([]bool, len(fields))
+	fps, _ := strconv.ParseFloat(jsonFieldValue(jsonFields, "FrameRate"), 64)
+
+	emit := func(key, value string) {
+		label := key
+		textIndex, hasText := textByKey[key]
+		if hasText {
+			label = fields[textIndex].Name
+		} else if known, ok := fullFieldLabels[key]; ok {
+			label = known
+		}
+		out = append(out, Field{Name: label, Value: fullRawValue(key, value)})
+		display := ""
+		if hasText && !used[textIndex] {
+			display = fields[textIndex].Value
+			used[textIndex] = true
+		} else if !hasText {
+			display = fullDerivedDisplay(key, value)
+		}
+		if display != "" {
+			out = append(out, Field{Name: label, Value: display})
+		}
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil && fullDurationKeys[key] && seconds > 0 {
+			for _, variant := range []string{
+				formatDurationString1(seconds),
+				formatDurationString2(seconds),
+				formatDurationString3(seconds),
+				formatDurationString4(seconds, fps),
+				formatDurationString5(seconds, fps),
+			} {
+				if variant != "" {
+					out = append(out, Field{Name: label, Value: variant})
+				}
+			}
+		}
+	}
+
+	for _, field := range jsonFields {
+		switch {
+		case field.Key == "@type" || field.Key == "@typeorder":
+		case field.Key == "extra" && field.Raw:
+			parsed, err := parseOrderedJSON(field.Val)
+			if err != nil || parsed.kind != orderedObject {
+				continue
+			}
+			for _, kv := range parsed.obj {
+				if kv.val.kind == orderedString {
+					emit(kv.key, kv.val.str)
+				}
+			}
+		case field.Raw:
+		default:
+			emit(field.Key, field.Val)
+		}
+	}
+	// Text-only fields (chapters, "Format/Info", ...) keep their order relative to each other.
+	for i, field := range fields {
+		if !used[i] && keys[i] != "CompleteName" {
+			out = append(out, field)
+		}
+	}
+	return out
+}
+
+// textFieldJSONKeys returns the JSON key each text field produces, or "" when it has none.
+func textFieldJSONKeys(kind StreamKind, fields []Field) []string {
+	keys := make([]string, len(fields))
+	for i, field := range fields {
+		switch field.Name {
+		case "Complete name":
+			keys[i] = "CompleteName"
+			continue
+		case "File size":
+			keys[i] = "FileSize"
+			continue
+		}
+		for _, kv := range mapStreamFieldsToJSON(kind, []Field{field}) {
+			if !kv.Raw {
+				keys[i] = kv.Key
+				break
+			}
+		}
+	}
+	return keys
+}
+
+// fullRawValue converts JSON seconds to MediaInfo's raw milliseconds.
+func fullRawValue(key, value string) string {
+	if !fullDurationKeys[key] {
+		return value
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	return strconv.FormatFloat(math.Round(seconds*1e6)/1e3, 'f', -1, 64)
+}
+
+// fullDerivedDisplay formats the string variant of a field that has no text counterpart.
+func fullDerivedDisplay(key, value string) string {
+	switch {
+	case fullDurationKeys[key]:
+		if seconds, err := strconv.ParseFloat(value, 64); err == nil {
+			return formatDuration(seconds)
+		}
+	case key == "StreamSize" || key == "FileSize" || key == "HeaderSize" || key == "DataSize" || key == "FooterSize":
+		if size, err := strconv.ParseInt(value, 10, 64); err == nil {
+			return formatBytes(size)
+		}
+	case strings.HasPrefix(key, "BitRate") || strings.HasPrefix(key, "OverallBitRate"):
+		if bps, err := strconv.ParseFloat(value, 64); err == nil {
+			return formatBitrate(bps)
+		}
+	}
+	return ""
+}
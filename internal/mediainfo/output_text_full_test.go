@@ -0,0 +1,54 @@
+package mediainfo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRenderTextFullIncludesTextFields(t *testing.T) {
+	report, err := AnalyzeFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	full := RenderTextFull([]Report{report})
+	lines := map[string]bool{}
+	for line := range strings.SplitSeq(full, "\n") {
+		lines[line] = true
+	}
+	for line := range strings.SplitSeq(RenderText([]Report{report}), "\n") {
+		if !lines[line] {
+			t.Fatalf("full output is missing %q", line)
+		}
+	}
+	for _, want := range []string{
+		"Count of stream of this kind             : 1",
+		"Kind of stream                           : Video",
+		"Duration                                 : 3970",
+		"Duration                                 : 00:00:03.970",
+		"Duration                                 : 00:00:03.970 (00:00:03:29)",
+		"FrameRate_Num                            : 30000",
+		"Samples count                            : 193008",
+		"Delay, origin                            : Container",
+	} {
+		if !lines[want] {
+			t.Fatalf("full output is missing %q", want)
+		}
+	}
+}
+
+func TestFullRawValue(t *testing.T) {
+	cases := []struct {
+		key, value, want string
+	}{
+		{"Duration", "3.970", "3970"},
+		{"Delay", "0.0415", "41.5"},
+		{"Width", "640", "640"},
+		{"Delay_Source", "Container", "Container"},
+	}
+	for _, tc := range cases {
+		if got := fullRawValue(tc.key, tc.value); got != tc.want {
+			t.Fatalf("fullRawValue(%q, %q)=%q, want %q", tc.key, tc.value, got, tc.want)
+		}
+	}
+}
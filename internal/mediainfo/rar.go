@@ -0,0 +1,547 @@
+package mediainfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+var (
+	rar4Signature = []byte("Rar!\x1a\x07\x00")
+	rar5Signature = []byte("Rar!\x1a\x07\x01\x00")
+)
+
+// RAR4 block types and flags.
+const (
+	rar4BlockMain     = 0x73
+	rar4BlockFile     = 0x74
+	rar4BlockEnd      = 0x7b
+	rar4LongBlock     = 0x8000
+	rar4MainEncrypted = 0x0080
+	rar4SplitBefore   = 0x0001
+	rar4SplitAfter    = 0x0002
+	rar4FileEncrypted = 0x0004
+	rar4FileLarge     = 0x0100
+	rar4FileUnicode   = 0x0200
+	rar4DirectoryMask = 0x00e0
+	rar4EndNextVolume = 0x0001
+	rar4MethodStore   = 0x30
+)
+
+// RAR5 header types and flags.
+const (
+	rar5HeaderFile       = 2
+	rar5HeaderEncryption = 4
+	rar5HeaderEnd        = 5
+	rar5HasExtra         = 0x0001
+	rar5HasData          = 0x0002
+	rar5SplitBefore      = 0x0008
+	rar5SplitAfter       = 0x0010
+	rar5FileDirectory    = 0x0001
+	rar5FileHasTime      = 0x0002
+	rar5FileHasCRC       = 0x0004
+	rar5ExtraEncryption  = 0x01
+	rar5EndNextVolume    = 0x0001
+)
+
+// RARFile is one entry of a RAR volume set.
+type RARFile struct {
+	Name string
+	Size int64
+	// Stored is false for compressed entries, which cannot be read in place.
+	Stored    bool
+	Encrypted bool
+	parts     []rarPart
+}
+
+// rarPart is the slice of one volume holding part of an entry's data.
+type rarPart struct {
+	volume int
+	offset int64
+	size   int64
+}
+
+// RARArchive is an opened RAR4 or RAR5 volume set.
+type RARArchive struct {
+	Path    string
+	Files   []RARFile
+	volumes []*os.File
+}
+
+// IsRARPath reports whether path names the first volume of a RAR set by its extension.
+func IsRARPath(path string) bool {
+	return strings.EqualFold(filepath.Ext(path), ".rar")
+}
+
+// OpenRAR opens the volume set starting at path, following .partN.rar or .rar/.r00/.r01 names
+// for as long as an entry continues into the next volume.
+func OpenRAR(path string) (*RARArchive, error) {
+	archive := &RARArchive{Path: path}
+	open := map[string]int{}
+	volumePath := path
+	for {
+		file, err := os.Open(volumePath)
+		if err != nil {
+			missing := len(archive.volumes) > 0 && errors.Is(err, os.ErrNotExist)
+			archive.Close()
+			if missing {
+				return nil, fmt.Errorf("rar: missing volume %s", filepath.Base(volumePath))
+			}
+			return nil, err
+		}
+		index := len(archive.volumes)
+		archive.volumes = append(archive.volumes, file)
+		open[volumePath] = index
+		continues, err := archive.readVolume(file, index)
+		if err != nil {
+			archive.Close()
+			return nil, fmt.Errorf("rar: %s: %w", filepath.Base(volumePath), err)
+		}
+		if !continues {
+			break
+		}
+		volumePath = nextRARVolume(volumePath, index == 0)
+		if _, seen := open[volumePath]; seen || volumePath == "" {
+			archive.Close()
+			return nil, fmt.Errorf("rar: cannot name the volume after %s", filepath.Base(path))
+		}
+	}
+	for _, file := range archive.Files {
+		if total := rarPartsSize(file.parts); file.Stored && !file.Encrypted && total != file.Size {
+			archive.Close()
+			return nil, fmt.Errorf("rar: %s: volumes hold %d of %d bytes", file.Name, total, file.Size)
+		}
+	}
+	return archive, nil
+}
+
+// Close closes every volume.
+func (a *RARArchive) Close() error {
+	var errs []error
+	for _, file := range a.volumes {
+		errs = append(errs, file.Close())
+	}
+	a.volumes = nil
+	return errors.Join(errs...)
+}
+
+// Open returns a reader over a stored entry's data across all volumes.
+func (a *RARArchive) Open(name string) (io.ReaderAt, int64, error) {
+	for _, file := range a.Files {
+		if file.Name != name {
+			continue
+		}
+		switch {
+		case file.Encrypted:
+			return nil, 0, fmt.Errorf("rar: %s is encrypted", name)
+		case !file.Stored:
+			return nil, 0, fmt.Errorf("rar: %s is compressed; only stored (-m0) entries can be analyzed without extracting", name)
+		}
+		return newRARReader(a.volumes, file.parts, file.Size), file.Size, nil
+	}
+	return nil, 0, fmt.Errorf("rar: %s: %w", name, os.ErrNotExist)
+}
+
+// Largest returns the name of the biggest entry, which is the media in a typical release.
+func (a *RARArchive) Largest() string {
+	name, size := "", int64(-1)
+	for _, file := range a.Files {
+		if file.Size > size {
+			name, size = file.Name, file.Size
+		}
+	}
+	return name
+}
+
+// readVolume records the entries of one volume and reports whether the set continues, either
+// because the last entry is split or the end-of-archive block announces another volume.
+func (a *RARArchive) readVolume(file *os.File, volume int) (bool, error) {
+	signature := make([]byte, len(rar5Signature))
+	n, _ := io.ReadFull(file, signature)
+	switch {
+	case bytes.HasPrefix(signature[:n], rar5Signature):
+		return a.readRAR5Volume(file, volume, int64(len(rar5Signature)))
+	case bytes.HasPrefix(signature[:n], rar4Signature):
+		return a.readRAR4Volume(file, volume, int64(len(rar4Signature)))
+	}
+	return false, errors.New("not a RAR archive")
+}
+
+func (a *RARArchive) readRAR4Volume(file *os.File, volume int, offset int64) (bool, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	continues := false
+	for offset+7 <= stat.Size() {
+		head := make([]byte, 7)
+		if _, err := file.ReadAt(head, offset); err != nil {
+			return false, err
+		}
+		kind := head[2]
+		flags := binary.LittleEndian.Uint16(head[3:])
+		headSize := int64(binary.LittleEndian.Uint16(head[5:]))
+		if headSize < 7 {
+			return false, errors.New("corrupt block header")
+		}
+		header := make([]byte, headSize)
+		if _, err := file.ReadAt(header, offset); err != nil {
+			return false, err
+		}
+		var dataSize int64
+		if flags&rar4LongBlock != 0 || kind == rar4BlockFile {
+			if headSize < 11 {
+				return false, errors.New("corrupt block header")
+			}
+			dataSize = int64(binary.LittleEndian.Uint32(header[7:]))
+		}
+		switch kind {
+		case rar4BlockMain:
+			if flags&rar4MainEncrypted != 0 {
+				return false, errors.New("archive headers are encrypted")
+			}
+		case rar4BlockFile:
+			if headSize < 32 {
+				return false, errors.New("corrupt file header")
+			}
+			unpacked := int64(binary.LittleEndian.Uint32(header[11:]))
+			method := header[25]
+			nameSize := int(binary.LittleEndian.Uint16(header[26:]))
+			nameStart := 32
+			if flags&rar4FileLarge != 0 {
+				if headSize < 40 {
+					return false, errors.New("corrupt file header")
+				}
+				dataSize |= int64(binary.LittleEndian.Uint32(header[32:])) << 32
+				unpacked |= int64(binary.LittleEndian.Uint32(header[36:])) << 32
+				nameStart = 40
+			}
+			if nameStart+nameSize > len(header) {
+				return false, errors.New("corrupt file name")
+			}
+			name := header[nameStart : nameStart+nameSize]
+			if flags&rar4FileUnicode != 0 {
+				// The plain name precedes a NUL and RAR's compressed UTF-16 form.
+				if before, _, found := bytes.Cut(name, []byte{0}); found {
+					name = before
+				}
+			}
+			if flags&rar4DirectoryMask != rar4DirectoryMask {
+				err := a.addPart(volume, rarEntry{
+					name:        strings.ReplaceAll(string(name), `\`, "/"),
+					size:        unpacked,
+					stored:      method == rar4MethodStore,
+					encrypted:   flags&rar4FileEncrypted != 0,
+					splitBefore: flags&rar4SplitBefore != 0,
+				}, offset+headSize, dataSize)
+				if err != nil {
+					return false, err
+				}
+			}
+			continues = flags&rar4SplitAfter != 0
+		case rar4BlockEnd:
+			return continues || flags&rar4EndNextVolume != 0, nil
+		}
+		offset += headSize + dataSize
+	}
+	return continues, nil
+}
+
+func (a *RARArchive) readRAR5Volume(file *os.File, volume int, offset int64) (bool, error) {
+	stat, err := file.Stat()
+	if err != nil {
+		return false, err
+	}
+	continues := false
+	for offset+5 < stat.Size() {
+		// CRC32 (4 bytes), header size (vint), then the header itself.
+		prefix := make([]byte, 4+10)
+		n, _ := file.ReadAt(prefix, offset)
+		headerSize, sizeLen := rar5Vint(prefix[4:n])
+		if sizeLen == 0 || headerSize == 0 || headerSize > 2<<20 {
+			return false, errors.New("corrupt block header")
+		}
+		headerStart := offset + 4 + int64(sizeLen)
+		header := make([]byte, headerSize)
+		if _, err := file.ReadAt(header, headerStart); err != nil {
+			return false, err
+		}
+		fields := rar5Fields{data: header}
+		kind := fields.vint()
+		flags := fields.vint()
+		var extraSize, dataSize uint64
+		if flags&rar5HasExtra != 0 {
+			extraSize = fields.vint()
+		}
+		if flags&rar5HasData != 0 {
+			dataSize = fields.vint()
+		}
+		dataStart := headerStart + int64(headerSize)
+		switch kind {
+		case rar5HeaderEncryption:
+			return false, errors.New("archive headers are encrypted")
+		case rar5HeaderFile:
+			fileFlags := fields.vint()
+			unpacked := fields.vint()
+			fields.vint() // attributes
+			if fileFlags&rar5FileHasTime != 0 {
+				fields.skip(4)
+			}
+			if fileFlags&rar5FileHasCRC != 0 {
+				fields.skip(4)
+			}
+			compression := fields.vint()
+			fields.vint() // host OS
+			nameLen := fields.vint()
+			name := fields.bytes(int(nameLen))
+			if fields.err || extraSize > uint64(len(header)) {
+				return false, errors.New("corrupt file header")
+			}
+			encrypted := false
+			extra := rar5Fields{data: header[len(header)-int(extraSize):]}
+			for !extra.err && extra.pos < len(extra.data) {
+				size := extra.vint()
+				end := extra.pos + int(size)
+				if extra.err || end > len(extra.data) {
+					break
+				}
+				if extra.vint() == rar5ExtraEncryption {
+					encrypted = true
+				}
+				extra.pos = end
+			}
+			if fileFlags&rar5FileDirectory == 0 {
+				err := a.addPart(volume, rarEntry{
+					name:        string(name),
+					size:        int64(unpacked),
+					stored:      (compression>>7)&0x7 == 0,
+					encrypted:   encrypted,
+					splitBefore: flags&rar5SplitBefore != 0,
+				}, dataStart, int64(dataSize))
+				if err != nil {
+					return false, err
+				}
+			}
+			continues = flags&rar5SplitAfter != 0
+		case rar5HeaderEnd:
+			return continues || fields.vint()&rar5EndNextVolume != 0, nil
+		}
+		if fields.err {
+			return false, errors.New("corrupt block header")
+		}
+		offset = dataStart + int64(dataSize)
+	}
+	return continues, nil
+}
+
+type rarEntry struct {
+	name        string
+	size        int64
+	stored      bool
+	encrypted   bool
+	splitBefore bool
+}
+
+// addPart appends a data range to the entry it continues, or starts a new entry.
+func (a *RARArchive) addPart(volume int, entry rarEntry, offset, size int64) error {
+	part := rarPart{volume: volume, offset: offset, size: size}
+	if entry.splitBefore {
+		if len(a.Files) == 0 || a.Files[len(a.Files)-1].Name != entry.name {
+			if volume == 0 {
+				return errors.New("not the first volume of the set")
+			}
+			return fmt.Errorf("%s continues an entry missing from the previous volume", entry.name)
+		}
+		last := &a.Files[len(a.Files)-1]
+		last.parts = append(last.parts, part)
+		return nil
+	}
+	a.Files = append(a.Files, RARFile{
+		Name:      entry.name,
+		Size:      entry.size,
+		Stored:    entry.stored,
+		Encrypted: entry.encrypted,
+		parts:     []rarPart{part},
+	})
+	return nil
+}
+
+var rarPartPattern = regexp.MustCompile(`(?i)^(.*\.part)(\d+)(\.rar)$`)
+
+// nextRARVolume names the volume after path: name.part01.rar -> name.part02.rar, and for the
+// old scheme name.rar -> name.r00 -> name.r01 ... name.r99 -> name.s00.
+func nextRARVolume(path string, first bool) string {
+	dir, base := filepath.Split(path)
+	if match := rarPartPattern.FindStringSubmatch(base); match != nil {
+		number, _ := strconv.Atoi(match[2])
+		return dir + match[1] + fmt.Sprintf("%0*d", len(match[2]), number+1) + match[3]
+	}
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	if first && strings.EqualFold(ext, ".rar") {
+		return dir + stem + rarVolumeExt(ext, 'r', 0)
+	}
+	if len(ext) == 4 && ext[2] >= '0' && ext[2] <= '9' && ext[3] >= '0' && ext[3] <= '9' {
+		number := int(ext[2]-'0')*10 + int(ext[3]-'0') + 1
+		letter := ext[1]
+		if number == 100 {
+			letter, number = letter+1, 0
+		}
+		return dir + stem + rarVolumeExt(ext, letter, number)
+	}
+	return ""
+}
+
+// rarVolumeExt builds ".r00"-style extensions, matching the case of the previous one.
+func rarVolumeExt(previous string, letter byte, number int) string {
+	ext := fmt.Sprintf(".%c%02d", letter, number)
+	if previous[1] >= 'A' && previous[1] <= 'Z' {
+		return strings.ToUpper(ext)
+	}
+	return strings.ToLower(ext)
+}
+
+func rarPartsSize(parts []rarPart) int64 {
+	var total int64
+	for _, part := range parts {
+		total += part.size
+	}
+	return total
+}
+
+// rarReader presents an entry's parts as one contiguous io.ReaderAt.
+type rarReader struct {
+	volumes []*os.File
+	parts   []rarPart
+	starts  []int64 // entry offset where each part begins
+	size    int64
+}
+
+func newRARReader(volumes []*os.File, parts []rarPart, size int64) *rarReader {
+	r := &rarReader{volumes: volumes, parts: parts, starts: make([]int64, len(parts)), size: size}
+	var offset int64
+	for i, part := range parts {
+		r.starts[i] = offset
+		offset += part.size
+	}
+	return r
+}
+
+func (r *rarReader) ReadAt(p []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+	n := 0
+	index := sort.Search(len(r.parts), func(i int) bool {
+		return r.starts[i]+r.parts[i].size > off
+	})
+	for ; index < len(r.parts) && n < len(p); index++ {
+		part := r.parts[index]
+		within := off + int64(n) - r.starts[index]
+		chunk := min(int64(len(p)-n), part.size-within)
+		read, err := r.volumes[part.volume].ReadAt(p[n:n+int(chunk)], part.offset+within)
+		n += read
+		if err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return n, err
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+// rar5Fields decodes RAR5 header fields, recording overruns instead of panicking.
+type rar5Fields struct {
+	data []byte
+	pos  int
+	err  bool
+}
+
+func (f *rar5Fields) vint() uint64 {
+	value, n := rar5Vint(f.data[min(f.pos, len(f.data)):])
+	if n == 0 {
+		f.err = true
+		return 0
+	}
+	f.pos += n
+	return value
+}
+
+func (f *rar5Fields) skip(n int) {
+	f.bytes(n)
+}
+
+func (f *rar5Fields) bytes(n int) []byte {
+	if n < 0 || f.pos+n > len(f.data) {
+		f.err = true
+		return nil
+	}
+	value := f.data[f.pos : f.pos+n]
+	f.pos += n
+	return value
+}
+
+// rar5Vint decodes RAR5's little-endian base-128 integers; n is 0 when data is too short.
+func rar5Vint(data []byte) (uint64, int) {
+	var value uint64
+	for i := 0; i < len(data) && i < 10; i++ {
+		value |= uint64(data[i]&0x7f) << (7 * i)
+		if data[i]&0x80 == 0 {
+			return value, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// AnalyzeRAR analyzes a stored entry of the RAR volume set starting at path without extracting
+// it; inner "" picks the largest entry. The report is named "<archive>/<entry>".
+func AnalyzeRAR(ctx context.Context, path, inner string, opts AnalyzeOptions) (Report, error) {
+	archive, err := OpenRAR(path)
+	if err != nil {
+		return Report{}, err
+	}
+	defer archive.Close()
+	if inner == "" {
+		inner = archive.Largest()
+		if inner == "" {
+			return Report{}, fmt.Errorf("rar: %s has no files", filepath.Base(path))
+		}
+	}
+	reader, size, err := archive.Open(inner)
+	if err != nil {
+		return Report{}, err
+	}
+	return AnalyzeReaderContext(ctx, reader, size, path+"/"+inner, opts)
+}
+
+// SplitRARPath splits "dir/archive.rar/inner/name.mkv" into the archive and entry names when
+// the archive exists; ok is false for ordinary paths.
+func SplitRARPath(path string) (archive, inner string, ok bool) {
+	slashed := filepath.ToSlash(path)
+	lower := strings.ToLower(slashed)
+	for search := 0; ; {
+		index := strings.Index(lower[search:], ".rar/")
+		if index < 0 {
+			return "", "", false
+		}
+		end := search + index + len(".rar")
+		candidate := filepath.FromSlash(slashed[:end])
+		if info, err := os.Stat(candidate); err == nil && info.Mode().IsRegular() {
+			return candidate, slashed[end+1:], true
+		}
+		search = end
+	}
+}
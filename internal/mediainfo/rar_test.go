@@ -0,0 +1,280 @@
+package mediainfo
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// rarTestEntry is one entry's slice in one test volume.
+type rarTestEntry struct {
+	name        string
+	size        int64
+	data        []byte
+	splitBefore bool
+	splitAfter  bool
+	compressed  bool
+	encrypted   bool
+}
+
+func writeRAR4Volume(t *testing.T, path string, entries []rarTestEntry, nextVolume bool) {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(rar4Signature)
+	block := func(kind byte, flags uint16, body []byte) {
+		head := make([]byte, 7, 7+len(body))
+		head[2] = kind
+		binary.LittleEndian.PutUint16(head[3:], flags)
+		binary.LittleEndian.PutUint16(head[5:], uint16(7+len(body)))
+		head = append(head, body...)
+		binary.LittleEndian.PutUint16(head[0:], uint16(crc32.ChecksumIEEE(head[2:])))
+		buf.Write(head)
+	}
+	block(rar4BlockMain, 0x0001, make([]byte, 6))
+	for _, entry := range entries {
+		flags := uint16(rar4LongBlock)
+		if entry.splitBefore {
+			flags |= rar4SplitBefore
+		}
+		if entry.splitAfter {
+			flags |= rar4SplitAfter
+		}
+		if entry.encrypted {
+			flags |= rar4FileEncrypted
+		}
+		method := byte(rar4MethodStore)
+		if entry.compressed {
+			method = 0x33
+		}
+		body := make([]byte, 25+len(entry.name))
+		binary.LittleEndian.PutUint32(body[0:], uint32(len(entry.data)))
+		binary.LittleEndian.PutUint32(body[4:], uint32(entry.size))
+		body[8] = 2 // Windows
+		body[17] = 29
+		body[18] = method
+		binary.LittleEndian.PutUint16(body[19:], uint16(len(entry.name)))
+		copy(body[25:], entry.name)
+		block(rar4BlockFile, flags, body)
+		buf.Write(entry.data)
+	}
+	endFlags := uint16(0)
+	if nextVolume {
+		endFlags = rar4EndNextVolume
+	}
+	block(rar4BlockEnd, endFlags, nil)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rar5AppendVint(buf []byte, value uint64) []byte {
+	for value >= 0x80 {
+		buf = append(buf, byte(value)|0x80)
+		value >>= 7
+	}
+	return append(buf, byte(value))
+}
+
+func writeRAR5Volume(t *testing.T, path string, volume int, entries []rarTestEntry, nextVolume bool) {
+	t.Helper()
+	var buf bytes.Buffer
+	buf.Write(rar5Signature)
+	block := func(header []byte) {
+		sized := rar5AppendVint(nil, uint64(len(header)))
+		sized = append(sized, header...)
+		crc := make([]byte, 4)
+		binary.LittleEndian.PutUint32(crc, crc32.ChecksumIEEE(sized))
+		buf.Write(crc)
+		buf.Write(sized)
+	}
+	main := rar5AppendVint(nil, 1)
+	main = rar5AppendVint(main, 0)
+	if volume == 0 {
+		main = rar5AppendVint(main, 0x1)
+	} else {
+		main = rar5AppendVint(main, 0x3)
+		main = rar5AppendVint(main, uint64(volume))
+	}
+	block(main)
+	for _, entry := range entries {
+		var extra []byte
+		if entry.encrypted {
+			record := rar5AppendVint(nil, rar5ExtraEncryption)
+			record = append(record, make([]byte, 4)...)
+			extra = append(rar5AppendVint(nil, uint64(len(record))), record...)
+		}
+		flags := uint64(rar5HasData)
+		if len(extra) > 0 {
+			flags |= rar5HasExtra
+		}
+		if entry.splitBefore {
+			flags |= rar5SplitBefore
+		}
+		if entry.splitAfter {
+			flags |= rar5SplitAfter
+		}
+		header := rar5AppendVint(nil, rar5HeaderFile)
+		header = rar5AppendVint(header, flags)
+		if len(extra) > 0 {
+			header = rar5AppendVint(header, uint64(len(extra)))
+		}
+		header = rar5AppendVint(header, uint64(len(entry.data)))
+		header = rar5AppendVint(header, 0) // file flags
+		header = rar5AppendVint(header, uint64(entry.size))
+		header = rar5AppendVint(header, 0x20)
+		compression := uint64(0)
+		if entry.compressed {
+			compression = 3 << 7
+		}
+		header = rar5AppendVint(header, compression)
+		header = rar5AppendVint(header, 1) // Unix
+		header = rar5AppendVint(header, uint64(len(entry.name)))
+		header = append(header, entry.name...)
+		header = append(header, extra...)
+		block(header)
+		buf.Write(entry.data)
+	}
+	end := rar5AppendVint(nil, rar5HeaderEnd)
+	end = rar5AppendVint(end, 0)
+	if nextVolume {
+		end = rar5AppendVint(end, rar5EndNextVolume)
+	} else {
+		end = rar5AppendVint(end, 0)
+	}
+	block(end)
+	if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// splitRAREntry cuts data into the per-volume slices of one stored entry.
+func splitRAREntry(name string, data []byte, volumes int) []rarTestEntry {
+	var parts []rarTestEntry
+	chunk := (len(data) + volumes - 1) / volumes
+	for i := range volumes {
+		from, to := i*chunk, min((i+1)*chunk, len(data))
+		parts = append(parts, rarTestEntry{
+			name: name, size: int64(len(data)), data: data[from:to],
+			splitBefore: i > 0, splitAfter: i < volumes-1,
+		})
+	}
+	return parts
+}
+
+func TestAnalyzeRARVolumeSets(t *testing.T) {
+	media, err := os.ReadFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local, err := AnalyzeFile("samples/sample.mkv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nfo := rarTestEntry{name: "release.nfo", size: 5, data: []byte("hello")}
+
+	dir := t.TempDir()
+	parts := splitRAREntry("Release/movie.mkv", media, 3)
+	parts[0].name, parts[1].name, parts[2].name = `Release\movie.mkv`, `Release\movie.mkv`, `Release\movie.mkv`
+	writeRAR4Volume(t, filepath.Join(dir, "old.rar"), []rarTestEntry{nfo, parts[0]}, true)
+	writeRAR4Volume(t, filepath.Join(dir, "old.r00"), parts[1:2], true)
+	writeRAR4Volume(t, filepath.Join(dir, "old.r01"), parts[2:], false)
+
+	parts = splitRAREntry("Release/movie.mkv", media, 3)
+	for i, part := range parts {
+		entries := []rarTestEntry{part}
+		if i == 0 {
+			entries = append([]rarTestEntry{nfo}, entries...)
+		}
+		writeRAR5Volume(t, filepath.Join(dir, "new.part"+string(rune('1'+i))+".rar"), i, entries, i < 2)
+	}
+
+	for _, archive := range []string{"old.rar", "new.part1.rar"} {
+		path := filepath.Join(dir, archive)
+		opened, err := OpenRAR(path)
+		if err != nil {
+			t.Fatalf("%s: %v", archive, err)
+		}
+		if len(opened.Files) != 2 || opened.Files[1].Name != "Release/movie.mkv" || len(opened.volumes) != 3 {
+			t.Fatalf("%s: files=%+v volumes=%d", archive, opened.Files, len(opened.volumes))
+		}
+		opened.Close()
+
+		report, err := AnalyzeRAR(context.Background(), path, "", AnalyzeOptions{})
+		if err != nil {
+			t.Fatalf("%s: %v", archive, err)
+		}
+		if want := path + "/Release/movie.mkv"; findField(report.General.Fields, "Complete name") != want {
+			t.Fatalf("%s: Complete name=%q, want %q", archive, findField(report.General.Fields, "Complete name"), want)
+		}
+		if got, want := stripTextRef(RenderText([]Report{report})), stripTextRef(RenderText([]Report{local})); got != want {
+			t.Fatalf("%s: text differs from the unpacked file:\n%s\nwant:\n%s", archive, got, want)
+		}
+		if _, err := AnalyzeRAR(context.Background(), path, "release.nfo", AnalyzeOptions{}); err != nil {
+			t.Fatalf("%s: small entry: %v", archive, err)
+		}
+	}
+
+	if _, err := OpenRAR(filepath.Join(dir, "new.part2.rar")); err == nil || !strings.Contains(err.Error(), "first volume") {
+		t.Fatalf("err=%v, want a not-the-first-volume error", err)
+	}
+	os.Remove(filepath.Join(dir, "old.r01"))
+	if _, err := OpenRAR(filepath.Join(dir, "old.rar")); err == nil || !strings.Contains(err.Error(), "missing volume old.r01") {
+		t.Fatalf("err=%v, want a missing-volume error", err)
+	}
+}
+
+func TestAnalyzeRARUnsupportedEntries(t *testing.T) {
+	dir := t.TempDir()
+	data := []byte("packed bytes")
+	writeRAR4Volume(t, filepath.Join(dir, "packed.rar"), []rarTestEntry{{name: "movie.mkv", size: 100, data: data, compressed: true}}, false)
+	writeRAR5Volume(t, filepath.Join(dir, "packed5.rar"), 0, []rarTestEntry{{name: "movie.mkv", size: 100, data: data, compressed: true}}, false)
+	writeRAR5Volume(t, filepath.Join(dir, "locked.rar"), 0, []rarTestEntry{{name: "movie.mkv", size: 12, data: data, encrypted: true}}, false)
+	for name, want := range map[string]string{
+		"packed.rar":  "compressed",
+		"packed5.rar": "compressed",
+		"locked.rar":  "encrypted",
+	} {
+		_, err := AnalyzeRAR(context.Background(), filepath.Join(dir, name), "", AnalyzeOptions{})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Fatalf("%s: err=%v, want %q", name, err, want)
+		}
+	}
+}
+
+func TestNextRARVolume(t *testing.T) {
+	for _, tc := range []struct {
+		path  string
+		first bool
+		want  string
+	}{
+		{"a/x.part1.rar", true, "a/x.part2.rar"},
+		{"x.part09.rar", false, "x.part10.rar"},
+		{"x.PART001.RAR", true, "x.PART002.RAR"},
+		{"x.rar", true, "x.r00"},
+		{"X.RAR", true, "X.R00"},
+		{"x.r00", false, "x.r01"},
+		{"x.r99", false, "x.s00"},
+		{"x.mkv", false, ""},
+	} {
+		if got := nextRARVolume(tc.path, tc.first); got != tc.want {
+			t.Fatalf("nextRARVolume(%q)=%q, want %q", tc.path, got, tc.want)
+		}
+	}
+}
+
+func TestSplitRARPath(t *testing.T) {
+	dir := t.TempDir()
+	archive := filepath.Join(dir, "a.rar")
+	os.WriteFile(archive, rar5Signature, 0o644)
+	if got, inner, ok := SplitRARPath(archive + "/Release/movie.mkv"); !ok || got != archive || inner != "Release/movie.mkv" {
+		t.Fatalf("SplitRARPath=%q, %q, %v", got, inner, ok)
+	}
+	if _, _, ok := SplitRARPath(filepath.Join(dir, "b.rar", "movie.mkv")); ok {
+		t.Fatalf("split a path whose archive does not exist")
+	}
+}
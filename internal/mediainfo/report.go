@@ -7,6 +7,7 @@ const (
 	StreamVideo   StreamKind = "Video"
 	StreamAudio   StreamKind = "Audio"
 	StreamText    StreamKind = "Text"
+	StreamOther   StreamKind = "Other"
 	StreamImage   StreamKind = "Image"
 	StreamMenu    StreamKind = "Menu"
 )
@@ -28,10 +29,22 @@ type Stream struct {
 	mkvHeaderStripBytes []byte
 	mkvDolbyVision      dolbyVisionConfig
 	mkvHasDolbyVision   bool
+	// parsedJSON holds the ordered raw fields of a stream read back by ParseJSONReport or
+	// ParseXMLReport; when set, JSON/XML rendering reproduces it instead of deriving from Fields.
+	parsedJSON []jsonKV
 }
 
 type Report struct {
 	Ref     string
 	General Stream
 	Streams []Stream
+	// Incomplete is set when analysis was cut short by context cancellation or deadline.
+	Incomplete bool
+	// virtual reports were analyzed without a file on disk (AnalyzeReader), so Ref cannot be
+	// used to stat the file; fileSize carries the analyzed size instead.
+	virtual  bool
+	fileSize int64
+	// sibling reports also read another file (an MP4 init segment found next to the media
+	// segment), so they are not cached under the analyzed file's identity alone.
+	sibling bool
 }
@@ -0,0 +1,351 @@
+package mediainfo
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ParseJSONReport reads MediaInfo JSON output ({"media":{"track":[...]}}, or an array of such
+// documents for several files) back into reports. Track order, field order and "extra" blocks are
+// kept, so JSON and XML re-render the original fields; text fields are derived from them.
+func ParseJSONReport(data []byte) ([]Report, error) {
+	root, err := parseOrderedJSON(string(bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})))
+	if err != nil {
+		return nil, fmt.Errorf("parse json report: %w", err)
+	}
+	documents := []orderedValue{root}
+	if root.kind == orderedArray {
+		documents = root.arr
+	}
+	var reports []Report
+	for _, document := range documents {
+		media, ok := orderedMember(document, "media")
+		if !ok || media.kind != orderedObject {
+			return nil, errors.New("parse json report: missing media object")
+		}
+		ref, _ := orderedMember(media, "@ref")
+		tracks, _ := orderedMember(media, "track")
+		if tracks.kind == orderedObject {
+			tracks = orderedValue{kind: orderedArray, arr: []orderedValue{tracks}}
+		}
+		var parsed []parsedTrack
+		for _, track := range tracks.arr {
+			if track.kind != orderedObject {
+				return nil, errors.New("parse json report: track is not an object")
+			}
+			var entry parsedTrack
+			for _, kv := range track.obj {
+				switch {
+				case kv.key == "@type":
+					entry.kind = StreamKind(kv.val.str)
+				case kv.key == "@typeorder":
+				case kv.val.kind == orderedString:
+					entry.fields = append(entry.fields, jsonKV{Key: kv.key, Val: kv.val.str})
+				default:
+					entry.fields = append(entry.fields, jsonKV{Key: kv.key, Val: renderOrderedJSON(kv.val), Raw: true})
+				}
+			}
+			parsed = append(parsed, entry)
+		}
+		report, err := reportFromParsedTracks(ref.str, parsed)
+		if err != nil {
+			return nil, fmt.Errorf("parse json report: %w", err)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// ParseXMLReport reads MediaInfo XML output (<MediaInfo><media ref="..."><track type="...">)
+// back into reports, one per <media> element. Nested elements such as <extra> become raw JSON
+// objects, as RenderXML expects.
+func ParseXMLReport(data []byte) ([]Report, error) {
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	var reports []Report
+	sawRoot := false
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse xml report: %w", err)
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "MediaInfo":
+			sawRoot = true
+		case "media":
+			report, err := parseXMLMedia(dec, start)
+			if err != nil {
+				return nil, fmt.Errorf("parse xml report: %w", err)
+			}
+			reports = append(reports, report)
+		default:
+			if err := dec.Skip(); err != nil {
+				return nil, fmt.Errorf("parse xml report: %w", err)
+			}
+		}
+	}
+	if !sawRoot {
+		return nil, errors.New("parse xml report: missing MediaInfo element")
+	}
+	return reports, nil
+}
+
+type parsedTrack struct {
+	kind   StreamKind
+	fields []jsonKV
+}
+
+func parseXMLMedia(dec *xml.Decoder, start xml.StartElement) (Report, error) {
+	ref := xmlAttrValue(start, "ref")
+	var tracks []parsedTrack
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return Report{}, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			if t.Name.Local != "track" {
+				if err := dec.Skip(); err != nil {
+					return Report{}, err
+				}
+				continue
+			}
+			track := parsedTrack{kind: StreamKind(xmlAttrValue(t, "type"))}
+			value, err := parseXMLElementValue(dec)
+			if err != nil {
+				return Report{}, err
+			}
+			for _, kv := range value.obj {
+				if kv.val.kind == orderedString {
+					track.fields = append(track.fields, jsonKV{Key: kv.key, Val: kv.val.str})
+				} else {
+					track.fields = append(track.fields, jsonKV{Key: kv.key, Val: renderOrderedJSON(kv.val), Raw: true})
+				}
+			}
+			tracks = append(tracks, track)
+		case xml.EndElement:
+			return reportFromParsedTracks(ref, tracks)
+		}
+	}
+}
+
+// parseXMLElementValue reads the content of the current element: text when it has no child
+// elements, otherwise an ordered object of its children.
+func parseXMLElementValue(dec *xml.Decoder) (orderedValue, error) {
+	var text strings.Builder
+	var obj []orderedKV
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return orderedValue{}, err
+		}
+		switch t := tok.(type) {
+		case xml.CharData:
+			text.Write(t)
+		case xml.StartElement:
+			child, err := parseXMLElementValue(dec)
+			if err != nil {
+				return orderedValue{}, err
+			}
+			obj = append(obj, orderedKV{key: t.Name.Local, val: child})
+		case xml.EndElement:
+			if obj != nil {
+				return orderedValue{kind: orderedObject, obj: obj}, nil
+			}
+			return orderedValue{kind: orderedString, str: strings.TrimSpace(text.String())}, nil
+		}
+	}
+}
+
+func xmlAttrValue(start xml.StartElement, name string) string {
+	for _, attr := range start.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+func orderedMember(value orderedValue, key string) (orderedValue, bool) {
+	for _, kv := range value.obj {
+		if kv.key == key {
+			return kv.val, true
+		}
+	}
+	return orderedValue{}, false
+}
+
+// renderOrderedJSON serializes an ordered value compactly, keeping key order.
+func renderOrderedJSON(value orderedValue) string {
+	switch value.kind {
+	case orderedObject:
+		var buf bytes.Buffer
+		buf.WriteString("{")
+		for i, kv := range value.obj {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			buf.WriteString(renderJSONString(kv.key))
+			buf.WriteString(":")
+			buf.WriteString(renderOrderedJSON(kv.val))
+		}
+		buf.WriteString("}")
+		return buf.String()
+	case orderedArray:
+		items := make([]string, 0, len(value.arr))
+		for _, item := range value.arr {
+			items = append(items, renderOrderedJSON(item))
+		}
+		return "[" + strings.Join(items, ",") + "]"
+	default:
+		return renderJSONString(value.str)
+	}
+}
+
+func reportFromParsedTracks(ref string, tracks []parsedTrack) (Report, error) {
+	report := Report{Ref: ref, virtual: true}
+	sawGeneral := false
+	for _, track := range tracks {
+		if track.kind == "" {
+			return Report{}, errors.New("track without a type")
+		}
+		stream := Stream{Kind: track.kind, parsedJSON: track.fields}
+		if track.kind == StreamGeneral {
+			if sawGeneral {
+				return Report{}, errors.New("more than one General track")
+			}
+			sawGeneral = true
+			report.General = stream
+			if size, err := strconv.ParseInt(jsonFieldValue(track.fields, "FileSize"), 10, 64); err == nil {
+				report.fileSize = size
+			}
+			continue
+		}
+		report.Streams = append(report.Streams, stream)
+	}
+	if !sawGeneral {
+		report.General = Stream{Kind: StreamGeneral, parsedJSON: []jsonKV{}}
+	}
+	report.General.Fields = parsedTextFields(StreamGeneral, report.General.parsedJSON, report.fileSize)
+	if ref != "" {
+		report.General.Fields = append([]Field{{Name: "Complete name", Value: ref}}, report.General.Fields...)
+	}
+	for i := range report.Streams {
+		report.Streams[i].Fields = parsedTextFields(report.Streams[i].Kind, report.Streams[i].parsedJSON, report.fileSize)
+	}
+	return report, nil
+}
+
+// parsedTextFields derives text output fields from raw values, using the field registry labels.
+// Fields without a text label (and "extra" objects) only appear in JSON/XML, as they do when
+// rendering an analyzed file.
+func parsedTextFields(kind StreamKind, fields []jsonKV, fileSize int64) []Field {
+	labels := map[string]string{}
+	for _, info := range FieldRegistry() {
+		if info.Kind == kind && info.Text != "" {
+			labels[info.Name] = info.Text
+		}
+	}
+	var out []Field
+	for _, field := range fields {
+		label := labels[field.Key]
+		if label == "" || field.Raw || field.Key == "Format_Level" || field.Key == "Format_Tier" {
+			continue
+		}
+		value := parsedDisplayValue(field.Key, field.Val, fields, fileSize)
+		if value != "" {
+			out = append(out, Field{Name: label, Value: value})
+		}
+	}
+	return out
+}
+
+func parsedDisplayValue(key, value string, fields []jsonKV, fileSize int64) string {
+	switch key {
+	case "Format_Profile":
+		if level := jsonFieldValue(fields, "Format_Level"); level != "" {
+			if _, err := strconv.ParseFloat(level, 64); err == nil {
+				level = "L" + level
+			}
+			value += "@" + level
+		}
+		if tier := jsonFieldValue(fields, "Format_Tier"); tier != "" {
+			value += "@" + tier
+		}
+		return value
+	case "BitRate_Mode", "OverallBitRate_Mode", "FrameRate_Mode":
+		switch value {
+		case "CBR", "CFR":
+			return "Constant"
+		case "VBR", "VFR":
+			return "Variable"
+		}
+		return value
+	case "Language":
+		return formatLanguage(value)
+	}
+	number, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return value
+	}
+	switch {
+	case fullDurationKeys[key]:
+		return formatDuration(number)
+	case key == "FileSize":
+		return formatBytes(int64(number))
+	case key == "StreamSize" || key == "Source_StreamSize":
+		if display := formatStreamSize(int64(number), fileSize); display != "" {
+			return display
+		}
+		return formatBytes(int64(number))
+	case strings.HasPrefix(key, "BitRate") || strings.HasPrefix(key, "OverallBitRate"):
+		return formatBitrate(number)
+	case key == "Width" || key == "Height":
+		return formatPixels(uint64(number))
+	case key == "Channels":
+		return formatChannels(uint64(number))
+	case key == "SamplingRate":
+		return formatSampleRate(number)
+	case key == "BitDepth":
+		return formatBitDepth(uint8(number))
+	case key == "FrameRate":
+		num, _ := strconv.ParseUint(jsonFieldValue(fields, "FrameRate_Num"), 10, 32)
+		den, _ := strconv.ParseUint(jsonFieldValue(fields, "FrameRate_Den"), 10, 32)
+		if num > 0 && den > 1 {
+			return formatFrameRateRatio(uint32(num), uint32(den))
+		}
+		return formatFrameRate(number)
+	case key == "DisplayAspectRatio":
+		return parsedAspectRatio(number)
+	case key == "Format_Version":
+		return "Version " + value
+	}
+	return value
+}
+
+func parsedAspectRatio(ratio float64) string {
+	for _, common := range []struct {
+		value float64
+		text  string
+	}{
+		{1, "1:1"}, {4.0 / 3, "4:3"}, {1.5, "3:2"}, {5.0 / 4, "5:4"}, {16.0 / 9, "16:9"},
+	} {
+		if math.Abs(ratio-common.value) < 0.01 {
+			return common.text
+		}
+	}
+	return strconv.FormatFloat(ratio, 'f', 2, 64) + ":1"
+}
@@ -0,0 +1,115 @@
+package mediainfo
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseReportRoundTrip(t *testing.T) {
+	paths, _ := filepath.Glob(filepath.Join("samples", "*"))
+	if len(paths) == 0 {
+		t.Skip("no samples")
+	}
+	var reports []Report
+	for _, path := range paths {
+		report, err := AnalyzeFile(path)
+		if err != nil {
+			continue
+		}
+		reports = append(reports, report)
+
+		jsonOut := RenderJSON([]Report{report})
+		parsed, err := ParseJSONReport([]byte(jsonOut))
+		if err != nil {
+			t.Fatalf("%s: ParseJSONReport: %v", path, err)
+		}
+		if got := RenderJSON(parsed); got != jsonOut {
+			t.Fatalf("%s: JSON round trip differs:\n%s\nwant:\n%s", path, got, jsonOut)
+		}
+
+		xmlOut := RenderXML([]Report{report})
+		parsed, err = ParseXMLReport([]byte(xmlOut))
+		if err != nil {
+			t.Fatalf("%s: ParseXMLReport: %v", path, err)
+		}
+		if got := RenderXML(parsed); got != xmlOut {
+			t.Fatalf("%s: XML round trip differs:\n%s\nwant:\n%s", path, got, xmlOut)
+		}
+	}
+
+	multi := RenderJSON(reports)
+	parsed, err := ParseJSONReport([]byte(multi))
+	if err != nil || len(parsed) != len(reports) {
+		t.Fatalf("multi-file JSON: %d reports, err=%v", len(parsed), err)
+	}
+	if got := RenderJSON(parsed); got != multi {
+		t.Fatalf("multi-file JSON round trip differs")
+	}
+}
+
+func TestParseJSONReportText(t *testing.T) {
+	doc := `{"creatingLibrary":{"name":"MediaInfoLib","version":"24.06","url":"https://mediaarea.net/MediaInfo"},
+"media":{"@ref":"/archive/movie.mkv","track":[
+{"@type":"General","VideoCount":"1","AudioCount":"2","Format":"Matroska","FileSize":"1000000","Duration":"5.005","OverallBitRate":"1598401"},
+{"@type":"Video","StreamOrder":"0","ID":"1","Format":"HEVC","Format_Profile":"Main 10","Format_Level":"5.1","Format_Tier":"High","Width":"3840","Height":"2160","DisplayAspectRatio":"1.778","FrameRate_Mode":"CFR","FrameRate":"23.976","FrameRate_Num":"24000","FrameRate_Den":"1001","StreamSize":"900000","Language":"en"},
+{"@type":"Audio","@typeorder":"1","StreamOrder":"1","ID":"2","Format":"E-AC-3","Channels":"6","SamplingRate":"48000","BitRate_Mode":"CBR","BitRate":"640000","extra":{"ComplexityIndex":"16","nested":{"a":"1"}}},
+{"@type":"Audio","@typeorder":"2","StreamOrder":"2","ID":"3","Format":"AAC","Channels":"2"}
+]}}`
+	reports, err := ParseJSONReport([]byte(doc))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Ref != "/archive/movie.mkv" || len(reports[0].Streams) != 3 {
+		t.Fatalf("unexpected reports: %+v", reports)
+	}
+	text := RenderText(reports)
+	for _, want := range []string{
+		"Complete name                            : /archive/movie.mkv\n",
+		"Duration                                 : 5 s 5 ms\n",
+		"Format profile                           : Main 10@L5.1@High\n",
+		"Width                                    : 3 840 pixels\n",
+		"Display aspect ratio                     : 16:9\n",
+		"Frame rate mode                          : Constant\n",
+		"Frame rate                               : 23.976 (24000/1001) FPS\n",
+		"Stream size                              : 879 KiB (90%)\n",
+		"Language                                 : English\n",
+		"Audio #1\n",
+		"Channel(s)                               : 6 channels\n",
+		"Sampling rate                            : 48.0 kHz\n",
+		"Bit rate mode                            : Constant\n",
+		"Bit rate                                 : 640 kb/s\n",
+		"Audio #2\n",
+	} {
+		if !strings.Contains(text, want) {
+			t.Fatalf("missing %q in:\n%s", want, text)
+		}
+	}
+	xmlOut := RenderXML(reports)
+	if !strings.Contains(xmlOut, "<track type=\"Audio\" typeorder=\"1\">") || !strings.Contains(xmlOut, "<ComplexityIndex>16</ComplexityIndex>\n<nested>\n<a>1</a>\n</nested>") {
+		t.Fatalf("extra block not preserved:\n%s", xmlOut)
+	}
+	tracks := reports[0].Tracks()
+	if tracks.Video[0].Width != 3840 || tracks.Audio[0].BitRate != 640000 {
+		t.Fatalf("typed view of parsed report: %+v", tracks)
+	}
+}
+
+func TestParseReportErrors(t *testing.T) {
+	for name, doc := range map[string]string{
+		"not json":    `{"media":`,
+		"no media":    `{"creatingLibrary":{}}`,
+		"no type":     `{"media":{"track":[{"Format":"AVC"}]}}`,
+		"two general": `{"media":{"track":[{"@type":"General"},{"@type":"General"}]}}`,
+	} {
+		if _, err := ParseJSONReport([]byte(doc)); err == nil {
+			t.Fatalf("%s: expected an error", name)
+		}
+	}
+	if _, err := ParseXMLReport([]byte(`<Mediainfo version="0.7"><File/></Mediainfo>`)); err == nil {
+		t.Fatalf("expected an error for a document without a MediaInfo root")
+	}
+	if _, err := ParseXMLReport([]byte(`<MediaInfo><media><track type="Video"><Width>1</Width>`)); err == nil {
+		t.Fatalf("expected an error for truncated XML")
+	}
+}
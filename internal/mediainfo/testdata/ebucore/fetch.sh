@@ -0,0 +1,38 @@
+#!/usr/bin/env bash
+# Vendors the published EBUCore 1.8 schema (and the schemas it imports) for
+# TestRenderEBUCoreXSD. Imports are rewritten to the local copies so xmllint
+# can validate with --nonet. Commit the fetched files together with LICENSE.
+set -euo pipefail
+
+need() {
+  command -v "$1" >/dev/null 2>&1 || {
+    echo "missing dependency: $1" >&2
+    exit 1
+  }
+}
+
+need curl
+need sed
+
+here="$(cd "$(dirname "${BASH_SOURCE[0]}")" && pwd)"
+base="https://www.ebu.ch/metadata/schemas/EBUCore/20171009"
+
+curl -fsSL "$base/ebucore.xsd" -o "$here/ebucore.xsd"
+
+# Localize every remote import/include, following imports of the imported schemas too.
+pending=("$here/ebucore.xsd")
+while ((${#pending[@]})); do
+  file="${pending[0]}"
+  pending=("${pending[@]:1}")
+  for url in $(grep -o 'schemaLocation="https\{0,1\}://[^"]*"' "$file" | sed 's/^schemaLocation="//; s/"$//' | sort -u); do
+    name="$(basename "$url")"
+    if [[ ! -f "$here/$name" ]]; then
+      curl -fsSL "$url" -o "$here/$name"
+      pending+=("$here/$name")
+    fi
+    sed -i.bak "s|schemaLocation=\"$url\"|schemaLocation=\"$name\"|g" "$file"
+    rm -f "$file.bak"
+  done
+done
+
+echo "fetched into $here; add the EBU licence terms of the schema as $here/LICENSE" >&2
@@ -0,0 +1,294 @@
+package mediainfo
+
+import (
+	_ "embed"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//go:embed schema/tracks.schema.json
+var tracksJSONSchema string
+
+// TracksJSONSchema returns the JSON Schema (draft 2020-12) describing the JSON encoding of Tracks.
+func TracksJSONSchema() string {
+	return tracksJSONSchema
+}
+
+// Rational is an exact ratio such as 24000/1001.
+type Rational struct {
+	Num int64 `json:"num"`
+	Den int64 `json:"den"`
+}
+
+// Float64 returns the ratio as a float, or 0 when the denominator is zero.
+func (r Rational) Float64() float64 {
+	if r.Den == 0 {
+		return 0
+	}
+	return float64(r.Num) / float64(r.Den)
+}
+
+func (r Rational) String() string {
+	if r.Den == 1 {
+		return strconv.FormatInt(r.Num, 10)
+	}
+	return strconv.FormatInt(r.Num, 10) + "/" + strconv.FormatInt(r.Den, 10)
+}
+
+// Tracks is a typed view of a Report. Values come from the raw (JSON) fields, so sizes are bytes,
+// bit rates bits per second and durations exact; zero values mean the field was not reported.
+type Tracks struct {
+	General GeneralTrack `json:"general"`
+	Video   []VideoTrack `json:"video"`
+	Audio   []AudioTrack `json:"audio"`
+	Text    []TextTrack  `json:"text"`
+}
+
+type GeneralTrack struct {
+	Format             string        `json:"format,omitempty"`
+	FormatProfile      string        `json:"formatProfile,omitempty"`
+	FileSize           int64         `json:"fileSize,omitempty"`
+	Duration           time.Duration `json:"duration,omitempty"`
+	OverallBitRate     int64         `json:"overallBitRate,omitempty"`
+	FrameRate          Rational      `json:"frameRate"`
+	Title              string        `json:"title,omitempty"`
+	Movie              string        `json:"movie,omitempty"`
+	EncodedDate        string        `json:"encodedDate,omitempty"`
+	EncodedApplication string        `json:"encodedApplication,omitempty"`
+	EncodedLibrary     string        `json:"encodedLibrary,omitempty"`
+}
+
+type VideoTrack struct {
+	ID                      string        `json:"id,omitempty"`
+	Format                  string        `json:"format,omitempty"`
+	FormatProfile           string        `json:"formatProfile,omitempty"`
+	FormatLevel             string        `json:"formatLevel,omitempty"`
+	FormatTier              string        `json:"formatTier,omitempty"`
+	CodecID                 string        `json:"codecId,omitempty"`
+	Width                   int           `json:"width,omitempty"`
+	Height                  int           `json:"height,omitempty"`
+	PixelAspectRatio        float64       `json:"pixelAspectRatio,omitempty"`
+	DisplayAspectRatio      float64       `json:"displayAspectRatio,omitempty"`
+	FrameRateMode           string        `json:"frameRateMode,omitempty"`
+	FrameRate               Rational      `json:"frameRate"`
+	FrameCount              int64         `json:"frameCount,omitempty"`
+	Duration                time.Duration `json:"duration,omitempty"`
+	Delay                   time.Duration `json:"delay,omitempty"`
+	BitRateMode             string        `json:"bitRateMode,omitempty"`
+	BitRate                 int64         `json:"bitRate,omitempty"`
+	BitDepth                int           `json:"bitDepth,omitempty"`
+	ColorSpace              string        `json:"colorSpace,omitempty"`
+	ChromaSubsampling       string        `json:"chromaSubsampling,omitempty"`
+	ScanType                string        `json:"scanType,omitempty"`
+	ColorRange              string        `json:"colorRange,omitempty"`
+	ColorPrimaries          string        `json:"colorPrimaries,omitempty"`
+	TransferCharacteristics string        `json:"transferCharacteristics,omitempty"`
+	MatrixCoefficients      string        `json:"matrixCoefficients,omitempty"`
+	HDRFormat               string        `json:"hdrFormat,omitempty"`
+	StreamSize              int64         `json:"streamSize,omitempty"`
+	Title                   string        `json:"title,omitempty"`
+	Language                string        `json:"language,omitempty"`
+	Default                 bool          `json:"default"`
+	Forced                  bool          `json:"forced"`
+}
+
+type AudioTrack struct {
+	ID               string        `json:"id,omitempty"`
+	Format           string        `json:"format,omitempty"`
+	FormatCommercial string        `json:"formatCommercial,omitempty"`
+	FormatProfile    string        `json:"formatProfile,omitempty"`
+	FormatFeatures   string        `json:"formatFeatures,omitempty"`
+	CodecID          string        `json:"codecId,omitempty"`
+	Channels         int           `json:"channels,omitempty"`
+	ChannelLayout    string        `json:"channelLayout,omitempty"`
+	SamplingRate     int           `json:"samplingRate,omitempty"`
+	BitDepth         int           `json:"bitDepth,omitempty"`
+	BitRateMode      string        `json:"bitRateMode,omitempty"`
+	BitRate          int64         `json:"bitRate,omitempty"`
+	CompressionMode  string        `json:"compressionMode,omitempty"`
+	Duration         time.Duration `json:"duration,omitempty"`
+	Delay            time.Duration `json:"delay,omitempty"`
+	StreamSize       int64         `json:"streamSize,omitempty"`
+	Title            string        `json:"title,omitempty"`
+	Language         string        `json:"language,omitempty"`
+	Default          bool          `json:"default"`
+	Forced           bool          `json:"forced"`
+}
+
+type TextTrack struct {
+	ID           string        `json:"id,omitempty"`
+	Format       string        `json:"format,omitempty"`
+	CodecID      string        `json:"codecId,omitempty"`
+	MuxingMode   string        `json:"muxingMode,omitempty"`
+	Duration     time.Duration `json:"duration,omitempty"`
+	BitRate      int64         `json:"bitRate,omitempty"`
+	ElementCount int64         `json:"elementCount,omitempty"`
+	StreamSize   int64         `json:"streamSize,omitempty"`
+	Title        string        `json:"title,omitempty"`
+	Language     string        `json:"language,omitempty"`
+	Default      bool          `json:"default"`
+	Forced       bool          `json:"forced"`
+}
+
+// Tracks returns the typed view of the report's general, video, audio and text streams.
+func (r Report) Tracks() Tracks {
+	general := trackValues(buildJSONGeneralFields(r))
+	tracks := Tracks{
+		General: GeneralTrack{
+			Format:             general.str("Format"),
+			FormatProfile:      general.str("Format_Profile"),
+			FileSize:           general.int64("FileSize"),
+			Duration:           general.duration("Duration"),
+			OverallBitRate:     general.int64("OverallBitRate"),
+			FrameRate:          general.frameRate(),
+			Title:              general.str("Title"),
+			Movie:              general.str("Movie"),
+			EncodedDate:        general.str("Encoded_Date"),
+			EncodedApplication: general.str("Encoded_Application"),
+			EncodedLibrary:     general.str("Encoded_Library"),
+		},
+	}
+	containerFormat := findField(r.General.Fields, "Format")
+	for _, stream := range orderTracks(r.Streams) {
+		values := trackValues(buildJSONStreamFields(stream, 0, 0, containerFormat))
+		switch stream.Kind {
+		case StreamVideo:
+			hdr := values.str("HDR_Format")
+			if hdr == "" {
+				hdr = findField(stream.Fields, "HDR format")
+			}
+			tracks.Video = append(tracks.Video, VideoTrack{
+				ID:                      values.str("ID"),
+				Format:                  values.str("Format"),
+				FormatProfile:           values.str("Format_Profile"),
+				FormatLevel:             values.str("Format_Level"),
+				FormatTier:              values.str("Format_Tier"),
+				CodecID:                 values.str("CodecID"),
+				Width:                   int(values.int64("Width")),
+				Height:                  int(values.int64("Height")),
+				PixelAspectRatio:        values.float("PixelAspectRatio"),
+				DisplayAspectRatio:      values.float("DisplayAspectRatio"),
+				FrameRateMode:           values.str("FrameRate_Mode"),
+				FrameRate:               values.frameRate(),
+				FrameCount:              values.int64("FrameCount"),
+				Duration:                values.duration("Duration"),
+				Delay:                   values.duration("Delay"),
+				BitRateMode:             values.str("BitRate_Mode"),
+				BitRate:                 values.int64("BitRate"),
+				BitDepth:                int(values.int64("BitDepth")),
+				ColorSpace:              values.str("ColorSpace"),
+				ChromaSubsampling:       values.str("ChromaSubsampling"),
+				ScanType:                values.str("ScanType"),
+				ColorRange:              values.str("colour_range"),
+				ColorPrimaries:          values.str("colour_primaries"),
+				TransferCharacteristics: values.str("transfer_characteristics"),
+				MatrixCoefficients:      values.str("matrix_coefficients"),
+				HDRFormat:               hdr,
+				StreamSize:              values.int64("StreamSize"),
+				Title:                   values.str("Title"),
+				Language:                values.str("Language"),
+				Default:                 values.bool("Default"),
+				Forced:                  values.bool("Forced"),
+			})
+		case StreamAudio:
+			tracks.Audio = append(tracks.Audio, AudioTrack{
+				ID:               values.str("ID"),
+				Format:           values.str("Format"),
+				FormatCommercial: values.str("Format_Commercial_IfAny"),
+				FormatProfile:    values.str("Format_Profile"),
+				FormatFeatures:   values.str("Format_AdditionalFeatures"),
+				CodecID:          values.str("CodecID"),
+				Channels:         int(values.int64("Channels")),
+				ChannelLayout:    values.str("ChannelLayout"),
+				SamplingRate:     int(values.float("SamplingRate")),
+				BitDepth:         int(values.int64("BitDepth")),
+				BitRateMode:      values.str("BitRate_Mode"),
+				BitRate:          values.int64("BitRate"),
+				CompressionMode:  values.str("Compression_Mode"),
+				Duration:         values.duration("Duration"),
+				Delay:            values.duration("Delay"),
+				StreamSize:       values.int64("StreamSize"),
+				Title:            values.str("Title"),
+				Language:         values.str("Language"),
+				Default:          values.bool("Default"),
+				Forced:           values.bool("Forced"),
+			})
+		case StreamText:
+			tracks.Text = append(tracks.Text, TextTrack{
+				ID:           values.str("ID"),
+				Format:       values.str("Format"),
+				CodecID:      values.str("CodecID"),
+				MuxingMode:   values.str("MuxingMode"),
+				Duration:     values.duration("Duration"),
+				BitRate:      values.int64("BitRate"),
+				ElementCount: values.int64("ElementCount"),
+				StreamSize:   values.int64("StreamSize"),
+				Title:        values.str("Title"),
+				Language:     values.str("Language"),
+				Default:      values.bool("Default"),
+				Forced:       values.bool("Forced"),
+			})
+		}
+	}
+	return tracks
+}
+
+// trackValues looks up the raw values of a track's JSON fields.
+type trackValues []jsonKV
+
+func (v trackValues) str(key string) string {
+	for _, field := range v {
+		if field.Key == key && !field.Raw {
+			return field.Val
+		}
+	}
+	return ""
+}
+
+func (v trackValues) int64(key string) int64 {
+	value := v.str(key)
+	if parsed, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return parsed
+	}
+	// Some raw values are decimal ("48000.0") or lists ("2 / 6"); keep the leading number.
+	if parsed, err := strconv.ParseFloat(extractLeadingNumber(value), 64); err == nil {
+		return int64(math.Round(parsed))
+	}
+	return 0
+}
+
+func (v trackValues) float(key string) float64 {
+	parsed, _ := strconv.ParseFloat(strings.TrimSpace(v.str(key)), 64)
+	return parsed
+}
+
+func (v trackValues) bool(key string) bool {
+	return v.str(key) == "Yes"
+}
+
+func (v trackValues) duration(key string) time.Duration {
+	seconds, err := strconv.ParseFloat(v.str(key), 64)
+	if err != nil {
+		return 0
+	}
+	return time.Duration(math.Round(seconds * float64(time.Second)))
+}
+
+func (v trackValues) frameRate() Rational {
+	num, den := v.int64("FrameRate_Num"), v.int64("FrameRate_Den")
+	if num > 0 && den > 0 {
+		return Rational{Num: num, Den: den}
+	}
+	rate := v.float("FrameRate")
+	if rate <= 0 {
+		return Rational{}
+	}
+	if n, d := rationalizeFrameRate(rate); n > 0 && math.Abs(float64(n)/float64(d)-rate) < 0.0005 {
+		return Rational{Num: int64(n), Den: int64(d)}
+	}
+	n, d := uint64(math.Round(rate*1000)), uint64(1000)
+	g := gcd(n, d)
+	return Rational{Num: int64(n / g), Den: int64(d / g)}
+}
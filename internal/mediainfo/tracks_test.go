@@ -0,0 +1,207 @@
+package mediainfo
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReportTracks(t *testing.T) {
+	report, err := AnalyzeFile(filepath.Join("samples", "sample.mkv"))
+	if err != nil {
+		t.Fatalf("analyze: %v", err)
+	}
+	tracks := report.Tracks()
+	if tracks.General.Format != "Matroska" || tracks.General.Duration != 4021*time.Millisecond {
+		t.Fatalf("general=%+v", tracks.General)
+	}
+	if len(tracks.Video) != 1 || len(tracks.Audio) != 1 || len(tracks.Text) != 0 {
+		t.Fatalf("track counts video=%d audio=%d text=%d", len(tracks.Video), len(tracks.Audio), len(tracks.Text))
+	}
+	video := tracks.Video[0]
+	if video.Width != 640 || video.Height != 360 || video.FrameRate != (Rational{Num: 30000, Den: 1001}) {
+		t.Fatalf("video=%+v", video)
+	}
+	if video.Duration != 3970*time.Millisecond || video.BitDepth != 8 || video.ScanType != "Progressive" || video.ColorRange != "Limited" {
+		t.Fatalf("video=%+v", video)
+	}
+	audio := tracks.Audio[0]
+	if audio.Format != "AAC" || audio.FormatFeatures != "LC" || audio.Channels != 1 || audio.SamplingRate != 48000 || audio.Default {
+		t.Fatalf("audio=%+v", audio)
+	}
+}
+
+func TestTrackFrameRate(t *testing.T) {
+	cases := []struct {
+		fields []jsonKV
+		want   Rational
+	}{
+		{[]jsonKV{{Key: "FrameRate", Val: "23.976"}}, Rational{Num: 24000, Den: 1001}},
+		{[]jsonKV{{Key: "FrameRate", Val: "12.500"}}, Rational{Num: 25, Den: 2}},
+		{[]jsonKV{{Key: "FrameRate", Val: "25.000"}, {Key: "FrameRate_Num", Val: "50"}, {Key: "FrameRate_Den", Val: "2"}}, Rational{Num: 50, Den: 2}},
+		{nil, Rational{}},
+	}
+	for _, tc := range cases {
+		if got := trackValues(tc.fields).frameRate(); got != tc.want {
+			t.Fatalf("frameRate(%v)=%v, want %v", tc.fields, got, tc.want)
+		}
+	}
+	if got := (Rational{Num: 24000, Den: 1001}).String(); got != "24000/1001" {
+		t.Fatalf("String()=%q", got)
+	}
+}
+
+func TestTracksJSONSchemaMatchesTypes(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(TracksJSONSchema()), &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	defs := schema["$defs"].(map[string]any)
+	for def, typ := range map[string]reflect.Type{
+		"general":  reflect.TypeOf(GeneralTrack{}),
+		"video":    reflect.TypeOf(VideoTrack{}),
+		"audio":    reflect.TypeOf(AudioTrack{}),
+		"text":     reflect.TypeOf(TextTrack{}),
+		"rational": reflect.TypeOf(Rational{}),
+	} {
+		props := defs[def].(map[string]any)["properties"].(map[string]any)
+		var schemaNames, structNames []string
+		for name := range props {
+			schemaNames = append(schemaNames, name)
+		}
+		for i := 0; i < typ.NumField(); i++ {
+			name, _, _ := strings.Cut(typ.Field(i).Tag.Get("json"), ",")
+			structNames = append(structNames, name)
+		}
+		sort.Strings(schemaNames)
+		sort.Strings(structNames)
+		if !reflect.DeepEqual(schemaNames, structNames) {
+			t.Fatalf("%s schema properties %v do not match struct fields %v", def, schemaNames, structNames)
+		}
+	}
+}
+
+func TestTracksJSONValidatesAgainstSchema(t *testing.T) {
+	var schema map[string]any
+	if err := json.Unmarshal([]byte(TracksJSONSchema()), &schema); err != nil {
+		t.Fatal(err)
+	}
+	paths, _ := filepath.Glob(filepath.Join("samples", "*"))
+	for _, path := range paths {
+		report, err := AnalyzeFile(path)
+		if err != nil {
+			continue
+		}
+		data, err := json.Marshal(report.Tracks())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var doc any
+		if err := json.Unmarshal(data, &doc); err != nil {
+			t.Fatal(err)
+		}
+		if err := validateSchema(schema, schema, doc, "$"); err != "" {
+			t.Fatalf("%s: %s", path, err)
+		}
+	}
+}
+
+// validateSchema checks the JSON Schema subset the tracks schema uses: $ref, type, properties,
+// required, additionalProperties=false, items and minimum.
+func validateSchema(root, schema map[string]any, value any, path string) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		def := strings.TrimPrefix(ref, "#/$defs/")
+		return validateSchema(root, root["$defs"].(map[string]any)[def].(map[string]any), value, path)
+	}
+	if types, ok := schema["type"]; ok {
+		var allowed []string
+		switch typ := types.(type) {
+		case string:
+			allowed = []string{typ}
+		case []any:
+			for _, entry := range typ {
+				allowed = append(allowed, entry.(string))
+			}
+		}
+		matched := false
+		for _, typ := range allowed {
+			matched = matched || schemaTypeMatches(typ, value)
+		}
+		if !matched {
+			return path + ": " + reflect.TypeOf(value).String() + " is not " + strings.Join(allowed, "|")
+		}
+	}
+	if minimum, ok := schema["minimum"].(float64); ok {
+		if number, ok := value.(float64); ok && number < minimum {
+			return path + ": below minimum"
+		}
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		props, _ := schema["properties"].(map[string]any)
+		for _, name := range schemaStrings(schema["required"]) {
+			if _, ok := typed[name]; !ok {
+				return path + ": missing required " + name
+			}
+		}
+		for name, child := range typed {
+			prop, ok := props[name].(map[string]any)
+			if !ok {
+				if schema["additionalProperties"] == false {
+					return path + ": unexpected property " + name
+				}
+				continue
+			}
+			if err := validateSchema(root, prop, child, path+"."+name); err != "" {
+				return err
+			}
+		}
+	case []any:
+		items, _ := schema["items"].(map[string]any)
+		for _, child := range typed {
+			if err := validateSchema(root, items, child, path+"[]"); err != "" {
+				return err
+			}
+		}
+	}
+	return ""
+}
+
+func schemaTypeMatches(typ string, value any) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == float64(int64(number))
+	case "null":
+		return value == nil
+	}
+	return false
+}
+
+func schemaStrings(value any) []string {
+	list, _ := value.([]any)
+	out := make([]string, 0, len(list))
+	for _, entry := range list {
+		out = append(out, entry.(string))
+	}
+	return out
+}
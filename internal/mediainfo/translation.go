@@ -0,0 +1,114 @@
+package mediainfo
+
+import (
+	"bufio"
+	"bytes"
+	"embed"
+	"fmt"
+	"strings"
+)
+
+//go:embed locale/*.csv
+var bundledLocales embed.FS
+
+// Translation maps the English labels and display values of the text output to another
+// language, in the spirit of MediaInfo's --Language. A nil *Translation leaves text unchanged.
+type Translation struct {
+	entries map[string]string
+}
+
+// LoadTranslation returns the bundled table for language ("de", "fr_FR", "es-ES", ...).
+// "raw", "en" and "" return nil, which keeps the untranslated identifiers.
+func LoadTranslation(language string) (*Translation, error) {
+	code := strings.ToLower(strings.TrimSpace(language))
+	if base, _, ok := strings.Cut(strings.ReplaceAll(code, "_", "-"), "-"); ok {
+		code = base
+	}
+	switch code {
+	case "", "raw", "en":
+		return nil, nil
+	}
+	data, err := bundledLocales.ReadFile("locale/" + code + ".csv")
+	if err != nil {
+		return nil, fmt.Errorf("language not available: %s", language)
+	}
+	return ParseTranslation(data)
+}
+
+// ParseTranslation reads a translation table: one "English;Translation" pair per line.
+// Blank lines and lines starting with "#" are ignored.
+func ParseTranslation(data []byte) (*Translation, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	t := &Translation{entries: map[string]string{}}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		key, value, ok := strings.Cut(text, ";")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("translation line %d: expected \"English;Translation\"", line)
+		}
+		t.entries[key] = value
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Translate returns the translation of an exact label or value, or s when there is none.
+func (t *Translation) Translate(s string) string {
+	if t == nil {
+		return s
+	}
+	if translated, ok := t.entries[s]; ok && translated != "" {
+		return translated
+	}
+	return s
+}
+
+func (t *Translation) title(kind StreamKind, index, total int) string {
+	title := streamTitle(kind, index, total)
+	if t == nil {
+		return title
+	}
+	return t.Translate(string(kind)) + strings.TrimPrefix(title, string(kind))
+}
+
+func (t *Translation) stream(stream Stream) Stream {
+	if t == nil {
+		return stream
+	}
+	out := stream
+	out.Fields = make([]Field, len(stream.Fields))
+	for i, field := range stream.Fields {
+		out.Fields[i] = Field{Name: t.Translate(field.Name), Value: t.value(field.Value)}
+	}
+	return out
+}
+
+// value translates whole values ("Constant"), each part of "a / b" lists, a trailing unit after a
+// number ("6 channels") and a language name followed by a region ("English (US)").
+func (t *Translation) value(value string) string {
+	if translated := t.Translate(value); translated != value {
+		return translated
+	}
+	if strings.Contains(value, " / ") {
+		parts := strings.Split(value, " / ")
+		for i, part := range parts {
+			parts[i] = t.Translate(part)
+		}
+		return strings.Join(parts, " / ")
+	}
+	if space := strings.LastIndexByte(value, ' '); space > 0 && value[0] >= '0' && value[0] <= '9' {
+		return value[:space+1] + t.Translate(value[space+1:])
+	}
+	if name, region, ok := strings.Cut(value, " ("); ok {
+		return t.Translate(name) + " (" + region
+	}
+	return value
+}
@@ -0,0 +1,90 @@
+package mediainfo
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadTranslation(t *testing.T) {
+	for _, raw := range []string{"", "raw", "en", "en-US"} {
+		tr, err := LoadTranslation(raw)
+		if err != nil || tr != nil {
+			t.Fatalf("LoadTranslation(%q)=%v, %v; want nil translation", raw, tr, err)
+		}
+	}
+	for _, code := range []string{"de", "fr_FR", "ES-es"} {
+		tr, err := LoadTranslation(code)
+		if err != nil || tr == nil {
+			t.Fatalf("LoadTranslation(%q) failed: %v", code, err)
+		}
+		if tr.Translate("Bit rate mode") == "Bit rate mode" {
+			t.Fatalf("%s table does not translate field labels", code)
+		}
+	}
+	if _, err := LoadTranslation("tlh"); err == nil {
+		t.Fatalf("expected an error for an unknown language")
+	}
+}
+
+func TestTranslationValues(t *testing.T) {
+	tr, err := LoadTranslation("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cases := map[string]string{
+		"Constant":            "konstant",
+		"Progressive":         "progressiv",
+		"6 channels":          "6 Kanäle",
+		"1 920 pixels":        "1 920 Pixel",
+		"English / French":    "Englisch / Französisch",
+		"English (US)":        "Englisch (US)",
+		"AVC":                 "AVC",
+		"29.970 (30000/1001)": "29.970 (30000/1001)",
+	}
+	for in, want := range cases {
+		if got := tr.value(in); got != want {
+			t.Fatalf("value(%q)=%q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseTranslation(t *testing.T) {
+	tr, err := ParseTranslation([]byte("\xEF\xBB\xBF# custom\r\nAudio;Ton\r\n\r\nChannel(s);Spuren\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tr.Translate("Audio") != "Ton" || tr.Translate("Channel(s)") != "Spuren" {
+		t.Fatalf("custom table not applied: %v", tr.entries)
+	}
+	if _, err := ParseTranslation([]byte("Audio;Ton\nbroken line\n")); err == nil || !strings.Contains(err.Error(), "line 2") {
+		t.Fatalf("expected a line 2 error, got %v", err)
+	}
+}
+
+func TestRenderTextTranslated(t *testing.T) {
+	report := Report{
+		General: Stream{Kind: StreamGeneral, Fields: []Field{{Name: "Complete name", Value: "a.mkv"}}},
+		Streams: []Stream{
+			{Kind: StreamAudio, Fields: []Field{{Name: "Bit rate mode", Value: "Variable"}}},
+			{Kind: StreamAudio, Fields: []Field{{Name: "Channel(s)", Value: "2 channels"}}},
+		},
+	}
+	tr, err := LoadTranslation("fr")
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := RenderTextTranslated([]Report{report}, tr)
+	for _, want := range []string{
+		"Général\n",
+		"Nom complet                              : a.mkv\n",
+		"Audio #1\nType de débit                            : Variable\n",
+		"Audio #2\nCanaux                                   : 2 canaux\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("missing %q in:\n%s", want, out)
+		}
+	}
+	if RenderTextTranslated([]Report{report}, nil) != RenderText([]Report{report}) {
+		t.Fatalf("nil translation should match RenderText")
+	}
+}
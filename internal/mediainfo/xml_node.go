@@ -0,0 +1,128 @@
+package mediainfo
+
+import (
+	"bytes"
+	"strings"
+)
+
+// xmlNode is a small ordered element tree for the metadata-standard renderers (EBUCore, PBCore),
+// which need nesting and attributes that the flat MediaInfo XML writer does not.
+type xmlNode struct {
+	name     string
+	attrs    []xmlAttr
+	text     string
+	children []*xmlNode
+}
+
+type xmlAttr struct {
+	name  string
+	value string
+}
+
+func newXMLNode(name string, attrs ...xmlAttr) *xmlNode {
+	return &xmlNode{name: name, attrs: attrs}
+}
+
+// attr adds an attribute unless value is empty.
+func (n *xmlNode) attr(name, value string) *xmlNode {
+	if value != "" {
+		n.attrs = append(n.attrs, xmlAttr{name: name, value: value})
+	}
+	return n
+}
+
+func (n *xmlNode) add(child *xmlNode) *xmlNode {
+	n.children = append(n.children, child)
+	return child
+}
+
+// addText adds a text-only child unless value is empty; it returns nil when nothing was added.
+func (n *xmlNode) addText(name, value string, attrs ...xmlAttr) *xmlNode {
+	if value == "" {
+		return nil
+	}
+	child := &xmlNode{name: name, attrs: attrs, text: value}
+	n.children = append(n.children, child)
+	return child
+}
+
+func (n *xmlNode) writeXML(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("    ", depth)
+	buf.WriteString(indent)
+	buf.WriteString("<")
+	buf.WriteString(n.name)
+	for _, attr := range n.attrs {
+		buf.WriteString(" ")
+		buf.WriteString(attr.name)
+		buf.WriteString("=\"")
+		buf.WriteString(xmlEscapeAttr(attr.value))
+		buf.WriteString("\"")
+	}
+	switch {
+	case len(n.children) > 0:
+		buf.WriteString(">\n")
+		for _, child := range n.children {
+			child.writeXML(buf, depth+1)
+		}
+		buf.WriteString(indent)
+		buf.WriteString("</" + n.name + ">\n")
+	case n.text != "":
+		buf.WriteString(">")
+		buf.WriteString(xmlEscape(n.text))
+		buf.WriteString("</" + n.name + ">\n")
+	default:
+		buf.WriteString("/>\n")
+	}
+}
+
+// writeJSON serializes the node's content using the usual XML-to-JSON mapping: attributes become
+// "@name", text becomes "#value", and children are grouped by element name into arrays.
+func (n *xmlNode) writeJSON(buf *bytes.Buffer, depth int) {
+	indent := strings.Repeat("  ", depth+1)
+	buf.WriteString("{")
+	first := true
+	next := func(key string) {
+		if !first {
+			buf.WriteString(",")
+		}
+		first = false
+		buf.WriteString("\n")
+		buf.WriteString(indent)
+		buf.WriteString(renderJSONString(key))
+		buf.WriteString(": ")
+	}
+	for _, attr := range n.attrs {
+		next("@" + attr.name)
+		buf.WriteString(renderJSONString(attr.value))
+	}
+	if n.text != "" {
+		next("#value")
+		buf.WriteString(renderJSONString(n.text))
+	}
+	seen := map[string]bool{}
+	for _, child := range n.children {
+		if seen[child.name] {
+			continue
+		}
+		seen[child.name] = true
+		next(child.name)
+		buf.WriteString("[")
+		count := 0
+		for _, sibling := range n.children {
+			if sibling.name != child.name {
+				continue
+			}
+			if count > 0 {
+				buf.WriteString(", ")
+			}
+			sibling.writeJSON(buf, depth+1)
+			count++
+		}
+		buf.WriteString("]")
+	}
+	if !first {
+		buf.WriteString("\n")
+		buf.WriteString(strings.Repeat("  ", depth))
+	}
+	buf.WriteString("}")
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"time"
+)
+
+func (s *Server) handleMetrics(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	s.writeMetrics(w)
+}
+
+// writeMetrics emits the counters in the Prometheus text format.
+func (s *Server) writeMetrics(w io.Writer) {
+	s.metrics.mu.Lock()
+	codes := make([]int, 0, len(s.metrics.requestsByCode))
+	for code := range s.metrics.requestsByCode {
+		codes = append(codes, code)
+	}
+	slices.Sort(codes)
+	fmt.Fprintln(w, "# HELP mediainfo_http_requests_total HTTP requests served, by status code.")
+	fmt.Fprintln(w, "# TYPE mediainfo_http_requests_total counter")
+	for _, code := range codes {
+		fmt.Fprintf(w, "mediainfo_http_requests_total{code=\"%d\"} %d\n", code, s.metrics.requestsByCode[code])
+	}
+	s.metrics.mu.Unlock()
+
+	fmt.Fprintln(w, "# HELP mediainfo_analyses_in_flight Analyses currently running.")
+	fmt.Fprintln(w, "# TYPE mediainfo_analyses_in_flight gauge")
+	fmt.Fprintf(w, "mediainfo_analyses_in_flight %d\n", s.metrics.inFlight.Load())
+	fmt.Fprintln(w, "# HELP mediainfo_analyses_max_concurrent Configured analysis concurrency limit.")
+	fmt.Fprintln(w, "# TYPE mediainfo_analyses_max_concurrent gauge")
+	fmt.Fprintf(w, "mediainfo_analyses_max_concurrent %d\n", cap(s.slots))
+	fmt.Fprintln(w, "# HELP mediainfo_analyses_busy_total Requests that timed out waiting for a free slot.")
+	fmt.Fprintln(w, "# TYPE mediainfo_analyses_busy_total counter")
+	fmt.Fprintf(w, "mediainfo_analyses_busy_total %d\n", s.metrics.rejectedTimeout.Load())
+	fmt.Fprintln(w, "# HELP mediainfo_analysis_duration_seconds Time spent analyzing.")
+	fmt.Fprintln(w, "# TYPE mediainfo_analysis_duration_seconds summary")
+	fmt.Fprintf(w, "mediainfo_analysis_duration_seconds_sum %g\n", time.Duration(s.metrics.analyzeNanos.Load()).Seconds())
+	fmt.Fprintf(w, "mediainfo_analysis_duration_seconds_count %d\n", s.metrics.analyzeCount.Load())
+	fmt.Fprintln(w, "# HELP mediainfo_files_analyzed_total Media files reported.")
+	fmt.Fprintln(w, "# TYPE mediainfo_files_analyzed_total counter")
+	fmt.Fprintf(w, "mediainfo_files_analyzed_total %d\n", s.metrics.filesAnalyzed.Load())
+	fmt.Fprintln(w, "# HELP mediainfo_upload_bytes_total Bytes received in uploads.")
+	fmt.Fprintln(w, "# TYPE mediainfo_upload_bytes_total counter")
+	fmt.Fprintf(w, "mediainfo_upload_bytes_total %d\n", s.metrics.uploadBytes.Load())
+}
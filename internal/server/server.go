@@ -0,0 +1,348 @@
+// Package server exposes media analysis over HTTP for the `mediainfo serve` subcommand.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// Defaults applied by New to zero Config fields.
+const (
+	DefaultTimeout        = 60 * time.Second
+	DefaultMaxUploadBytes = 1 << 30
+)
+
+// Config controls what the server may read and how much work it accepts.
+type Config struct {
+	// AllowedRoots are the directories path requests may resolve into (after symlinks).
+	// Path analysis is refused when empty; uploads are always accepted.
+	AllowedRoots []string
+	// MaxConcurrent caps simultaneous analyses; requests wait for a slot until their timeout.
+	// Defaults to runtime.NumCPU().
+	MaxConcurrent int
+	// Timeout bounds each request, including the wait for a slot.
+	Timeout time.Duration
+	// MaxUploadBytes caps uploaded bodies.
+	MaxUploadBytes int64
+	// Scan controls how directories named in path requests are expanded.
+	Scan mediainfo.ScanOptions
+	// Analyze is passed to every analysis.
+	Analyze mediainfo.AnalyzeOptions
+	// TempDir holds uploads while they are analyzed; "" uses os.TempDir().
+	TempDir string
+}
+
+// Server is an http.Handler serving:
+//
+//	POST /analyze   {"path": "...", "output": "JSON"}, or a raw/multipart upload with ?output=&name=
+//	GET  /healthz   liveness probe
+//	GET  /metrics   Prometheus text exposition
+type Server struct {
+	config  Config
+	roots   []string
+	slots   chan struct{}
+	mux     *http.ServeMux
+	metrics metrics
+}
+
+type metrics struct {
+	inFlight        atomic.Int64
+	filesAnalyzed   atomic.Int64
+	uploadBytes     atomic.Int64
+	analyzeNanos    atomic.Int64
+	analyzeCount    atomic.Int64
+	mu              sync.Mutex
+	requestsByCode  map[int]int64
+	rejectedTimeout atomic.Int64
+}
+
+// analyzeRequest is the JSON body of a path request.
+type analyzeRequest struct {
+	Path   string   `json:"path"`
+	Paths  []string `json:"paths"`
+	Output string   `json:"output"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// New validates cfg and returns a ready handler.
+func New(cfg Config) (*Server, error) {
+	if cfg.MaxConcurrent <= 0 {
+		cfg.MaxConcurrent = runtime.NumCPU()
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.MaxUploadBytes <= 0 {
+		cfg.MaxUploadBytes = DefaultMaxUploadBytes
+	}
+	s := &Server{
+		config: cfg,
+		slots:  make(chan struct{}, cfg.MaxConcurrent),
+		mux:    http.NewServeMux(),
+	}
+	s.metrics.requestsByCode = map[int]int64{}
+	for _, root := range cfg.AllowedRoots {
+		resolved, err := resolvePath(root)
+		if err != nil {
+			return nil, fmt.Errorf("allowed root %s: %w", root, err)
+		}
+		s.roots = append(s.roots, resolved)
+	}
+	s.mux.HandleFunc("POST /analyze", s.handleAnalyze)
+	s.mux.HandleFunc("GET /healthz", s.handleHealth)
+	s.mux.HandleFunc("GET /metrics", s.handleMetrics)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+	s.mux.ServeHTTP(recorder, r)
+	s.metrics.mu.Lock()
+	s.metrics.requestsByCode[recorder.status]++
+	s.metrics.mu.Unlock()
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	io.WriteString(w, "ok\n")
+}
+
+func (s *Server) handleAnalyze(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), s.config.Timeout)
+	defer cancel()
+
+	output := r.URL.Query().Get("output")
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+
+	var run func(context.Context) ([]mediainfo.Report, error)
+	switch mediaType {
+	case "application/json":
+		var req analyzeRequest
+		if err := json.NewDecoder(io.LimitReader(r.Body, 1<<20)).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid request: %w", err))
+			return
+		}
+		if req.Output != "" {
+			output = req.Output
+		}
+		paths := req.Paths
+		if req.Path != "" {
+			paths = append([]string{req.Path}, paths...)
+		}
+		if len(paths) == 0 {
+			writeError(w, http.StatusBadRequest, errors.New("path is required"))
+			return
+		}
+		for i, path := range paths {
+			allowed, err := s.allowPath(path)
+			if err != nil {
+				writeError(w, http.StatusForbidden, err)
+				return
+			}
+			paths[i] = allowed
+		}
+		run = func(ctx context.Context) ([]mediainfo.Report, error) {
+			// Directory scans follow symlinks, so every file they turn up is checked again.
+			expanded, err := mediainfo.ExpandPaths(paths, s.config.Scan)
+			if err != nil {
+				return nil, err
+			}
+			for i, path := range expanded {
+				if expanded[i], err = s.allowPath(path); err != nil {
+					return nil, err
+				}
+			}
+			reports, _, err := mediainfo.AnalyzeFilesContext(ctx, expanded, mediainfo.ScanOptions{}, s.config.Analyze)
+			return reports, err
+		}
+	default:
+		name := r.URL.Query().Get("name")
+		body := io.Reader(http.MaxBytesReader(w, r.Body, s.config.MaxUploadBytes))
+		if mediaType == "multipart/form-data" {
+			part, filename, err := uploadPart(r)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			defer part.Close()
+			body = io.LimitReader(part, s.config.MaxUploadBytes+1)
+			if name == "" {
+				name = filename
+			}
+		}
+		if name == "" {
+			name = "upload"
+		}
+		upload, size, err := s.spool(body)
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) || errors.Is(err, errUploadTooLarge) {
+				writeError(w, http.StatusRequestEntityTooLarge, errUploadTooLarge)
+				return
+			}
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		defer os.Remove(upload.Name())
+		defer upload.Close()
+		s.metrics.uploadBytes.Add(size)
+		run = func(ctx context.Context) ([]mediainfo.Report, error) {
+			report, err := mediainfo.AnalyzeReaderContext(ctx, upload, size, filepath.Base(name), s.config.Analyze)
+			if err != nil {
+				return nil, err
+			}
+			return []mediainfo.Report{report}, nil
+		}
+	}
+	if !mediainfo.IsOutputFormat(output) && output != "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("output format not implemented: %s", output))
+		return
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+	case <-ctx.Done():
+		s.metrics.rejectedTimeout.Add(1)
+		writeError(w, http.StatusServiceUnavailable, errors.New("server busy"))
+		return
+	}
+	s.metrics.inFlight.Add(1)
+	start := time.Now()
+	reports, err := run(ctx)
+	s.metrics.analyzeNanos.Add(int64(time.Since(start)))
+	s.metrics.analyzeCount.Add(1)
+	s.metrics.inFlight.Add(-1)
+	<-s.slots
+
+	if err == nil {
+		err = ctx.Err()
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		writeError(w, http.StatusGatewayTimeout, errors.New("analysis timed out"))
+		return
+	case errors.Is(err, errPathNotAllowed):
+		writeError(w, http.StatusForbidden, err)
+		return
+	case errors.Is(err, os.ErrNotExist):
+		writeError(w, http.StatusNotFound, err)
+		return
+	case err != nil:
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	case len(reports) == 0:
+		writeError(w, http.StatusNotFound, errors.New("no media files found"))
+		return
+	}
+	s.metrics.filesAnalyzed.Add(int64(len(reports)))
+
+	rendered, err := mediainfo.RenderOutput(output, reports, nil)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	w.Header().Set("Content-Type", mediainfo.OutputContentType(output))
+	io.WriteString(w, rendered)
+}
+
+var (
+	errUploadTooLarge = errors.New("upload too large")
+	errPathNotAllowed = errors.New("path not allowed")
+)
+
+// uploadPart returns the first file part of a multipart body, falling back to the first part.
+func uploadPart(r *http.Request) (io.ReadCloser, string, error) {
+	reader, err := r.MultipartReader()
+	if err != nil {
+		return nil, "", err
+	}
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			if err == io.EOF {
+				return nil, "", errors.New("multipart upload has no file")
+			}
+			return nil, "", err
+		}
+		if part.FileName() != "" {
+			return part, part.FileName(), nil
+		}
+		part.Close()
+	}
+}
+
+// spool copies an upload to a temporary file, since the parsers need random access.
+func (s *Server) spool(body io.Reader) (*os.File, int64, error) {
+	file, err := os.CreateTemp(s.config.TempDir, "mediainfo-upload-*")
+	if err != nil {
+		return nil, 0, err
+	}
+	size, err := io.Copy(file, body)
+	if err == nil && size > s.config.MaxUploadBytes {
+		err = errUploadTooLarge
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(file.Name())
+		return nil, 0, err
+	}
+	return file, size, nil
+}
+
+// allowPath resolves path and checks it lies inside an allowed root.
+func (s *Server) allowPath(path string) (string, error) {
+	if len(s.roots) == 0 {
+		return "", errors.New("path analysis is disabled: no allowed roots configured")
+	}
+	resolved, err := resolvePath(path)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", errPathNotAllowed, path)
+	}
+	for _, root := range s.roots {
+		if resolved == root || strings.HasPrefix(resolved, root+string(filepath.Separator)) {
+			return resolved, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errPathNotAllowed, path)
+}
+
+func resolvePath(path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	return filepath.EvalSymlinks(abs)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(errorResponse{Error: err.Error()})
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -0,0 +1,250 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+const samplesDir = "../../samples"
+
+func newTestServer(t *testing.T, cfg Config) *httptest.Server {
+	t.Helper()
+	s, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ts := httptest.NewServer(s)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+func postJSON(t *testing.T, url string, body any) *http.Response {
+	t.Helper()
+	data, _ := json.Marshal(body)
+	resp, err := http.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+func readBody(t *testing.T, resp *http.Response) string {
+	t.Helper()
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(resp.Body); err != nil {
+		t.Fatalf("read body: %v", err)
+	}
+	return buf.String()
+}
+
+func TestServerHealth(t *testing.T) {
+	ts := newTestServer(t, Config{})
+	resp, err := http.Get(ts.URL + "/healthz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || readBody(t, resp) != "ok\n" {
+		t.Fatalf("healthz status=%d", resp.StatusCode)
+	}
+}
+
+func TestServerAnalyzePath(t *testing.T) {
+	ts := newTestServer(t, Config{AllowedRoots: []string{samplesDir}})
+	path := filepath.Join(samplesDir, "sample.mp4")
+
+	resp := postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: path, Output: "JSON"})
+	body := readBody(t, resp)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status=%d body=%s", resp.StatusCode, body)
+	}
+	if got := resp.Header.Get("Content-Type"); got != "application/json" {
+		t.Fatalf("Content-Type=%q, want %q", got, "application/json")
+	}
+	report, err := mediainfo.AnalyzeFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resolved, _ := resolvePath(path)
+	report.Ref = resolved
+	if want := mediainfo.RenderJSON([]mediainfo.Report{report}); body != want {
+		t.Fatalf("JSON output differs from RenderJSON:\n%s\nwant:\n%s", body, want)
+	}
+
+	resp = postJSON(t, ts.URL+"/analyze?output=XML", analyzeRequest{Path: path})
+	if body := readBody(t, resp); resp.StatusCode != http.StatusOK || !strings.Contains(body, "<MediaInfo") {
+		t.Fatalf("XML status=%d body=%.200s", resp.StatusCode, body)
+	}
+
+	resp = postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: path})
+	if body := readBody(t, resp); !strings.HasPrefix(body, "General\n") {
+		t.Fatalf("default output is not TEXT: %.200s", body)
+	}
+}
+
+func TestServerRejectsPaths(t *testing.T) {
+	outside := t.TempDir()
+	link := filepath.Join(t.TempDir(), "escape")
+	if err := os.Symlink(outside, link); err != nil {
+		t.Skip("symlinks unavailable:", err)
+	}
+	root := filepath.Dir(link)
+	ts := newTestServer(t, Config{AllowedRoots: []string{root}})
+
+	for _, path := range []string{outside, link, filepath.Join(root, "..", filepath.Base(outside)), "/etc/passwd"} {
+		resp := postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: path})
+		if resp.StatusCode != http.StatusForbidden {
+			t.Fatalf("%s: status=%d, want %d", path, resp.StatusCode, http.StatusForbidden)
+		}
+	}
+
+	disabled := newTestServer(t, Config{})
+	resp := postJSON(t, disabled.URL+"/analyze", analyzeRequest{Path: filepath.Join(samplesDir, "sample.mp4")})
+	if resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("no roots: status=%d, want %d", resp.StatusCode, http.StatusForbidden)
+	}
+}
+
+func TestServerRejectsSymlinksOutOfRoot(t *testing.T) {
+	outside := t.TempDir()
+	data, err := os.ReadFile(filepath.Join(samplesDir, "sample.mp4"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(outside, "secret.mp4"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "inside.mp4"), data, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(filepath.Join(outside, "secret.mp4"), filepath.Join(root, "file.mp4")); err != nil {
+		t.Skip("symlinks unavailable:", err)
+	}
+	if err := os.Symlink(outside, filepath.Join(root, "dir")); err != nil {
+		t.Fatal(err)
+	}
+	ts := newTestServer(t, Config{AllowedRoots: []string{root}, Scan: mediainfo.ScanOptions{Recursive: true}})
+
+	resp := postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: root, Output: "JSON"})
+	if body := readBody(t, resp); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("status=%d, want %d: %.200s", resp.StatusCode, http.StatusForbidden, body)
+	}
+	if err := os.Remove(filepath.Join(root, "file.mp4")); err != nil {
+		t.Fatal(err)
+	}
+	resp = postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: root, Output: "JSON"})
+	if body := readBody(t, resp); resp.StatusCode != http.StatusForbidden {
+		t.Fatalf("directory link: status=%d, want %d: %.200s", resp.StatusCode, http.StatusForbidden, body)
+	}
+	if err := os.Remove(filepath.Join(root, "dir")); err != nil {
+		t.Fatal(err)
+	}
+	resp = postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: root, Output: "JSON"})
+	if body := readBody(t, resp); resp.StatusCode != http.StatusOK || strings.Contains(body, "secret") {
+		t.Fatalf("status=%d body=%.200s", resp.StatusCode, body)
+	}
+}
+
+func TestServerAnalyzeUpload(t *testing.T) {
+	ts := newTestServer(t, Config{})
+	data, err := os.ReadFile(filepath.Join(samplesDir, "sample.flac"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := http.Post(ts.URL+"/analyze?output=JSON&name=song.flac", "application/octet-stream", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body := readBody(t, resp)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"Format":"FLAC"`) || !strings.Contains(body, `"@ref":"song.flac"`) {
+		t.Fatalf("raw upload status=%d body=%.300s", resp.StatusCode, body)
+	}
+
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	part, _ := writer.CreateFormFile("file", "track.flac")
+	part.Write(data)
+	writer.Close()
+	resp, err = http.Post(ts.URL+"/analyze?output=JSON", writer.FormDataContentType(), &form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body = readBody(t, resp)
+	if resp.StatusCode != http.StatusOK || !strings.Contains(body, `"@ref":"track.flac"`) {
+		t.Fatalf("multipart upload status=%d body=%.300s", resp.StatusCode, body)
+	}
+
+	small := newTestServer(t, Config{MaxUploadBytes: 1024})
+	resp, err = http.Post(small.URL+"/analyze", "application/octet-stream", bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("oversized upload status=%d, want %d", resp.StatusCode, http.StatusRequestEntityTooLarge)
+	}
+}
+
+func TestServerBadOutput(t *testing.T) {
+	ts := newTestServer(t, Config{AllowedRoots: []string{samplesDir}})
+	resp := postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: filepath.Join(samplesDir, "sample.mp4"), Output: "YAML"})
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Fatalf("status=%d, want %d", resp.StatusCode, http.StatusBadRequest)
+	}
+}
+
+func TestServerBusy(t *testing.T) {
+	s, err := New(Config{AllowedRoots: []string{samplesDir}, MaxConcurrent: 1, Timeout: 50 * time.Millisecond})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	s.slots <- struct{}{} // occupy the only slot
+	resp := postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: filepath.Join(samplesDir, "sample.mp4")})
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("status=%d, want %d", resp.StatusCode, http.StatusServiceUnavailable)
+	}
+	<-s.slots
+
+	resp = postJSON(t, ts.URL+"/analyze", analyzeRequest{Path: filepath.Join(samplesDir, "sample.mp4")})
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status=%d after slot was freed", resp.StatusCode)
+	}
+
+	metrics, err := http.Get(ts.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer metrics.Body.Close()
+	body := readBody(t, metrics)
+	for _, want := range []string{
+		`mediainfo_http_requests_total{code="200"} 1`,
+		`mediainfo_http_requests_total{code="503"} 1`,
+		"mediainfo_analyses_busy_total 1",
+		"mediainfo_analyses_in_flight 0",
+		"mediainfo_analyses_max_concurrent 1",
+		"mediainfo_analysis_duration_seconds_count 1",
+		"mediainfo_files_analyzed_total 1",
+	} {
+		if !strings.Contains(body, want) {
+			t.Fatalf("metrics missing %q:\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,374 @@
+// Package watch polls a directory for finished media files, analyzes them, saves the reports
+// and notifies a webhook, for the `mediainfo watch` subcommand.
+package watch
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// Defaults applied by New to zero Config fields.
+const (
+	DefaultStableFor      = 5 * time.Second
+	DefaultInterval       = time.Second
+	DefaultWebhookRetries = 3
+	DefaultWebhookBackoff = time.Second
+)
+
+// Reports are saved as <media>.mediainfo.<ext>, written through reportTempPrefix files; both
+// are ignored when scanning so the watcher never analyzes its own output.
+const (
+	reportSuffix     = ".mediainfo"
+	reportTempPrefix = ".mediainfo-report-"
+)
+
+// Config controls a Watcher.
+type Config struct {
+	// Dir is the directory to watch.
+	Dir string
+	// Scan selects which files under Dir are considered (recursion, name globs).
+	Scan mediainfo.ScanOptions
+	// Formats keeps only files whose DetectFormat result is listed (case-insensitive); empty
+	// accepts any recognized format. Unrecognized files are always skipped.
+	Formats []string
+	// StableFor is how long size and modification time must stay unchanged before analysis.
+	StableFor time.Duration
+	// Interval is the polling period.
+	Interval time.Duration
+	// Existing also processes files already present when watching starts.
+	Existing bool
+	// Output is the report format (an --Output name); "" writes JSON.
+	Output string
+	// OutputDir receives reports, mirroring the layout under Dir; "" writes them next to the media.
+	OutputDir string
+	// NoReports disables writing report files.
+	NoReports bool
+	// WebhookURL receives each JSON report as a POST; "" disables notifications.
+	WebhookURL string
+	// WebhookRetries is how many times a failed delivery is retried; 0 selects the default and a
+	// negative value disables retries.
+	WebhookRetries int
+	// WebhookBackoff is the delay before the first retry; it doubles after each attempt.
+	WebhookBackoff time.Duration
+	// Client sends webhook requests; nil uses a client with a 30 second timeout.
+	Client *http.Client
+	// Analyze is passed to every analysis.
+	Analyze mediainfo.AnalyzeOptions
+	// Logf receives progress messages; nil discards them.
+	Logf func(format string, args ...any)
+}
+
+// Result describes one processed file.
+type Result struct {
+	Path      string
+	Format    string
+	Report    string // saved report path, "" when none was written
+	Delivered bool   // webhook accepted the report
+	Skipped   bool   // format filter rejected the file
+	Err       error
+}
+
+// Watcher tracks files between polls.
+type Watcher struct {
+	config Config
+	files  map[string]*fileState
+	primed bool
+	now    func() time.Time
+	sleep  func(context.Context, time.Duration) error
+}
+
+type fileState struct {
+	size    int64
+	modTime time.Time
+	since   time.Time
+	done    bool
+}
+
+// New validates cfg and returns a Watcher.
+func New(cfg Config) (*Watcher, error) {
+	info, err := os.Stat(cfg.Dir)
+	if err != nil {
+		return nil, err
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("not a directory: %s", cfg.Dir)
+	}
+	if cfg.Output == "" {
+		cfg.Output = "JSON"
+	}
+	if !mediainfo.IsOutputFormat(cfg.Output) {
+		return nil, fmt.Errorf("output format not implemented: %s", cfg.Output)
+	}
+	if cfg.StableFor <= 0 {
+		cfg.StableFor = DefaultStableFor
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.WebhookRetries < 0 {
+		cfg.WebhookRetries = 0
+	} else if cfg.WebhookRetries == 0 {
+		cfg.WebhookRetries = DefaultWebhookRetries
+	}
+	if cfg.WebhookBackoff <= 0 {
+		cfg.WebhookBackoff = DefaultWebhookBackoff
+	}
+	if cfg.Client == nil {
+		cfg.Client = &http.Client{Timeout: 30 * time.Second}
+	}
+	if cfg.Logf == nil {
+		cfg.Logf = func(string, ...any) {}
+	}
+	cfg.Scan.SkipUnknown = false // the format filter below sniffs each file once, when it is stable
+	if cfg.Scan.OnError == nil {
+		logf := cfg.Logf
+		cfg.Scan.OnError = func(path string, err error) { logf("scan %s: %v", path, err) }
+	}
+	return &Watcher{
+		config: cfg,
+		files:  map[string]*fileState{},
+		now:    time.Now,
+		sleep:  sleepContext,
+	}, nil
+}
+
+// Run polls until ctx is cancelled.
+func (w *Watcher) Run(ctx context.Context) error {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := w.Poll(ctx); err != nil {
+			w.config.Logf("scan %s: %v", w.config.Dir, err)
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll scans the directory once and processes every file that has become stable.
+func (w *Watcher) Poll(ctx context.Context) ([]Result, error) {
+	paths, err := mediainfo.ExpandPaths([]string{w.config.Dir}, w.config.Scan)
+	if err != nil {
+		return nil, err
+	}
+	now := w.now()
+	present := make(map[string]bool, len(paths))
+	var results []Result
+	for _, path := range paths {
+		if w.isReport(path) {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		present[path] = true
+		state, known := w.files[path]
+		if !known || state.size != info.Size() || !state.modTime.Equal(info.ModTime()) {
+			// Files found on the first scan count as already handled unless Existing is set.
+			done := !w.primed && !w.config.Existing
+			w.files[path] = &fileState{size: info.Size(), modTime: info.ModTime(), since: now, done: done}
+			continue
+		}
+		if state.done || now.Sub(state.since) < w.config.StableFor {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		state.done = true
+		results = append(results, w.process(ctx, path))
+	}
+	for path := range w.files {
+		if !present[path] {
+			delete(w.files, path)
+		}
+	}
+	w.primed = true
+	return results, nil
+}
+
+func (w *Watcher) isReport(path string) bool {
+	if w.config.OutputDir != "" {
+		if rel, err := filepath.Rel(w.config.OutputDir, path); err == nil && !strings.HasPrefix(rel, "..") {
+			return true
+		}
+	}
+	if strings.HasPrefix(filepath.Base(path), reportTempPrefix) {
+		return true
+	}
+	ext := filepath.Ext(path)
+	if !strings.HasSuffix(strings.TrimSuffix(path, ext), reportSuffix) {
+		return false
+	}
+	return slices.ContainsFunc(mediainfo.OutputFormats, func(format string) bool {
+		return mediainfo.OutputExtension(format) == ext
+	})
+}
+
+func (w *Watcher) process(ctx context.Context, path string) Result {
+	result := Result{Path: path}
+	format, err := mediainfo.DetectFileFormat(path)
+	if err != nil {
+		result.Err = err
+		w.config.Logf("%s: %v", path, err)
+		return result
+	}
+	result.Format = format
+	if !w.acceptFormat(format) {
+		result.Skipped = true
+		w.config.Logf("%s: skipped (%s)", path, format)
+		return result
+	}
+
+	report, err := mediainfo.AnalyzeFileContext(ctx, path, w.config.Analyze)
+	if err != nil {
+		result.Err = err
+		w.config.Logf("%s: %v", path, err)
+		return result
+	}
+	reports := []mediainfo.Report{report}
+
+	if !w.config.NoReports {
+		rendered, err := mediainfo.RenderOutput(w.config.Output, reports, nil)
+		if err == nil {
+			result.Report = w.reportPath(path)
+			err = writeFileAtomic(result.Report, []byte(rendered))
+		}
+		if err != nil {
+			result.Report = ""
+			result.Err = err
+			w.config.Logf("%s: write report: %v", path, err)
+		} else {
+			w.config.Logf("%s: report written to %s", path, result.Report)
+		}
+	}
+
+	if w.config.WebhookURL != "" {
+		if err := w.notify(ctx, path, []byte(mediainfo.RenderJSON(reports))); err != nil {
+			result.Err = errors.Join(result.Err, err)
+			w.config.Logf("%s: webhook: %v", path, err)
+		} else {
+			result.Delivered = true
+			w.config.Logf("%s: webhook delivered", path)
+		}
+	}
+	return result
+}
+
+func (w *Watcher) acceptFormat(format string) bool {
+	if format == "Unknown" {
+		return false
+	}
+	if len(w.config.Formats) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(w.config.Formats, func(name string) bool {
+		return strings.EqualFold(strings.TrimSpace(name), format)
+	})
+}
+
+// reportPath places the report next to the media, or at the same relative path under OutputDir.
+func (w *Watcher) reportPath(path string) string {
+	name := path + reportSuffix + mediainfo.OutputExtension(w.config.Output)
+	if w.config.OutputDir == "" {
+		return name
+	}
+	rel, err := filepath.Rel(w.config.Dir, name)
+	if err != nil {
+		rel = filepath.Base(name)
+	}
+	return filepath.Join(w.config.OutputDir, rel)
+}
+
+// notify POSTs the JSON report, retrying network errors, 429 and 5xx responses with backoff.
+func (w *Watcher) notify(ctx context.Context, path string, body []byte) error {
+	backoff := w.config.WebhookBackoff
+	var lastErr error
+	for attempt := 0; attempt <= w.config.WebhookRetries; attempt++ {
+		if attempt > 0 {
+			if err := w.sleep(ctx, backoff); err != nil {
+				return err
+			}
+			backoff *= 2
+		}
+		retry, err := w.post(ctx, path, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	return lastErr
+}
+
+func (w *Watcher) post(ctx context.Context, path string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.config.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Mediainfo-Path", path)
+	resp, err := w.config.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500
+	return retry, fmt.Errorf("webhook returned %s", resp.Status)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), reportTempPrefix+"*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,204 @@
+package watch
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/autobrr/go-mediainfo/internal/mediainfo"
+)
+
+// testClock drives Poll without waiting for real time to pass.
+type testClock struct{ t time.Time }
+
+func (c *testClock) now() time.Time          { return c.t }
+func (c *testClock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTestWatcher(t *testing.T, cfg Config) (*Watcher, *testClock) {
+	t.Helper()
+	w, err := New(cfg)
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	clock := &testClock{t: time.Unix(1_700_000_000, 0)}
+	w.now = clock.now
+	w.sleep = func(context.Context, time.Duration) error { return nil }
+	return w, clock
+}
+
+func copySample(t *testing.T, name, dst string) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("../../samples", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestWatchWaitsForStableFiles(t *testing.T) {
+	dir := t.TempDir()
+	copySample(t, "sample.wav", filepath.Join(dir, "old.wav"))
+	w, clock := newTestWatcher(t, Config{Dir: dir, StableFor: 5 * time.Second})
+	ctx := context.Background()
+
+	if results, _ := w.Poll(ctx); len(results) != 0 {
+		t.Fatalf("first poll processed %d files", len(results))
+	}
+
+	path := filepath.Join(dir, "new.mp3")
+	copySample(t, "sample.mp3", path)
+	if results, _ := w.Poll(ctx); len(results) != 0 {
+		t.Fatalf("new file processed before it was stable")
+	}
+	clock.advance(3 * time.Second)
+	if results, _ := w.Poll(ctx); len(results) != 0 {
+		t.Fatalf("file processed after 3s, StableFor is 5s")
+	}
+
+	// Growing again restarts the stability window.
+	f, _ := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	f.Write(make([]byte, 16))
+	f.Close()
+	clock.advance(3 * time.Second)
+	if results, _ := w.Poll(ctx); len(results) != 0 {
+		t.Fatalf("file processed right after it changed")
+	}
+	clock.advance(5 * time.Second)
+	results, err := w.Poll(ctx)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("results=%v err=%v, want one", results, err)
+	}
+	got := results[0]
+	if got.Err != nil || got.Format != "MPEG Audio" || got.Report != path+".mediainfo.json" {
+		t.Fatalf("result=%+v", got)
+	}
+	data, err := os.ReadFile(got.Report)
+	if err != nil || !json.Valid(data) || !strings.Contains(string(data), `"Format":"MPEG Audio"`) {
+		t.Fatalf("report %s: err=%v %.200s", got.Report, err, data)
+	}
+
+	clock.advance(time.Minute)
+	if results, _ := w.Poll(ctx); len(results) != 0 {
+		t.Fatalf("processed again: %+v", results)
+	}
+}
+
+func TestWatchFormatsAndOutputDir(t *testing.T) {
+	dir := t.TempDir()
+	out := t.TempDir()
+	w, clock := newTestWatcher(t, Config{
+		Dir: dir, Formats: []string{"flac"}, OutputDir: out, Output: "XML", Existing: true,
+		Scan: mediainfo.ScanOptions{Recursive: true},
+	})
+	os.Mkdir(filepath.Join(dir, "album"), 0o755)
+	copySample(t, "sample.flac", filepath.Join(dir, "album", "a.flac"))
+	copySample(t, "sample.mp3", filepath.Join(dir, "b.mp3"))
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("not media"), 0o644)
+
+	w.Poll(context.Background())
+	clock.advance(10 * time.Second)
+	results, err := w.Poll(context.Background())
+	if err != nil || len(results) != 3 {
+		t.Fatalf("results=%+v err=%v", results, err)
+	}
+	reports := 0
+	for _, result := range results {
+		switch filepath.Base(result.Path) {
+		case "a.flac":
+			want := filepath.Join(out, "album", "a.flac.mediainfo.xml")
+			if result.Skipped || result.Report != want {
+				t.Fatalf("flac result=%+v, want report %s", result, want)
+			}
+			if _, err := os.Stat(want); err != nil {
+				t.Fatal(err)
+			}
+			reports++
+		default:
+			if !result.Skipped {
+				t.Fatalf("%s not skipped: %+v", result.Path, result)
+			}
+		}
+	}
+	if reports != 1 {
+		t.Fatalf("reports=%d, want 1", reports)
+	}
+}
+
+func TestWatchWebhookRetries(t *testing.T) {
+	var attempts atomic.Int32
+	var body []byte
+	var header string
+	hook := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if attempts.Add(1) < 3 {
+			rw.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		body, _ = io.ReadAll(r.Body)
+		header = r.Header.Get("X-Mediainfo-Path")
+	}))
+	defer hook.Close()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "song.flac")
+	copySample(t, "sample.flac", path)
+	w, clock := newTestWatcher(t, Config{Dir: dir, Existing: true, NoReports: true, WebhookURL: hook.URL})
+	w.Poll(context.Background())
+	clock.advance(time.Minute)
+	results, _ := w.Poll(context.Background())
+	if len(results) != 1 || !results[0].Delivered || results[0].Err != nil {
+		t.Fatalf("results=%+v", results)
+	}
+	if attempts.Load() != 3 || header != path || !json.Valid(body) {
+		t.Fatalf("attempts=%d header=%q body=%.100s", attempts.Load(), header, body)
+	}
+	if _, err := os.Stat(path + ".mediainfo.json"); !os.IsNotExist(err) {
+		t.Fatalf("report written despite NoReports")
+	}
+
+	attempts.Store(-100)
+	w.config.WebhookRetries = 2
+	os.WriteFile(path, append(mustRead(t, path), 0), 0o644)
+	w.Poll(context.Background())
+	clock.advance(time.Minute)
+	results, _ = w.Poll(context.Background())
+	if len(results) != 1 || results[0].Delivered || results[0].Err == nil {
+		t.Fatalf("results=%+v, want a delivery error", results)
+	}
+	if got := attempts.Load(); got != -97 {
+		t.Fatalf("attempts=%d, want 3 (one try plus two retries)", got+100)
+	}
+}
+
+func TestWatchIgnoresOwnReports(t *testing.T) {
+	w, _ := newTestWatcher(t, Config{Dir: t.TempDir()})
+	for path, want := range map[string]bool{
+		"/x/a.mkv.mediainfo.json":     true,
+		"/x/a.mkv.mediainfo.txt":      true,
+		"/x/.mediainfo-report-123456": true,
+		"/x/a.mediainfo.mkv":          false,
+		"/x/a.mkv":                    false,
+	} {
+		if got := w.isReport(path); got != want {
+			t.Fatalf("isReport(%q)=%v, want %v", path, got, want)
+		}
+	}
+}
+
+func mustRead(t *testing.T, path string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
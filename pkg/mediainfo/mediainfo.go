@@ -1,6 +1,9 @@
 package mediainfo
 
 import (
+	"context"
+	"io"
+
 	"github.com/autobrr/go-mediainfo/internal/mediainfo"
 )
 
@@ -10,6 +13,27 @@ type Field = mediainfo.Field
 type Stream = mediainfo.Stream
 type Report = mediainfo.Report
 type AnalyzeOptions = mediainfo.AnalyzeOptions
+type BatchResult = mediainfo.BatchResult
+type ScanOptions = mediainfo.ScanOptions
+type InformTemplate = mediainfo.InformTemplate
+type Translation = mediainfo.Translation
+type FieldInfo = mediainfo.FieldInfo
+type Tracks = mediainfo.Tracks
+type GeneralTrack = mediainfo.GeneralTrack
+type VideoTrack = mediainfo.VideoTrack
+type AudioTrack = mediainfo.AudioTrack
+type TextTrack = mediainfo.TextTrack
+type Rational = mediainfo.Rational
+type DiffOptions = mediainfo.DiffOptions
+type DiffChange = mediainfo.DiffChange
+type FieldDiff = mediainfo.FieldDiff
+type ReportDiff = mediainfo.ReportDiff
+type ReportCache = mediainfo.ReportCache
+type CacheStats = mediainfo.CacheStats
+type HTTPReader = mediainfo.HTTPReader
+type HTTPReaderOptions = mediainfo.HTTPReaderOptions
+type RARArchive = mediainfo.RARArchive
+type RARFile = mediainfo.RARFile
 
 // Constants
 const (
@@ -17,8 +41,13 @@ const (
 	StreamVideo   = mediainfo.StreamVideo
 	StreamAudio   = mediainfo.StreamAudio
 	StreamText    = mediainfo.StreamText
+	StreamOther   = mediainfo.StreamOther
 	StreamImage   = mediainfo.StreamImage
 	StreamMenu    = mediainfo.StreamMenu
+
+	DiffMissing = mediainfo.DiffMissing
+	DiffExtra   = mediainfo.DiffExtra
+	DiffChanged = mediainfo.DiffChanged
 )
 
 // Functions
@@ -30,15 +59,79 @@ func AnalyzeFileWithOptions(path string, opts AnalyzeOptions) (Report, error) {
 	return mediainfo.AnalyzeFileWithOptions(path, opts)
 }
 
+func AnalyzeFileContext(ctx context.Context, path string, opts AnalyzeOptions) (Report, error) {
+	return mediainfo.AnalyzeFileContext(ctx, path, opts)
+}
+
+func AnalyzeReader(r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	return mediainfo.AnalyzeReader(r, size, name, opts)
+}
+
+func AnalyzeReaderContext(ctx context.Context, r io.ReaderAt, size int64, name string, opts AnalyzeOptions) (Report, error) {
+	return mediainfo.AnalyzeReaderContext(ctx, r, size, name, opts)
+}
+
 func AnalyzeFilesWithOptions(paths []string, opts AnalyzeOptions) ([]Report, int, error) {
 	return mediainfo.AnalyzeFilesWithOptions(paths, opts)
 }
 
+func AnalyzeFilesContext(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions) ([]Report, int, error) {
+	return mediainfo.AnalyzeFilesContext(ctx, paths, scan, opts)
+}
+
+func ExpandPaths(paths []string, opts ScanOptions) ([]string, error) {
+	return mediainfo.ExpandPaths(paths, opts)
+}
+
+func AnalyzeBatch(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions, workers int) []BatchResult {
+	return mediainfo.AnalyzeBatch(ctx, paths, scan, opts, workers)
+}
+
+func AnalyzeBatchStream(ctx context.Context, paths []string, scan ScanOptions, opts AnalyzeOptions, workers int) <-chan BatchResult {
+	return mediainfo.AnalyzeBatchStream(ctx, paths, scan, opts, workers)
+}
+
+// OpenHTTPReader returns an io.ReaderAt over an http(s) URL backed by cached Range requests.
+func OpenHTTPReader(ctx context.Context, url string, opts HTTPReaderOptions) (*HTTPReader, error) {
+	return mediainfo.OpenHTTPReader(ctx, url, opts)
+}
+
+func AnalyzeURL(ctx context.Context, url string, opts AnalyzeOptions, httpOpts HTTPReaderOptions) (Report, error) {
+	return mediainfo.AnalyzeURL(ctx, url, opts, httpOpts)
+}
+
+func IsURL(path string) bool {
+	return mediainfo.IsURL(path)
+}
+
+// OpenRAR opens a RAR4/RAR5 volume set; stored entries can then be read in place with Open.
+func OpenRAR(path string) (*RARArchive, error) {
+	return mediainfo.OpenRAR(path)
+}
+
+// AnalyzeRAR analyzes a stored entry of a RAR volume set ("" picks the largest) without extracting it.
+func AnalyzeRAR(ctx context.Context, path, inner string, opts AnalyzeOptions) (Report, error) {
+	return mediainfo.AnalyzeRAR(ctx, path, inner, opts)
+}
+
+// OpenReportCache opens the on-disk report cache in dir ("" for the per-user default).
+func OpenReportCache(dir string) (*ReportCache, error) {
+	return mediainfo.OpenReportCache(dir)
+}
+
+func DefaultCacheDir() (string, error) {
+	return mediainfo.DefaultCacheDir()
+}
+
 // Rendering
 func RenderText(reports []Report) string {
 	return mediainfo.RenderText(reports)
 }
 
+func RenderTextFull(reports []Report) string {
+	return mediainfo.RenderTextFull(reports)
+}
+
 func RenderJSON(reports []Report) string {
 	return mediainfo.RenderJSON(reports)
 }
@@ -47,6 +140,19 @@ func RenderXML(reports []Report) string {
 	return mediainfo.RenderXML(reports)
 }
 
+func RenderOLDXML(reports []Report) string {
+	return mediainfo.RenderOLDXML(reports)
+}
+
+// RenderOutput renders reports in a named --Output format such as "JSON" or "GRAPH_SVG".
+func RenderOutput(format string, reports []Report, tr *Translation) (string, error) {
+	return mediainfo.RenderOutput(format, reports, tr)
+}
+
+func IsOutputFormat(name string) bool {
+	return mediainfo.IsOutputFormat(name)
+}
+
 func RenderCSV(reports []Report) string {
 	return mediainfo.RenderCSV(reports)
 }
@@ -55,14 +161,42 @@ func RenderHTML(reports []Report) string {
 	return mediainfo.RenderHTML(reports)
 }
 
+func LoadTranslation(language string) (*Translation, error) {
+	return mediainfo.LoadTranslation(language)
+}
+
+func ParseTranslation(data []byte) (*Translation, error) {
+	return mediainfo.ParseTranslation(data)
+}
+
+func RenderTextTranslated(reports []Report, tr *Translation) string {
+	return mediainfo.RenderTextTranslated(reports, tr)
+}
+
+func RenderTextFullTranslated(reports []Report, tr *Translation) string {
+	return mediainfo.RenderTextFullTranslated(reports, tr)
+}
+
+func RenderHTMLTranslated(reports []Report, tr *Translation) string {
+	return mediainfo.RenderHTMLTranslated(reports, tr)
+}
+
 func RenderEBUCore(reports []Report) string {
 	return mediainfo.RenderEBUCore(reports)
 }
 
+func RenderEBUCoreJSON(reports []Report) string {
+	return mediainfo.RenderEBUCoreJSON(reports)
+}
+
 func RenderPBCore(reports []Report) string {
 	return mediainfo.RenderPBCore(reports)
 }
 
+func RenderPBCore2(reports []Report) string {
+	return mediainfo.RenderPBCore2(reports)
+}
+
 func RenderGraphSVG(reports []Report) string {
 	return mediainfo.RenderGraphSVG(reports)
 }
@@ -71,10 +205,50 @@ func RenderGraphDOT(reports []Report) string {
 	return mediainfo.RenderGraphDOT(reports)
 }
 
+func ParseInformTemplate(text string) (InformTemplate, error) {
+	return mediainfo.ParseInformTemplate(text)
+}
+
+func RenderInform(reports []Report, tmpl InformTemplate) string {
+	return mediainfo.RenderInform(reports, tmpl)
+}
+
 func InfoParameters() string {
 	return mediainfo.InfoParameters()
 }
 
+func InfoParametersJSON() string {
+	return mediainfo.InfoParametersJSON()
+}
+
+func FieldRegistry() []FieldInfo {
+	return mediainfo.FieldRegistry()
+}
+
+func TracksJSONSchema() string {
+	return mediainfo.TracksJSONSchema()
+}
+
+func ParseJSONReport(data []byte) ([]Report, error) {
+	return mediainfo.ParseJSONReport(data)
+}
+
+func ParseXMLReport(data []byte) ([]Report, error) {
+	return mediainfo.ParseXMLReport(data)
+}
+
+func DiffReports(left, right Report, opts DiffOptions) ReportDiff {
+	return mediainfo.DiffReports(left, right, opts)
+}
+
+func RenderDiffText(d ReportDiff) string {
+	return mediainfo.RenderDiffText(d)
+}
+
+func RenderDiffJSON(d ReportDiff) string {
+	return mediainfo.RenderDiffJSON(d)
+}
+
 func FormatVersion(version string) string {
 	return mediainfo.FormatVersion(version)
 }
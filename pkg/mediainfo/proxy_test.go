@@ -11,3 +11,11 @@ func TestProxyAPI(t *testing.T) {
 	var _ mediainfo.Report
 	var _ mediainfo.StreamKind = mediainfo.StreamGeneral
 }
+
+func TestAnalyzeOptionsComparable(t *testing.T) {
+	// Callers compare options and use them as map keys; keep every field comparable.
+	seen := map[mediainfo.AnalyzeOptions]bool{{}: true}
+	if !seen[mediainfo.AnalyzeOptions{}] {
+		t.Fatal("zero options not found")
+	}
+}